@@ -30,6 +30,12 @@ const (
 	Terrain_PATH         = "/flatfile?f1c-%s-t.%d"         // 带数据库名称的terrain数据，%s是tilekey，%d是terrainEpoch
 )
 
+const (
+	ROCKTREE_BULK_METADATA_PATH          = "/rt/earth/BulkMetadata/pb=!1m2!1s%s!2u%d"           // %s 是八叉树路径，%d 是epoch
+	ROCKTREE_NODE_DATA_PATH              = "/rt/earth/NodeData/pb=!1m2!1s%s!2u%d!2e%d!4b0"      // %s 是八叉树路径，%d 是epoch，%d 是纹理格式
+	ROCKTREE_NODE_DATA_WITH_IMAGERY_PATH = "/rt/earth/NodeData/pb=!1m2!1s%s!2u%d!2e%d!3u%d!4b0" // 同上，最后的 %d 是imageryEpoch
+)
+
 /*
 这里是google earth的tilekey编号规则
 		   c0    c1
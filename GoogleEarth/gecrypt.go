@@ -85,6 +85,25 @@ func UnpackGEZlib(src []byte) ([]byte, error) {
 	return out, nil
 }
 
+// DecryptImagery 解密影像数据：
+// - 若前4字节为加密的JPEG魔法数，则解密后返回 JPEG
+// - 若已是 JPEG，直接返回原数据副本
+// - 其他情况（或解密结果不是 JPEG，通常是密钥不匹配）返回错误
+func DecryptImagery(src []byte) ([]byte, error) {
+	if len(src) < 4 {
+		return nil, fmt.Errorf("input too short")
+	}
+	out := make([]byte, len(src))
+	copy(out, src)
+	if binary.LittleEndian.Uint32(out[:4]) == CRYPTED_JPEG_MAGIC {
+		geDecrypt(out, CryptKey)
+	}
+	if out[0] != 0xFF || out[1] != 0xD8 {
+		return nil, fmt.Errorf("not a JPEG image")
+	}
+	return out, nil
+}
+
 // CryptKey 默认解密密钥（固定密钥，当 dbRoot 密钥未加载时使用）
 var CryptKey = []byte{
 	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x45, 0xF4, 0xBD, 0x0B, 0x79, 0xE2, 0x6A, 0x45,
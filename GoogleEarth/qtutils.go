@@ -677,6 +677,78 @@ func ConvertToQtNodeFromBounds(minY, minX, maxY, maxX float64, level uint, isMer
 	return names
 }
 
+// QtNodeRange 边界框在指定层级覆盖的 qtnode 网格范围（平面投影）
+// 与 ConvertToQtNodeFromBounds 不同，不一次性生成地址列表，而是按序号惰性生成，适合大范围高层级的枚举
+type QtNodeRange struct {
+	Level uint
+	MinX  uint // 最小列号（含）
+	MaxX  uint // 最大列号（含）
+	MinY  uint // 最小行号（含，行号从北向南递增）
+	MaxY  uint // 最大行号（含）
+}
+
+// NewQtNodeRangeFromBounds 计算经纬度边界框在指定层级覆盖的 qtnode 网格范围
+// minLat, minLon, maxLat, maxLon: 边界框（度）
+// level: 层级（0 为根节点 "0"）
+// 返回：网格范围；边界框或层级无效时 ok 为 false
+func NewQtNodeRangeFromBounds(minLat, minLon, maxLat, maxLon float64, level uint) (r QtNodeRange, ok bool) {
+	if level >= MAX_LEVEL || minLat > maxLat || minLon > maxLon {
+		return QtNodeRange{}, false
+	}
+	if minLat < -90 || maxLat > 90 || minLon < -180 || maxLon > 180 {
+		return QtNodeRange{}, false
+	}
+
+	// 平面投影下四叉树覆盖 [-180, 180] x [-180, 180] 的正方形，每层每个方向划分为 2^level 格
+	n := uint(1) << level
+	cell := 360.0 / float64(n)
+	clamp := func(v float64) uint {
+		if v < 0 {
+			return 0
+		}
+		if v > float64(n-1) {
+			return n - 1
+		}
+		return uint(v)
+	}
+	// 上边界恰好落在格线上时不包含下一格
+	lower := func(v float64) uint { return clamp(math.Floor(v / cell)) }
+	upper := func(v float64) uint { return clamp(math.Ceil(v/cell) - 1) }
+
+	r = QtNodeRange{
+		Level: level,
+		MinX:  lower(minLon + 180),
+		MaxX:  upper(maxLon + 180),
+		MinY:  lower(180 - maxLat),
+		MaxY:  upper(180 - minLat),
+	}
+	// 退化为点或线的边界框至少覆盖所在的格子
+	if r.MaxX < r.MinX {
+		r.MaxX = r.MinX
+	}
+	if r.MaxY < r.MinY {
+		r.MaxY = r.MinY
+	}
+	return r, true
+}
+
+// Count 返回范围内的 qtnode 数量
+func (r QtNodeRange) Count() uint64 {
+	return uint64(r.MaxX-r.MinX+1) * uint64(r.MaxY-r.MinY+1)
+}
+
+// At 返回范围内第 i 个 qtnode 地址（按列优先的顺序，与 ConvertToQtNodeFromBounds 一致）
+// i 超出范围时返回空字符串
+func (r QtNodeRange) At(i uint64) string {
+	if i >= r.Count() {
+		return ""
+	}
+	h := uint64(r.MaxY - r.MinY + 1)
+	x := r.MinX + uint(i/h)
+	y := r.MinY + uint(i%h)
+	return ConvertToQtNode(x, y, r.Level)
+}
+
 // LatLonToMeters 经纬度转米制坐标（Google Maps API 使用的墨卡托投影）
 // lat: 纬度
 // lon: 经度
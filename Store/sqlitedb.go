@@ -40,17 +40,19 @@ func initSchema(db *sql.DB, dataType string) error {
 	// 根据数据类型创建对应的表
 	switch dataType {
 	case "q2":
-		// q2 表：仅存原始 BLOB 与层级、状态
+		// q2 表：存原始 BLOB 与层级、状态，以及写入时的 epoch（未知时为 NULL）
 		if _, err := db.Exec(`
 			CREATE TABLE IF NOT EXISTS q2 (
 				tile_id BLOB PRIMARY KEY,
 				level INTEGER NOT NULL,
 				data  BLOB NOT NULL,
-				status INTEGER NOT NULL DEFAULT 0
+				status INTEGER NOT NULL DEFAULT 0,
+				epoch INTEGER NULL
 			);
 		`); err != nil {
 			return err
 		}
+		return addEpochColumn(db, "q2")
 	case "qp":
 		// qp 表：结构同 q2
 		if _, err := db.Exec(`
@@ -58,11 +60,13 @@ func initSchema(db *sql.DB, dataType string) error {
 				tile_id BLOB PRIMARY KEY,
 				level INTEGER NOT NULL,
 				data  BLOB NOT NULL,
-				status INTEGER NOT NULL DEFAULT 0
+				status INTEGER NOT NULL DEFAULT 0,
+				epoch INTEGER NULL
 			);
 		`); err != nil {
 			return err
 		}
+		return addEpochColumn(db, "qp")
 	default:
 		// 通用瓦片表：使用 BLOB 存储 tile_id（8字节），并增加 epoch / provider_id 列，便于查询
 		// 使用参数化查询防止SQL注入
@@ -86,6 +90,32 @@ func initSchema(db *sql.DB, dataType string) error {
 	return nil
 }
 
+// addEpochColumn 为旧版本创建的 q2/qp 表补充 epoch 列（已有数据的 epoch 为 NULL，视为版本未知）
+func addEpochColumn(db *sql.DB, table string) error {
+	rows, err := db.Query(`PRAGMA table_info(` + table + `);`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return err
+		}
+		if name == "epoch" {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+	_, err = db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN epoch INTEGER NULL;`)
+	return err
+}
+
 // sanitizeTableName 清理表名，确保符合SQLite标识符规范
 func sanitizeTableName(name string) string {
 	// 移除非法字符，只保留字母、数字和下划线
@@ -246,13 +276,13 @@ func PutTileSQLite(dbdir, dataType, tilekey string, value []byte) error {
 
 	// 根据数据类型决定写入方式
 	if dataType == "q2" || dataType == "qp" {
-		// q2/qp 集合：写入 tiles_q2/tiles_qp 表，仅存储 level 和 data
+		// q2/qp 集合：写入 tiles_q2/tiles_qp 表，仅存储 level 和 data（版本未知，epoch 置为 NULL）
 		level := len(tilekey)
 		
 		table := dataType // 直接使用数据类型作为表名
 		_, err = db.Exec(`
 			INSERT INTO `+table+`(tile_id, level, data) VALUES(?, ?, ?)
-			ON CONFLICT(tile_id) DO UPDATE SET level=excluded.level, data=excluded.data;
+			ON CONFLICT(tile_id) DO UPDATE SET level=excluded.level, data=excluded.data, epoch=NULL;
 		`, key, level, value)
 		return err
 	}
@@ -282,14 +312,14 @@ func PutTileSQLiteWithMetadata(dbdir, dataType, tilekey string, value []byte, ep
 
 	// 根据数据类型决定写入方式
 	if dataType == "q2" || dataType == "qp" {
-		// q2/qp 集合：写入 tiles_q2/tiles_qp 表，仅存储 level 和 data
+		// q2/qp 集合：写入 tiles_q2/tiles_qp 表，存储 level、data 与 epoch（q2/qp 表不记录 provider_id）
 		level := len(tilekey)
 		
 		table := dataType // 直接使用数据类型作为表名
 		_, err = db.Exec(`
-			INSERT INTO `+table+`(tile_id, level, data) VALUES(?, ?, ?)
-			ON CONFLICT(tile_id) DO UPDATE SET level=excluded.level, data=excluded.data;
-		`, key, level, value)
+			INSERT INTO `+table+`(tile_id, level, data, epoch) VALUES(?, ?, ?, ?)
+			ON CONFLICT(tile_id) DO UPDATE SET level=excluded.level, data=excluded.data, epoch=excluded.epoch;
+		`, key, level, value, epoch)
 		return err
 	}
 
@@ -299,9 +329,9 @@ func PutTileSQLiteWithMetadata(dbdir, dataType, tilekey string, value []byte, ep
 	// 处理 provider_id 为 NULL 的情况
 	if providerID == nil {
 		_, err = db.Exec(`
-			INSERT INTO `+tableName+`(tile_id, epoch, provider_id, value) VALUES(?, ?, ?, NULL)
+			INSERT INTO `+tableName+`(tile_id, epoch, provider_id, value) VALUES(?, ?, NULL, ?)
 			ON CONFLICT(tile_id) DO UPDATE SET epoch=excluded.epoch, provider_id=excluded.provider_id, value=excluded.value;
-		`, key, epoch, epoch) // 使用 epoch 作为默认 provider_id，但实际存储为 NULL
+		`, key, epoch, value)
 	} else {
 		_, err = db.Exec(`
 			INSERT INTO `+tableName+`(tile_id, epoch, provider_id, value) VALUES(?, ?, ?, ?)
@@ -348,7 +378,7 @@ func PutTilesSQLiteBatch(dbdir, dataType string, records map[string][]byte) erro
 			table := dataType // 直接使用数据类型作为表名
 			stmt, err = tx.Prepare(fmt.Sprintf(`
 				INSERT INTO %s(tile_id, level, data) VALUES(?, ?, ?)
-				ON CONFLICT(tile_id) DO UPDATE SET level=excluded.level, data=excluded.data;
+				ON CONFLICT(tile_id) DO UPDATE SET level=excluded.level, data=excluded.data, epoch=NULL;
 			`, table))
 		} else {
 			tableName := dataType // 直接使用数据类型作为表名
@@ -431,8 +461,8 @@ func PutTilesSQLiteBatchWithMetadata(dbdir, dataType string, records map[string]
 		if dataType == "q2" || dataType == "qp" {
 			table := dataType // 直接使用数据类型作为表名
 			stmt, err = tx.Prepare(fmt.Sprintf(`
-				INSERT INTO %s(tile_id, level, data) VALUES(?, ?, ?)
-				ON CONFLICT(tile_id) DO UPDATE SET level=excluded.level, data=excluded.data;
+				INSERT INTO %s(tile_id, level, data, epoch) VALUES(?, ?, ?, ?)
+				ON CONFLICT(tile_id) DO UPDATE SET level=excluded.level, data=excluded.data, epoch=excluded.epoch;
 			`, table))
 			} else {
 				tableName := dataType // 直接使用数据类型作为表名
@@ -466,7 +496,7 @@ func PutTilesSQLiteBatchWithMetadata(dbdir, dataType string, records map[string]
 			
 			if dataType == "q2" || dataType == "qp" {
 				level := len(tilekey)
-				if _, err := stmt.Exec(key, level, value); err != nil {
+				if _, err := stmt.Exec(key, level, value, epoch); err != nil {
 					tx.Rollback()
 					return err
 				}
@@ -524,6 +554,66 @@ func GetTileSQLite(dbdir, dataType, tilekey string) ([]byte, error) {
 	return val, nil
 }
 
+// GetTileSQLiteWithMetadata 读取数据及元数据（q2/qp 表只记录 epoch，未记录 epoch 时 metadata 返回 nil）
+func GetTileSQLiteWithMetadata(dbdir, dataType, tilekey string) ([]byte, *TileMetadata, error) {
+	if dataType == "q2" || dataType == "qp" {
+		return getQuadtreeSQLiteWithMetadata(dbdir, dataType, tilekey)
+	}
+
+	dbPath := getDBPath(dbdir, dataType, tilekey, "sqlite") // 指定存储类型为sqlite
+	// 传递 dataType 以便在 getOrOpenDBWithDataType 中正确初始化表结构
+	db, err := defaultSQLiteManager.getOrOpenDBWithDataType(dbPath, dataType)
+	if err != nil {
+		return nil, nil, err
+	}
+	tileID, err := CompressTileKeyToUint64(tilekey)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := encodeKeyBigEndian(tileID)
+
+	tableName := dataType // 直接使用数据类型作为表名
+	row := db.QueryRow(`SELECT value, epoch, provider_id FROM `+tableName+` WHERE tile_id=?;`, key)
+	var val []byte
+	var epoch int
+	var providerID sql.NullInt64
+	if err := row.Scan(&val, &epoch, &providerID); err != nil {
+		return nil, nil, err
+	}
+	metadata := &TileMetadata{Epoch: epoch}
+	if providerID.Valid {
+		id := int(providerID.Int64)
+		metadata.ProviderID = &id
+	}
+	return val, metadata, nil
+}
+
+// getQuadtreeSQLiteWithMetadata 读取 q2/qp 数据及写入时记录的 epoch
+func getQuadtreeSQLiteWithMetadata(dbdir, dataType, tilekey string) ([]byte, *TileMetadata, error) {
+	dbPath := getDBPath(dbdir, dataType, tilekey, "sqlite") // 指定存储类型为sqlite
+	db, err := defaultSQLiteManager.getOrOpenDBWithDataType(dbPath, dataType)
+	if err != nil {
+		return nil, nil, err
+	}
+	tileID, err := CompressTileKeyToUint64(tilekey)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := encodeKeyBigEndian(tileID)
+
+	table := dataType // 直接使用数据类型作为表名
+	row := db.QueryRow(`SELECT data, epoch FROM `+table+` WHERE tile_id=?;`, key)
+	var val []byte
+	var epoch sql.NullInt64
+	if err := row.Scan(&val, &epoch); err != nil {
+		return nil, nil, err
+	}
+	if !epoch.Valid {
+		return val, nil, nil
+	}
+	return val, &TileMetadata{Epoch: int(epoch.Int64)}, nil
+}
+
 // DeleteTileSQLite 删除
 func DeleteTileSQLite(dbdir, dataType, tilekey string) error {
 	dbPath := getDBPath(dbdir, dataType, tilekey, "sqlite") // 指定存储类型为sqlite
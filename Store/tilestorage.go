@@ -130,6 +130,10 @@ func decodeTileDataWithMetadata(encoded []byte) ([]byte, *TileMetadata, error) {
 		return nil, nil, err
 	}
 
+	if dataLen < 0 || int(dataLen) > buf.Len() {
+		return nil, nil, errors.New("invalid data length")
+	}
+
 	// 读取原始数据
 	data := make([]byte, dataLen)
 	if _, err := buf.Read(data); err != nil {
@@ -139,6 +143,29 @@ func decodeTileDataWithMetadata(encoded []byte) ([]byte, *TileMetadata, error) {
 	return data, metadata, nil
 }
 
+// decodeStoredTileData 严格解码带元数据的存储数据：
+// 只有头部与长度字段完全自洽时才认为是 encodeTileDataWithMetadata 的产物，否则按原始数据处理
+func decodeStoredTileData(stored []byte) ([]byte, *TileMetadata) {
+	if len(stored) == 0 || (stored[0] != 0 && stored[0] != 1) {
+		return stored, nil
+	}
+	data, metadata, err := decodeTileDataWithMetadata(stored)
+	if err != nil {
+		return stored, nil
+	}
+	headerLen := 1 + 4 // 元数据标志 + 数据长度
+	if metadata != nil {
+		headerLen += 4 + 1 // epoch + provider_id 标志
+		if metadata.ProviderID != nil {
+			headerLen += 4
+		}
+	}
+	if headerLen+len(data) != len(stored) {
+		return stored, nil
+	}
+	return data, metadata
+}
+
 // TileStorageConfig 瓦片存储配置
 type TileStorageConfig struct {
 	// 持久化后端选择（bbolt 或 sqlite）
@@ -168,6 +195,7 @@ type TileStorage struct {
 
 	// 异步持久化相关
 	persistQueue chan *persistTask
+	persistFlush chan chan struct{} // 立即持久化请求（worker 写完已入队的数据后关闭回复通道）
 	persistWg    sync.WaitGroup
 	ctx          context.Context
 	cancel       context.CancelFunc
@@ -231,6 +259,7 @@ func NewTileStorage(config TileStorageConfig) (*TileStorage, error) {
 	// 启动异步持久化 worker
 	if config.EnableAsyncPersist {
 		ts.persistQueue = make(chan *persistTask, config.PersistBatchSize*10) // 队列容量是批次的10倍
+		ts.persistFlush = make(chan chan struct{})
 		ts.startPersistWorker()
 	}
 
@@ -328,6 +357,26 @@ func (ts *TileStorage) startPersistWorker() {
 			case <-ticker.C:
 				// 定时刷新
 				flushBatch()
+
+			case done := <-ts.persistFlush:
+				// 立即持久化：收集已入队的数据后刷新
+				for collecting := true; collecting; {
+					select {
+					case task, ok := <-ts.persistQueue:
+						if !ok {
+							collecting = false
+							break
+						}
+						batch[task.tilekey] = task.value
+						dataTypeMap[task.tilekey] = task.dataType
+						epochMap[task.tilekey] = task.epoch
+						providerIDMap[task.tilekey] = task.providerID
+					default:
+						collecting = false
+					}
+				}
+				flushBatch()
+				close(done)
 			}
 		}
 	}()
@@ -561,6 +610,39 @@ func (ts *TileStorage) Get(dataType, tilekey string) ([]byte, error) {
 	return data, nil
 }
 
+// GetWithMetadata 读取瓦片数据及其元数据（epoch、provider_id）
+// 元数据未知时（如通过 Put 写入或旧版本 q2/qp 表中的数据）返回的 metadata 为 nil
+func (ts *TileStorage) GetWithMetadata(dataType, tilekey string) ([]byte, *TileMetadata, error) {
+	// 1. 先查 Redis 缓存（PutWithMetadata 写入的缓存数据带有元数据编码）
+	if ts.config.EnableCache {
+		if stored, err := GetTileRedis(ts.config.RedisAddr, dataType, tilekey); err == nil && len(stored) > 0 {
+			if data, metadata := decodeStoredTileData(stored); metadata != nil {
+				return data, metadata, nil
+			}
+			// 缓存中没有元数据，继续查持久化
+		}
+	}
+
+	// 2. 查询持久化存储
+	switch ts.config.Backend {
+	case BackendBBolt:
+		stored, err := GetTileBBolt(ts.config.DBDir, dataType, tilekey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("持久化读取失败: %w", err)
+		}
+		data, metadata := decodeStoredTileData(stored)
+		return data, metadata, nil
+	case BackendSQLite:
+		data, metadata, err := GetTileSQLiteWithMetadata(ts.config.DBDir, dataType, tilekey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("持久化读取失败: %w", err)
+		}
+		return data, metadata, nil
+	}
+
+	return nil, nil, fmt.Errorf("不支持的后端类型: %s", ts.config.Backend)
+}
+
 // Delete 删除瓦片数据（同时删除缓存和持久化）
 func (ts *TileStorage) Delete(dataType, tilekey string) error {
 	// 1. 删除缓存
@@ -675,11 +757,36 @@ func (ts *TileStorage) Close() error {
 	return nil
 }
 
+// Flush 立即持久化异步队列中已有的数据，等待写入完成或 ctx 结束
+func (ts *TileStorage) Flush(ctx context.Context) error {
+	if !ts.config.EnableAsyncPersist || ts.persistQueue == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	select {
+	case ts.persistFlush <- done:
+	case <-ctx.Done():
+		return fmt.Errorf("等待持久化队列写入超时: %w", ctx.Err())
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("等待持久化队列写入超时: %w", ctx.Err())
+	}
+}
+
 // GetBackend 获取当前持久化后端类型
 func (ts *TileStorage) GetBackend() StorageBackend {
 	return ts.config.Backend
 }
 
+// GetDBDir 获取持久化数据库目录
+func (ts *TileStorage) GetDBDir() string {
+	return ts.config.DBDir
+}
+
 // IsCacheEnabled 检查缓存是否启用
 func (ts *TileStorage) IsCacheEnabled() bool {
 	return ts.config.EnableCache
@@ -4,12 +4,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
 
+	"crawler-platform/GoogleEarth"
+	"crawler-platform/Store"
+	server "crawler-platform/cmd/grpcserver/internal"
+	"crawler-platform/cmd/grpcserver/tasksmanager"
 	"crawler-platform/utlsclient"
 )
 
@@ -64,6 +69,36 @@ type ServerConfig struct {
 	Address   string   `toml:"address"`
 	Port      string   `toml:"port"`
 	Bootstrap []string `toml:"bootstrap"`
+
+	// SubmitTaskStream 单个流允许同时执行的任务数（流控额度）
+	StreamMaxInFlight int `toml:"stream_max_in_flight"`
+
+	// 热点瓦片缓存容量（字节，保存最近成功的上游响应，0 表示不缓存）
+	HotCacheMaxBytes int64 `toml:"hot_cache_max_bytes"`
+
+	// 热连接池饱和或没有可用连接时，任务转发到其他服务器节点的最大跳数（0 表示不转发）
+	ForwardMaxHops int `toml:"forward_max_hops"`
+
+	// 收到退出信号后等待正在执行的任务与持久化队列完成的最长时间（字符串格式，如 "30s"）
+	DrainTimeout string `toml:"drain_timeout"`
+
+	// 任务可以通过 request_headers 附加的请求头（不区分大小写，为空时不允许附加请求头）
+	AllowedRequestHeaders []string `toml:"allowed_request_headers"`
+
+	// 上游响应体的最大字节数（超过时中止下载，0 表示不限制；任务的 max_body_bytes 只能进一步降低）
+	MaxBodyBytes int64 `toml:"max_body_bytes"`
+}
+
+// defaultDrainTimeout 默认排空超时时间
+const defaultDrainTimeout = 30 * time.Second
+
+// DrainTimeoutDuration 返回排空超时时间（为空或格式错误时返回默认值 30 秒）
+func (c *ServerConfig) DrainTimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(c.DrainTimeout)
+	if err != nil || d <= 0 {
+		return defaultDrainTimeout
+	}
+	return d
 }
 
 // LocalIPPoolConfig 本地 IP 池配置
@@ -103,11 +138,11 @@ type IPInfoConfig struct {
 type RockTreeDataConfig struct {
 	Enable           bool   `toml:"enable"`
 	HostName         string `toml:"HostName"`
-	HealthCheckPath  string `toml:"healthCheckPath"` // 健康检查路径（GET方法）
-	SessionIdPath    string `toml:"SessionIdPath"`   // 获取SessionID的路径（POST方法）
-	BulkMetadataPath string `toml:"BulkMetadataPath"`
-	NodeDataPath     string `toml:"NodeDataPath"`
-	ImageryDataPath  string `toml:"ImageryDataPath"`
+	HealthCheckPath  string `toml:"healthCheckPath"`  // 健康检查路径（GET方法）
+	SessionIdPath    string `toml:"SessionIdPath"`    // 获取SessionID的路径（POST方法）
+	BulkMetadataPath string `toml:"BulkMetadataPath"` // 参数: 八叉树路径, epoch
+	NodeDataPath     string `toml:"NodeDataPath"`     // 参数: 八叉树路径, epoch, 纹理格式
+	ImageryDataPath  string `toml:"ImageryDataPath"`  // 带影像版本的 NodeData，参数: 八叉树路径, epoch, 纹理格式, imageryEpoch
 }
 
 // GoogleEarthDesktopDataConfig Google Earth Desktop 数据配置
@@ -120,6 +155,146 @@ type GoogleEarthDesktopDataConfig struct {
 	Q2Path          string `toml:"q2Path"`
 	ImageryPath     string `toml:"imageryPath"`
 	TerrainPath     string `toml:"terrainPath"`
+
+	// 多数据库（tm/mars/moon/sky）数据，QP 与历史影像任务使用
+	TMHostName         string `toml:"tmHostName"`         // 多数据库数据所在主机（默认 khmdb.google.com）
+	QPPath             string `toml:"qpPath"`             // 参数: 数据库名, tilekey, epoch
+	ImageryHistoryPath string `toml:"imageryHistoryPath"` // 参数: tilekey, imageryEpoch, 十六进制日期
+}
+
+// StorageConfig 服务器端瓦片存储配置
+// 对应配置文件中的 [Storage] 表。
+type StorageConfig struct {
+	Enable             bool   `toml:"enable"`
+	Backend            string `toml:"backend"`              // 持久化后端: "bbolt" 或 "sqlite"
+	DBDir              string `toml:"db_dir"`               // 数据库文件目录
+	EnableCache        bool   `toml:"enable_cache"`         // 是否启用 Redis 缓存
+	RedisAddr          string `toml:"redis_addr"`           // Redis 地址（默认 localhost:6379）
+	CacheExpiration    string `toml:"cache_expiration"`     // 缓存过期时间（字符串格式，如 "24h"）
+	EnableAsyncPersist bool   `toml:"enable_async_persist"` // 是否启用异步持久化（Redis 作为写缓冲区）
+	PersistBatchSize   int    `toml:"persist_batch_size"`   // 异步持久化批次大小
+	PersistInterval    string `toml:"persist_interval"`     // 异步持久化间隔（字符串格式，如 "5s"）
+}
+
+// JobsConfig 作业（服务器后台批量任务）配置
+// 对应配置文件中的 [Jobs] 表。
+type JobsConfig struct {
+	StateDir    string `toml:"state_dir"`   // 作业状态持久化目录（为空时不持久化）
+	Concurrency int    `toml:"concurrency"` // 单个作业同时执行的任务数
+}
+
+// SchedulerConfig 上游请求调度配置
+// 对应配置文件中的 [Scheduler] 表。
+type SchedulerConfig struct {
+	MaxRunning int    `toml:"max_running"` // 同时请求上游的任务数上限
+	MaxQueued  int    `toml:"max_queued"`  // 等待队列容量（0 表示不排队，上限已满时立即拒绝）
+	MaxWait    string `toml:"max_wait"`    // 任务在等待队列中的最长等待时间（字符串格式，如 "10s"）
+}
+
+// MaxWaitDuration 返回最长等待时间（为空或格式错误时返回 0，由服务器使用默认值）
+func (c *SchedulerConfig) MaxWaitDuration() time.Duration {
+	d, err := time.ParseDuration(c.MaxWait)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// TaskTimeoutsConfig 各任务类型的默认超时时间（客户端未设置截止时间时使用，字符串格式，如 "10s"）
+// 对应配置文件中的 [TaskTimeouts] 表。任务类型未配置时使用 default。
+type TaskTimeoutsConfig struct {
+	Default              string `toml:"default"`
+	RockTreeBulkMetadata string `toml:"rocktree_bulk_metadata"`
+	RockTreeNodeData     string `toml:"rocktree_node_data"`
+	Q2                   string `toml:"q2"`
+	Imagery              string `toml:"imagery"`
+	ImageryHistory       string `toml:"imagery_history"`
+	QP                   string `toml:"qp"`
+	Terrain              string `toml:"terrain"`
+	HTTPSite             string `toml:"http_site"`
+}
+
+// byTaskType 返回任务类型到配置值的映射（不含 default）
+func (c *TaskTimeoutsConfig) byTaskType() map[tasksmanager.TaskType]string {
+	return map[tasksmanager.TaskType]string{
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_ROCKTREE_BULK_METADATA: c.RockTreeBulkMetadata,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_ROCKTREE_NODE_DATA:     c.RockTreeNodeData,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2:                     c.Q2,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY:                c.Imagery,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY_HISTORY:        c.ImageryHistory,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_QP:                     c.QP,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_TERRAIN:                c.Terrain,
+		tasksmanager.TaskType_TASK_TYPE_HTTP_SITE:                           c.HTTPSite,
+	}
+}
+
+// ToTaskTimeouts 返回各任务类型的超时时间（为空或格式错误时使用 default，default 也无效时由服务器使用默认值）
+func (c *TaskTimeoutsConfig) ToTaskTimeouts() map[tasksmanager.TaskType]time.Duration {
+	defaultTimeout, _ := time.ParseDuration(c.Default)
+	timeouts := make(map[tasksmanager.TaskType]time.Duration)
+	for taskType, value := range c.byTaskType() {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			d = defaultTimeout
+		}
+		timeouts[taskType] = d
+	}
+	return timeouts
+}
+
+// MetricsConfig 指标导出配置
+// 对应配置文件中的 [Metrics] 表。
+type MetricsConfig struct {
+	Enable  bool   `toml:"enable"`
+	Address string `toml:"address"` // HTTP 监听地址（如 "127.0.0.1:9100"），Prometheus 从 /metrics 抓取
+}
+
+// AdminConfig 管理接口与仪表盘配置
+// 对应配置文件中的 [Admin] 表。
+type AdminConfig struct {
+	Enable  bool   `toml:"enable"`
+	Address string `toml:"address"` // HTTP 监听地址（与 [Metrics] 相同时共用一个 HTTP 服务器）
+	WebDir  string `toml:"web_dir"` // 仪表盘静态文件目录（为空时只提供 /api 接口）
+}
+
+// AuthConfig 客户端认证与授权配置
+// 对应配置文件中的 [Auth] 表。
+type AuthConfig struct {
+	Enable    bool   `toml:"enable"`
+	NodeToken string `toml:"node_token"` // 本节点连接其他节点时携带的 API 令牌（其他节点需将其配置为 node 角色）
+
+	// 静态 API 令牌（客户端通过 authorization: Bearer <令牌> 元数据携带）
+	Tokens []AuthTokenConfig `toml:"tokens"`
+
+	// mTLS 客户端证书身份（按证书 CN 或 DNS 名称匹配，需要证书目录中提供 ca.crt）
+	Certs []AuthCertConfig `toml:"certs"`
+}
+
+// AuthTokenConfig 静态 API 令牌
+// 对应配置文件中的 [[Auth.tokens]] 表。
+type AuthTokenConfig struct {
+	Name  string `toml:"name"`
+	Token string `toml:"token"`
+	Role  string `toml:"role"` // admin / node / client
+}
+
+// AuthCertConfig 客户端证书身份
+// 对应配置文件中的 [[Auth.certs]] 表。
+type AuthCertConfig struct {
+	Name string `toml:"name"` // 证书 CN 或 DNS 名称
+	Role string `toml:"role"` // admin / node / client
+}
+
+// SiteConfig 通用 HTTP 站点配置（HTTP_SITE 任务通过 site 名称引用）
+// 对应配置文件中的 [[Sites]] 数组表。
+type SiteConfig struct {
+	Name           string            `toml:"name"`
+	HostName       string            `toml:"HostName"`
+	PathTemplates  map[string]string `toml:"path_templates"`  // 模板名 -> 路径模板（{参数名} 为占位符，如 "/api/items/{id}?page={page}"）
+	Headers        map[string]string `toml:"headers"`         // 每个请求携带的默认请求头
+	ValidatorPath  string            `toml:"validator_path"`  // 连接池验证该主机连接时请求的路径（为空时使用 UtlsClient.health_check_path）
+	AllowedMethods []string          `toml:"allowed_methods"` // 允许的 HTTP 方法（为空时只允许 GET）
+	AllowFullPath  bool              `toml:"allow_full_path"` // 是否允许任务直接指定完整路径
 }
 
 // Config gRPC 服务器整体配置
@@ -137,6 +312,14 @@ type Config struct {
 	RockTreeData           RockTreeDataConfig           `toml:"RockTreeDataConfig"`
 	GoogleEarthDesktopData GoogleEarthDesktopDataConfig `toml:"GoogleEarthDesktopDataConfig"`
 	UtlsClient             UtlsClientConfig             `toml:"UtlsClient"`
+	Storage                StorageConfig                `toml:"Storage"`
+	Jobs                   JobsConfig                   `toml:"Jobs"`
+	Scheduler              SchedulerConfig              `toml:"Scheduler"`
+	TaskTimeouts           TaskTimeoutsConfig           `toml:"TaskTimeouts"`
+	Auth                   AuthConfig                   `toml:"Auth"`
+	Metrics                MetricsConfig                `toml:"Metrics"`
+	Admin                  AdminConfig                  `toml:"Admin"`
+	Sites                  []SiteConfig                 `toml:"Sites"` // 通用 HTTP 站点（HTTP_SITE 任务）
 }
 
 // UtlsClientConfig UTLS 客户端连接池配置
@@ -174,6 +357,19 @@ type UtlsClientConfig struct {
 
 	// 获取SessionID的请求体（POST方法使用）
 	SessionIdBody []byte `toml:"session_id_body"`
+
+	// 以下限速在每次从热连接池获取连接时计数（每次上游请求获取一次连接，包括重试），健康检查不计入
+	// 每个目标主机每秒允许的请求数（<=0 表示不限速）及突发请求数
+	PerHostRate  float64 `toml:"per_host_rate"`
+	PerHostBurst int     `toml:"per_host_burst"`
+
+	// 每个远程 IP 每秒允许的请求数（<=0 表示不限速）及突发请求数
+	PerRemoteIPRate  float64 `toml:"per_remote_ip_rate"`
+	PerRemoteIPBurst int     `toml:"per_remote_ip_burst"`
+
+	// 每个本地源 IP 每秒允许的请求数（<=0 表示不限速）及突发请求数
+	PerLocalIPRate  float64 `toml:"per_local_ip_rate"`
+	PerLocalIPBurst int     `toml:"per_local_ip_burst"`
 }
 
 // defaultConfig 返回一份合理的默认配置（在没有配置文件时使用）
@@ -193,8 +389,17 @@ func defaultConfig() *Config {
 			Type: ProtocolTypeGRPC, // 默认使用 gRPC
 		},
 		Server: ServerConfig{
-			Address: "0.0.0.0",
-			Port:    "50051",
+			Address:           "0.0.0.0",
+			Port:              "50051",
+			StreamMaxInFlight: 64,
+			HotCacheMaxBytes:  256 << 20,
+			ForwardMaxHops:    2,
+			DrainTimeout:      "30s",
+			AllowedRequestHeaders: []string{
+				"Accept", "Accept-Language", "Content-Type", "Cookie",
+				"If-Match", "If-Modified-Since", "If-None-Match", "If-Range", "If-Unmodified-Since", "Range",
+			},
+			MaxBodyBytes: 256 << 20,
 		},
 		TUIC: TUICConfig{
 			Enable:      false,
@@ -221,10 +426,16 @@ func defaultConfig() *Config {
 			Token: "",
 		},
 		RockTreeData: RockTreeDataConfig{
-			Enable: false,
+			Enable:           false,
+			BulkMetadataPath: GoogleEarth.ROCKTREE_BULK_METADATA_PATH,
+			NodeDataPath:     GoogleEarth.ROCKTREE_NODE_DATA_PATH,
+			ImageryDataPath:  GoogleEarth.ROCKTREE_NODE_DATA_WITH_IMAGERY_PATH,
 		},
 		GoogleEarthDesktopData: GoogleEarthDesktopDataConfig{
-			Enable: false,
+			Enable:             false,
+			TMHostName:         GoogleEarth.TM_HOST_NAME,
+			QPPath:             GoogleEarth.QPQ2_PATH,
+			ImageryHistoryPath: GoogleEarth.IMAGERY_WITH_TM_PATH,
 		},
 		UtlsClient: UtlsClientConfig{
 			MaxConnsPerHost:       10,
@@ -239,6 +450,42 @@ func defaultConfig() *Config {
 			SessionIdPath:         "",
 			SessionIdBody:         nil,
 		},
+		Storage: StorageConfig{
+			Enable:           false,
+			Backend:          string(Store.BackendBBolt),
+			DBDir:            "./data/tiles",
+			CacheExpiration:  "24h",
+			PersistBatchSize: 100,
+			PersistInterval:  "5s",
+		},
+		Jobs: JobsConfig{
+			StateDir:    "./data/jobs",
+			Concurrency: 16,
+		},
+		Metrics: MetricsConfig{
+			Enable:  false,
+			Address: "127.0.0.1:9100",
+		},
+		Admin: AdminConfig{
+			Enable:  false,
+			Address: "127.0.0.1:8080",
+			WebDir:  "./web",
+		},
+		Scheduler: SchedulerConfig{
+			MaxRunning: 64,
+			MaxQueued:  1024,
+			MaxWait:    "10s",
+		},
+		TaskTimeouts: TaskTimeoutsConfig{
+			Default:              "30s",
+			RockTreeBulkMetadata: "15s",
+			RockTreeNodeData:     "30s",
+			Q2:                   "15s",
+			Imagery:              "30s",
+			ImageryHistory:       "30s",
+			QP:                   "15s",
+			Terrain:              "30s",
+		},
 	}
 }
 
@@ -276,6 +523,32 @@ func (c *Config) GetCertsDir() string {
 	return "./certs"
 }
 
+// TUICCertPaths 返回 TUIC 服务器使用的 TLS 证书与密钥路径。
+// 优先使用 [tuic] 中的 tls_cert_path / tls_key_path；未指定时从证书目录查找 cert.pem/key.pem 或 server.crt/server.key。
+// 找不到证书时返回空字符串。
+func (c *Config) TUICCertPaths() (certPath, keyPath string) {
+	certPath = c.TUIC.TLSCertPath
+	keyPath = c.TUIC.TLSKeyPath
+	if certPath != "" && keyPath != "" {
+		return certPath, keyPath
+	}
+
+	certsDir := c.GetCertsDir()
+	for _, pair := range [][2]string{{"cert.pem", "key.pem"}, {"server.crt", "server.key"}} {
+		if _, err := os.Stat(filepath.Join(certsDir, pair[0])); err != nil {
+			continue
+		}
+		if certPath == "" {
+			certPath = filepath.Join(certsDir, pair[0])
+		}
+		if keyPath == "" {
+			keyPath = filepath.Join(certsDir, pair[1])
+		}
+		break
+	}
+	return certPath, keyPath
+}
+
 // LoadDNSServersFromJSON 从 JSON 文件加载 DNS 服务器列表。
 // JSON 格式示例: ["8.8.8.8", "1.1.1.1"]
 // 输入:
@@ -360,6 +633,104 @@ func (c *UtlsClientConfig) ToPoolConfig() *utlsclient.PoolConfig {
 		HealthCheckPath:       c.HealthCheckPath,
 		SessionIdPath:         c.SessionIdPath,
 		SessionIdBody:         c.SessionIdBody,
+		RateLimits:            c.ToRateLimits(),
+	}
+}
+
+// ToPoolLimits 将 UtlsClientConfig 中可在运行时修改的连接池配置转换为 utlsclient.PoolLimits。
+func (c *UtlsClientConfig) ToPoolLimits() utlsclient.PoolLimits {
+	poolConfig := c.ToPoolConfig()
+	return utlsclient.PoolLimits{
+		MaxConnsPerHost:       poolConfig.MaxConnsPerHost,
+		MaxConcurrentPreWarms: poolConfig.MaxConcurrentPreWarms,
+		PreWarmInterval:       poolConfig.PreWarmInterval,
+		ConnTimeout:           poolConfig.ConnTimeout,
+		IdleTimeout:           poolConfig.IdleTimeout,
+		HealthCheckInterval:   poolConfig.HealthCheckInterval,
+		IPBlacklistTimeout:    poolConfig.IPBlacklistTimeout,
+	}
+}
+
+// ToRateLimits 将 UtlsClientConfig 中的限速配置转换为 utlsclient.RateLimits。
+func (c *UtlsClientConfig) ToRateLimits() utlsclient.RateLimits {
+	return utlsclient.RateLimits{
+		PerHost:     utlsclient.RateLimit{Rate: c.PerHostRate, Burst: c.PerHostBurst},
+		PerRemoteIP: utlsclient.RateLimit{Rate: c.PerRemoteIPRate, Burst: c.PerRemoteIPBurst},
+		PerLocalIP:  utlsclient.RateLimit{Rate: c.PerLocalIPRate, Burst: c.PerLocalIPBurst},
+	}
+}
+
+// ToIdentities 将 AuthConfig 转换为 API 令牌与证书名称到身份的映射。
+func (c *AuthConfig) ToIdentities() (tokens, certs map[string]server.AuthIdentity, err error) {
+	validRole := func(role string) bool {
+		return role == server.RoleAdmin || role == server.RoleNode || role == server.RoleClient
+	}
+	tokens = make(map[string]server.AuthIdentity, len(c.Tokens))
+	for _, t := range c.Tokens {
+		if t.Token == "" || !validRole(t.Role) {
+			return nil, nil, fmt.Errorf("无效的 API 令牌配置: %s（令牌不能为空，角色必须为 admin/node/client）", t.Name)
+		}
+		tokens[t.Token] = server.AuthIdentity{Name: t.Name, Role: t.Role}
+	}
+	certs = make(map[string]server.AuthIdentity, len(c.Certs))
+	for _, cert := range c.Certs {
+		if cert.Name == "" || !validRole(cert.Role) {
+			return nil, nil, fmt.Errorf("无效的证书身份配置: %s（名称不能为空，角色必须为 admin/node/client）", cert.Name)
+		}
+		certs[cert.Name] = server.AuthIdentity{Name: cert.Name, Role: cert.Role}
+	}
+	return tokens, certs, nil
+}
+
+// ToSites 将 Sites 配置转换为 server.Site 列表。
+func (c *Config) ToSites() []server.Site {
+	sites := make([]server.Site, 0, len(c.Sites))
+	for _, site := range c.Sites {
+		sites = append(sites, server.Site{
+			Name:           site.Name,
+			HostName:       site.HostName,
+			PathTemplates:  site.PathTemplates,
+			Headers:        site.Headers,
+			AllowedMethods: site.AllowedMethods,
+			AllowFullPath:  site.AllowFullPath,
+		})
+	}
+	return sites
+}
+
+// SiteHealthCheckPaths 返回配置了 validator_path 的站点主机名到验证路径的映射。
+func (c *Config) SiteHealthCheckPaths() map[string]string {
+	paths := make(map[string]string)
+	for _, site := range c.Sites {
+		if site.ValidatorPath != "" {
+			paths[site.HostName] = site.ValidatorPath
+		}
+	}
+	return paths
+}
+
+// ToTileStorageConfig 将 StorageConfig 转换为 Store.TileStorageConfig。
+func (c *StorageConfig) ToTileStorageConfig() Store.TileStorageConfig {
+	parseDuration := func(s string, defaultVal time.Duration) time.Duration {
+		if s == "" {
+			return defaultVal
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return defaultVal
+		}
+		return d
+	}
+
+	return Store.TileStorageConfig{
+		Backend:            Store.StorageBackend(c.Backend),
+		DBDir:              c.DBDir,
+		RedisAddr:          c.RedisAddr,
+		CacheExpiration:    parseDuration(c.CacheExpiration, 24*time.Hour),
+		EnableCache:        c.EnableCache,
+		EnableAsyncPersist: c.EnableAsyncPersist,
+		PersistBatchSize:   c.PersistBatchSize,
+		PersistInterval:    parseDuration(c.PersistInterval, 5*time.Second),
 	}
 }
 
@@ -0,0 +1,689 @@
+package grpcserver
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+	"crawler-platform/utlsclient"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// adminMaxBodyBytes 管理 API 请求体大小上限
+const adminMaxBodyBytes = 16 << 20
+
+// adminResponse 管理 API 的统一响应格式（与 web/index.js 中 fetchJSON 的约定一致）
+type adminResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	Data    any    `json:"data"`
+}
+
+// adminTask 仪表盘任务列表中的一行（由作业信息转换）
+type adminTask struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	Type          string  `json:"type"`
+	Status        string  `json:"status"` // pending / running / completed / failed / cancelled
+	Priority      int     `json:"priority"`
+	Schedule      string  `json:"schedule"`
+	LastExecution string  `json:"lastExecution"`
+	CreatedAt     string  `json:"createdAt"`
+	SuccessRate   float64 `json:"successRate"` // 已完成任务中成功的百分比
+	Target        string  `json:"target"`      // 最近执行的瓦片键
+	Progress      float64 `json:"progress"`    // 已完成任务的百分比
+	Total         int64   `json:"total"`
+	Success       int64   `json:"success"`
+	Failed        int64   `json:"failed"`
+}
+
+// adminCreateTaskRequest 仪表盘创建任务表单
+// 服务器只执行 Google Earth 瓦片作业，作业定义（protojson 格式）放在 job、regionJob 或 discoveryJob 中的一个
+type adminCreateTaskRequest struct {
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	URL          string          `json:"url"`
+	Schedule     string          `json:"schedule"`
+	Job          json.RawMessage `json:"job"`          // CreateJobRequest
+	RegionJob    json.RawMessage `json:"regionJob"`    // CreateRegionJobRequest
+	DiscoveryJob json.RawMessage `json:"discoveryJob"` // CreateDiscoveryJobRequest
+}
+
+// adminLocalIP 本地 IP 池中的一个地址
+type adminLocalIP struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	Type    string `json:"type"`   // ipv4 / ipv6
+	Source  string `json:"source"` // static（配置的静态 IPv4）/ subnet（IPv6 子网动态生成）
+	Status  string `json:"status"`
+}
+
+// adminIPRequest 白名单/黑名单添加请求
+type adminIPRequest struct {
+	IP string `json:"ip"`
+}
+
+// adminIPSettings 热连接池的 IP 策略（只读，修改需要编辑配置文件 [UtlsClient] 表并重启）
+type adminIPSettings struct {
+	PreheatConnections    int   `json:"preheatConnections"`    // 每个主机的最大连接数
+	MaxFailures           int   `json:"maxFailures"`           // 拉黑前允许的 403 次数（收到 403 立即拉黑）
+	RotateIntervalSeconds int64 `json:"rotateIntervalSeconds"` // 连接池重新预热间隔
+	AutoRecoverSeconds    int64 `json:"autoRecoverSeconds"`    // 黑名单超时时间
+}
+
+// adminPoolStats 仪表盘热连接池统计卡片
+type adminPoolStats struct {
+	TotalConnections   int64
+	ActiveConnections  int64 // 正在使用的连接数
+	IdleConnections    int64 // 健康且空闲的连接数
+	HealthyConnections int64
+	SuccessRate        float64
+	ConnReuseRate      float64
+	WhitelistIPs       int
+	BlacklistIPs       int
+	RateLimited        int64
+	LastUpdateTime     time.Time
+}
+
+// SetAdminWebDir 设置管理仪表盘静态文件目录（为空时只提供 /api 接口）
+func (s *Server) SetAdminWebDir(dir string) {
+	s.adminWebDir = dir
+}
+
+// registerAdminRoutes 注册管理 API 与仪表盘静态文件
+func (s *Server) registerAdminRoutes(mux *http.ServeMux) {
+	api := func(pattern string, handler http.HandlerFunc) {
+		mux.Handle(pattern, s.adminAuth(handler))
+	}
+
+	api("GET /api/tasks", s.handleAdminListTasks)
+	api("POST /api/tasks/create", s.handleAdminCreateTask)
+	api("GET /api/jobs", s.handleAdminListJobs)
+	api("POST /api/jobs", s.handleAdminCreateJob)
+	api("POST /api/jobs/region", s.handleAdminCreateRegionJob)
+	api("POST /api/jobs/discovery", s.handleAdminCreateDiscoveryJob)
+	api("GET /api/jobs/{id}", s.handleAdminGetJob)
+	api("POST /api/jobs/{id}/{action}", s.handleAdminJobAction)
+
+	api("GET /api/clients", s.handleAdminListClients)
+	api("GET /api/nodes", s.handleAdminListNodes)
+	api("GET /api/scheduler", s.handleAdminSchedulerStats)
+	api("GET /api/events", s.handleAdminEvents)
+	api("POST /api/events/session", s.handleAdminEventsSession)
+
+	api("GET /api/pool/stats", s.handleAdminPoolStats)
+	api("GET /api/pool/connections", s.handleAdminPoolConnections)
+
+	api("GET /api/ip/local", s.handleAdminListLocalIPs)
+	api("POST /api/ip/local", s.handleAdminAddLocalIP)
+	api("DELETE /api/ip/local/{id}", s.handleAdminReleaseLocalIP)
+	api("GET /api/ip/whitelist", s.handleAdminListWhitelist)
+	api("POST /api/ip/whitelist", s.handleAdminAddWhitelist)
+	api("DELETE /api/ip/whitelist", s.handleAdminRemoveWhitelist)
+	api("GET /api/ip/blacklist", s.handleAdminListBlacklist)
+	api("POST /api/ip/blacklist", s.handleAdminAddBlacklist)
+	api("DELETE /api/ip/blacklist", s.handleAdminRemoveBlacklist)
+	api("GET /api/ip/settings", s.handleAdminGetIPSettings)
+	api("PUT /api/ip/settings", s.handleAdminPutIPSettings)
+
+	api("PUT /api/users/{id}", s.handleAdminUpdateUser)
+
+	api("POST /api/config/reload", s.handleAdminReloadConfig)
+
+	if s.adminWebDir != "" {
+		mux.Handle("GET /", http.FileServer(http.Dir(s.adminWebDir)))
+	}
+}
+
+// adminEventsCookie 事件流的令牌 Cookie（EventSource 无法设置请求头，只用于 GET /api/events）
+const adminEventsCookie = "crawler_admin_events"
+
+// adminAuth 启用认证时，管理 API 要求携带 admin 角色的 API 令牌（Authorization: Bearer <令牌>）
+// GET /api/events 也可以使用 POST /api/events/session 设置的 Cookie
+func (s *Server) adminAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.authEnabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found && r.Method == http.MethodGet && r.URL.Path == "/api/events" {
+			if cookie, err := r.Cookie(adminEventsCookie); err == nil {
+				token, found = cookie.Value, true
+			}
+		}
+		if !found {
+			writeAdminError(w, status.Error(codes.Unauthenticated, "缺少 API 令牌，应为 Authorization: Bearer <令牌>"))
+			return
+		}
+		identity, ok := s.tokenIdentity(token)
+		if !ok {
+			writeAdminError(w, status.Error(codes.Unauthenticated, "无效的 API 令牌"))
+			return
+		}
+		if identity.Role != RoleAdmin {
+			s.logger.Warn("拒绝未授权的管理 API 调用: %s %s, 身份: %s (%s)", r.Method, r.URL.Path, identity.Name, identity.Role)
+			writeAdminError(w, status.Errorf(codes.PermissionDenied, "身份 %s (%s) 无权调用管理 API", identity.Name, identity.Role))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authIdentityKey{}, identity)))
+	})
+}
+
+// handleAdminEventsSession 将请求携带的 API 令牌写入只用于事件流的 HttpOnly Cookie（未启用认证时不设置）
+func (s *Server) handleAdminEventsSession(w http.ResponseWriter, r *http.Request) {
+	if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found && s.authEnabled {
+		http.SetCookie(w, &http.Cookie{
+			Name:     adminEventsCookie,
+			Value:    token,
+			Path:     "/api/events",
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteStrictMode,
+		})
+	}
+	writeAdminJSON(w, nil)
+}
+
+// adminProtoJSON 按 protojson 格式编码 proto 消息（输出零值字段，便于前端直接读取）
+func adminProtoJSON(m proto.Message) (json.RawMessage, error) {
+	return protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(m)
+}
+
+// writeAdminJSON 写入成功响应（proto 消息按 protojson 格式编码）
+func writeAdminJSON(w http.ResponseWriter, data any) {
+	if m, ok := data.(proto.Message); ok {
+		raw, err := adminProtoJSON(m)
+		if err != nil {
+			writeAdminError(w, fmt.Errorf("编码响应失败: %w", err))
+			return
+		}
+		data = raw
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(adminResponse{Success: true, Data: data})
+}
+
+// writeAdminError 写入错误响应（HTTP 状态码按 gRPC 状态码映射）
+func writeAdminError(w http.ResponseWriter, err error) {
+	httpStatus := http.StatusInternalServerError
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		httpStatus = http.StatusBadRequest
+	case codes.NotFound:
+		httpStatus = http.StatusNotFound
+	case codes.FailedPrecondition, codes.AlreadyExists:
+		httpStatus = http.StatusConflict
+	case codes.Unauthenticated:
+		httpStatus = http.StatusUnauthorized
+	case codes.PermissionDenied:
+		httpStatus = http.StatusForbidden
+	case codes.Unimplemented:
+		httpStatus = http.StatusNotImplemented
+	case codes.Unavailable:
+		httpStatus = http.StatusServiceUnavailable
+	}
+	message := err.Error()
+	if st, ok := status.FromError(err); ok {
+		message = st.Message()
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(adminResponse{Success: false, Message: message})
+}
+
+// readAdminJSON 解码 JSON 请求体
+func readAdminJSON(r *http.Request, v any) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, adminMaxBodyBytes))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "读取请求体失败: %v", err)
+	}
+	if m, ok := v.(proto.Message); ok {
+		err = protojson.Unmarshal(body, m)
+	} else {
+		err = json.Unmarshal(body, v)
+	}
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "请求体格式错误: %v", err)
+	}
+	return nil
+}
+
+// adminTaskFromJob 将作业信息转换为仪表盘任务行
+func adminTaskFromJob(info *tasksmanager.JobInfo) adminTask {
+	done := info.GetSuccessCount() + info.GetFailedCount()
+	task := adminTask{
+		ID:            info.GetJobId(),
+		Name:          info.GetName(),
+		Type:          "google_earth",
+		Priority:      1, // 作业任务按 BULK 优先级执行
+		Schedule:      "once",
+		LastExecution: info.GetUpdateTime(),
+		CreatedAt:     info.GetCreateTime(),
+		Target:        info.GetCurrentTileKey(),
+		Total:         info.GetTotalCount(),
+		Success:       info.GetSuccessCount(),
+		Failed:        info.GetFailedCount(),
+	}
+	if done > 0 {
+		task.SuccessRate = float64(info.GetSuccessCount()) * 100 / float64(done)
+	}
+	if info.GetTotalCount() > 0 {
+		task.Progress = float64(done) * 100 / float64(info.GetTotalCount())
+	}
+
+	switch info.GetStatus() {
+	case tasksmanager.TasksStatus_TASKS_STATUS_RUNNING:
+		task.Status = "running"
+	case tasksmanager.TasksStatus_TASKS_STATUS_STOPPED:
+		task.Status = "cancelled"
+	case tasksmanager.TasksStatus_TASKS_STATUS_COMPLETED:
+		task.Status = "completed"
+		if info.GetSuccessCount() == 0 && info.GetFailedCount() > 0 {
+			task.Status = "failed"
+		}
+	default:
+		task.Status = "pending"
+	}
+	return task
+}
+
+// handleAdminListTasks 以仪表盘任务格式返回所有作业
+func (s *Server) handleAdminListTasks(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.ListJobs(r.Context(), &tasksmanager.ListJobsRequest{})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	tasks := make([]adminTask, 0, len(resp.GetItems()))
+	for _, info := range resp.GetItems() {
+		tasks = append(tasks, adminTaskFromJob(info))
+	}
+	writeAdminJSON(w, tasks)
+}
+
+// handleAdminCreateTask 按仪表盘表单创建作业，返回仪表盘任务格式
+func (s *Server) handleAdminCreateTask(w http.ResponseWriter, r *http.Request) {
+	var req adminCreateTaskRequest
+	if err := readAdminJSON(r, &req); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	if req.Schedule != "" && req.Schedule != "once" {
+		writeAdminError(w, status.Errorf(codes.Unimplemented, "暂不支持 %s 调度，作业创建后立即执行一次", req.Schedule))
+		return
+	}
+
+	var (
+		info *tasksmanager.JobInfo
+		err  error
+	)
+	switch {
+	case len(req.Job) > 0:
+		jobReq := &tasksmanager.CreateJobRequest{}
+		if err = protojson.Unmarshal(req.Job, jobReq); err == nil {
+			jobReq.Name = cmp.Or(jobReq.Name, req.Name)
+			info, err = s.CreateJob(r.Context(), jobReq)
+		}
+	case len(req.RegionJob) > 0:
+		jobReq := &tasksmanager.CreateRegionJobRequest{}
+		if err = protojson.Unmarshal(req.RegionJob, jobReq); err == nil {
+			jobReq.Name = cmp.Or(jobReq.Name, req.Name)
+			info, err = s.CreateRegionJob(r.Context(), jobReq)
+		}
+	case len(req.DiscoveryJob) > 0:
+		jobReq := &tasksmanager.CreateDiscoveryJobRequest{}
+		if err = protojson.Unmarshal(req.DiscoveryJob, jobReq); err == nil {
+			jobReq.Name = cmp.Or(jobReq.Name, req.Name)
+			info, err = s.CreateDiscoveryJob(r.Context(), jobReq)
+		}
+	default:
+		err = status.Errorf(codes.Unimplemented, "不支持按 URL 创建 %s 任务：服务器只执行 Google Earth 瓦片作业，请在 job、regionJob 或 discoveryJob 中提供作业定义", req.Type)
+	}
+	if err != nil {
+		if _, ok := status.FromError(err); !ok {
+			err = status.Errorf(codes.InvalidArgument, "作业定义格式错误: %v", err)
+		}
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, adminTaskFromJob(info))
+}
+
+// handleAdminListJobs 返回作业列表
+func (s *Server) handleAdminListJobs(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.ListJobs(r.Context(), &tasksmanager.ListJobsRequest{})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, resp)
+}
+
+// handleAdminCreateJob 按任务列表创建作业
+func (s *Server) handleAdminCreateJob(w http.ResponseWriter, r *http.Request) {
+	req := &tasksmanager.CreateJobRequest{}
+	if err := readAdminJSON(r, req); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	info, err := s.CreateJob(r.Context(), req)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, info)
+}
+
+// handleAdminCreateRegionJob 按区域创建作业
+func (s *Server) handleAdminCreateRegionJob(w http.ResponseWriter, r *http.Request) {
+	req := &tasksmanager.CreateRegionJobRequest{}
+	if err := readAdminJSON(r, req); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	info, err := s.CreateRegionJob(r.Context(), req)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, info)
+}
+
+// handleAdminCreateDiscoveryJob 创建 Q2 引用发现作业
+func (s *Server) handleAdminCreateDiscoveryJob(w http.ResponseWriter, r *http.Request) {
+	req := &tasksmanager.CreateDiscoveryJobRequest{}
+	if err := readAdminJSON(r, req); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	info, err := s.CreateDiscoveryJob(r.Context(), req)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, info)
+}
+
+// handleAdminGetJob 返回作业信息
+func (s *Server) handleAdminGetJob(w http.ResponseWriter, r *http.Request) {
+	info, err := s.GetJob(r.Context(), &tasksmanager.JobRequest{JobId: r.PathValue("id")})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, info)
+}
+
+// handleAdminJobAction 暂停、恢复或取消作业
+func (s *Server) handleAdminJobAction(w http.ResponseWriter, r *http.Request) {
+	req := &tasksmanager.JobRequest{JobId: r.PathValue("id")}
+	var (
+		info *tasksmanager.JobInfo
+		err  error
+	)
+	switch action := r.PathValue("action"); action {
+	case "pause":
+		info, err = s.PauseJob(r.Context(), req)
+	case "resume":
+		info, err = s.ResumeJob(r.Context(), req)
+	case "cancel":
+		info, err = s.CancelJob(r.Context(), req)
+	default:
+		err = status.Errorf(codes.NotFound, "未知的作业操作: %s（支持 pause / resume / cancel）", action)
+	}
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, info)
+}
+
+// handleAdminListClients 返回已注册的任务客户端
+func (s *Server) handleAdminListClients(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.GetTaskClientInfoList(r.Context(), &tasksmanager.TaskClientInfoListRequest{})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, resp)
+}
+
+// handleAdminListNodes 返回已知的服务器节点
+func (s *Server) handleAdminListNodes(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.GetGrpcServerNodeInfoList(r.Context(), &tasksmanager.GrpcServerNodeInfoListRequest{})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, resp)
+}
+
+// handleAdminSchedulerStats 返回任务调度统计
+func (s *Server) handleAdminSchedulerStats(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, s.scheduler.stats())
+}
+
+// requireUTLSClient 返回热连接池客户端（未初始化时写入错误响应并返回 nil）
+func (s *Server) requireUTLSClient(w http.ResponseWriter) *utlsclient.Client {
+	if s.utlsClient == nil {
+		writeAdminError(w, status.Error(codes.Unavailable, "UTLS 热连接池未初始化"))
+	}
+	return s.utlsClient
+}
+
+// poolStats 返回热连接池统计（热连接池未初始化时返回 false）
+func (s *Server) poolStats() (adminPoolStats, bool) {
+	client := s.utlsClient
+	if client == nil {
+		return adminPoolStats{}, false
+	}
+	snapshot := client.GetMetrics()
+	stats := adminPoolStats{
+		TotalConnections:   snapshot.ActiveConnections,
+		ActiveConnections:  snapshot.InUseConnections,
+		IdleConnections:    snapshot.HealthyConnections - snapshot.InUseConnections,
+		HealthyConnections: snapshot.HealthyConnections,
+		SuccessRate:        snapshot.SuccessRate(),
+		WhitelistIPs:       len(client.WhitelistedIPs()),
+		BlacklistIPs:       int(snapshot.BlacklistedIPs),
+		RateLimited:        snapshot.RateLimitedRequests,
+		LastUpdateTime:     time.Now(),
+	}
+	if snapshot.TotalRequests > 0 {
+		stats.ConnReuseRate = max(0, float64(snapshot.TotalRequests-snapshot.ConnectionsCreated)*100/float64(snapshot.TotalRequests))
+	}
+	return stats, true
+}
+
+// handleAdminPoolStats 返回热连接池统计
+func (s *Server) handleAdminPoolStats(w http.ResponseWriter, r *http.Request) {
+	if s.requireUTLSClient(w) == nil {
+		return
+	}
+	stats, _ := s.poolStats()
+	writeAdminJSON(w, stats)
+}
+
+// handleAdminPoolConnections 返回热连接池中的所有连接
+func (s *Server) handleAdminPoolConnections(w http.ResponseWriter, r *http.Request) {
+	if client := s.requireUTLSClient(w); client != nil {
+		writeAdminJSON(w, client.Connections())
+	}
+}
+
+// handleAdminListLocalIPs 返回本地 IP 池中的地址
+func (s *Server) handleAdminListLocalIPs(w http.ResponseWriter, r *http.Request) {
+	ips := []adminLocalIP{}
+	if s.ipPool != nil {
+		for _, addr := range s.ipPool.GetIPv4Addresses() {
+			ips = append(ips, adminLocalIP{ID: addr, Address: addr, Type: "ipv4", Source: "static", Status: "active"})
+		}
+		for _, addr := range s.ipPool.GetActiveIPv6Addresses() {
+			ips = append(ips, adminLocalIP{ID: addr, Address: addr, Type: "ipv6", Source: "subnet", Status: "active"})
+		}
+	}
+	writeAdminJSON(w, ips)
+}
+
+// handleAdminAddLocalIP 本地 IP 池不支持手动添加地址
+func (s *Server) handleAdminAddLocalIP(w http.ResponseWriter, r *http.Request) {
+	writeAdminError(w, status.Error(codes.Unimplemented, "本地 IP 池不支持手动添加地址：IPv4 地址来自配置文件 [LocalIPPool] 表，IPv6 地址由子网自动生成"))
+}
+
+// handleAdminReleaseLocalIP 释放本地 IP 池中的 IPv6 地址（删除后由地址池生成新地址）
+func (s *Server) handleAdminReleaseLocalIP(w http.ResponseWriter, r *http.Request) {
+	if s.ipPool == nil {
+		writeAdminError(w, status.Error(codes.Unavailable, "本地 IP 池未启用"))
+		return
+	}
+	ip := net.ParseIP(r.PathValue("id"))
+	if ip == nil {
+		writeAdminError(w, status.Errorf(codes.InvalidArgument, "无效的 IP 地址: %s", r.PathValue("id")))
+		return
+	}
+	if ip.To4() != nil {
+		writeAdminError(w, status.Error(codes.FailedPrecondition, "静态 IPv4 地址来自配置文件，不能在运行时删除"))
+		return
+	}
+	s.ipPool.ReleaseIP(ip)
+	s.logger.Info("已通过管理 API 释放本地 IPv6 地址: %s", ip)
+	writeAdminJSON(w, nil)
+}
+
+// parseAdminIP 校验并规范化请求中的 IP 地址
+func parseAdminIP(value string) (string, error) {
+	ip := net.ParseIP(strings.TrimSpace(value))
+	if ip == nil {
+		return "", status.Errorf(codes.InvalidArgument, "无效的 IP 地址: %q", value)
+	}
+	return ip.String(), nil
+}
+
+// handleAdminListWhitelist 返回白名单（已建立热连接的远程 IP）
+func (s *Server) handleAdminListWhitelist(w http.ResponseWriter, r *http.Request) {
+	if client := s.requireUTLSClient(w); client != nil {
+		writeAdminJSON(w, client.WhitelistedIPs())
+	}
+}
+
+// handleAdminAddWhitelist 为远程 IP 建立并验证热连接
+func (s *Server) handleAdminAddWhitelist(w http.ResponseWriter, r *http.Request) {
+	client := s.requireUTLSClient(w)
+	if client == nil {
+		return
+	}
+	var req adminIPRequest
+	if err := readAdminJSON(r, &req); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	ip, err := parseAdminIP(req.IP)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	if err := client.WarmIP(ip); err != nil {
+		code := codes.Unavailable
+		if errors.Is(err, utlsclient.ErrInvalidConfig) {
+			code = codes.InvalidArgument
+		}
+		writeAdminError(w, status.Error(code, err.Error()))
+		return
+	}
+	writeAdminJSON(w, ip)
+}
+
+// handleAdminRemoveWhitelist 关闭到远程 IP 的热连接
+func (s *Server) handleAdminRemoveWhitelist(w http.ResponseWriter, r *http.Request) {
+	client := s.requireUTLSClient(w)
+	if client == nil {
+		return
+	}
+	ip, err := parseAdminIP(r.URL.Query().Get("ip"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	client.RemoveConnection(ip)
+	writeAdminJSON(w, ip)
+}
+
+// handleAdminListBlacklist 返回黑名单中的远程 IP
+func (s *Server) handleAdminListBlacklist(w http.ResponseWriter, r *http.Request) {
+	if client := s.requireUTLSClient(w); client != nil {
+		writeAdminJSON(w, client.BlacklistedIPs())
+	}
+}
+
+// handleAdminAddBlacklist 将远程 IP 加入黑名单并关闭其热连接
+func (s *Server) handleAdminAddBlacklist(w http.ResponseWriter, r *http.Request) {
+	client := s.requireUTLSClient(w)
+	if client == nil {
+		return
+	}
+	var req adminIPRequest
+	if err := readAdminJSON(r, &req); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	ip, err := parseAdminIP(req.IP)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	client.BlockIP(ip)
+	writeAdminJSON(w, ip)
+}
+
+// handleAdminRemoveBlacklist 将远程 IP 移出黑名单
+func (s *Server) handleAdminRemoveBlacklist(w http.ResponseWriter, r *http.Request) {
+	client := s.requireUTLSClient(w)
+	if client == nil {
+		return
+	}
+	ip, err := parseAdminIP(r.URL.Query().Get("ip"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	client.UnblockIP(ip)
+	writeAdminJSON(w, ip)
+}
+
+// handleAdminGetIPSettings 返回热连接池的 IP 策略
+func (s *Server) handleAdminGetIPSettings(w http.ResponseWriter, r *http.Request) {
+	client := s.requireUTLSClient(w)
+	if client == nil {
+		return
+	}
+	config := client.Config()
+	writeAdminJSON(w, adminIPSettings{
+		PreheatConnections:    config.MaxConnsPerHost,
+		MaxFailures:           1,
+		RotateIntervalSeconds: int64(config.PreWarmInterval / time.Second),
+		AutoRecoverSeconds:    int64(config.IPBlacklistTimeout / time.Second),
+	})
+}
+
+// handleAdminPutIPSettings IP 策略不支持在运行时修改
+func (s *Server) handleAdminPutIPSettings(w http.ResponseWriter, r *http.Request) {
+	writeAdminError(w, status.Error(codes.Unimplemented, "IP 策略不支持在运行时修改，请编辑配置文件 [UtlsClient] 表后重启服务器"))
+}
+
+// handleAdminUpdateUser 服务器没有用户管理
+func (s *Server) handleAdminUpdateUser(w http.ResponseWriter, r *http.Request) {
+	writeAdminError(w, status.Error(codes.Unimplemented, "服务器没有用户管理，访问控制由配置文件 [Auth] 表中的令牌与证书决定"))
+}
@@ -0,0 +1,200 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"strings"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// 身份角色
+const (
+	RoleAdmin  = "admin"  // 管理员：可以调用所有接口
+	RoleNode   = "node"   // 服务器节点：节点间注册、心跳、同步与任务转发
+	RoleClient = "client" // 任务客户端：提交任务、注册与心跳
+)
+
+// AuthIdentity 认证后的调用方身份
+type AuthIdentity struct {
+	Name string // 身份名称（令牌名称或证书 CN/DNS 名称）
+	Role string // 角色（admin / node / client）
+}
+
+// methodRoles 各接口允许调用的角色（admin 可以调用所有接口，未列出的接口只允许 admin）
+var methodRoles = map[string][]string{
+	tasksmanager.TasksManager_SubmitTask_FullMethodName:            {RoleClient, RoleNode},
+	tasksmanager.TasksManager_SubmitTaskStream_FullMethodName:      {RoleClient, RoleNode},
+	tasksmanager.TasksManager_DownloadTask_FullMethodName:          {RoleClient, RoleNode},
+	tasksmanager.TasksManager_RegisterClient_FullMethodName:        {RoleClient},
+	tasksmanager.TasksManager_ClientHeartbeat_FullMethodName:       {RoleClient},
+	tasksmanager.TasksManager_GetTaskClientInfoList_FullMethodName: {RoleClient, RoleNode},
+
+	// 节点列表包含所有节点地址，只允许节点间同步使用
+	tasksmanager.TasksManager_GetGrpcServerNodeInfoList_FullMethodName: {RoleNode},
+	tasksmanager.TasksManager_RegisterNode_FullMethodName:              {RoleNode},
+	tasksmanager.TasksManager_NodeHeartbeat_FullMethodName:             {RoleNode},
+	tasksmanager.TasksManager_SendNodeMessage_FullMethodName:           {RoleNode},
+	tasksmanager.TasksManager_SyncNodeList_FullMethodName:              {RoleNode},
+
+	// 服务反射只暴露接口定义，允许所有已认证的调用方使用
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:        {RoleClient, RoleNode},
+	reflectionv1alphapb.ServerReflection_ServerReflectionInfo_FullMethodName: {RoleClient, RoleNode},
+}
+
+// publicMethods 不需要认证的接口（负载均衡器的健康检查不携带令牌或客户端证书）
+var publicMethods = map[string]bool{
+	healthpb.Health_Check_FullMethodName: true,
+	healthpb.Health_Watch_FullMethodName: true,
+	healthpb.Health_List_FullMethodName:  true,
+}
+
+// authIdentityKey 请求上下文键：认证后的调用方身份
+type authIdentityKey struct{}
+
+// AuthIdentityFromContext 返回请求上下文中认证后的调用方身份（未启用认证时返回 false）
+func AuthIdentityFromContext(ctx context.Context) (AuthIdentity, bool) {
+	identity, ok := ctx.Value(authIdentityKey{}).(AuthIdentity)
+	return identity, ok
+}
+
+// SetAuthConfig 设置客户端认证与授权
+// tokens 为静态 API 令牌 -> 身份；certIdentities 为客户端证书 CN 或 DNS 名称 -> 身份（需要 TLS 配置了 ClientCAs）
+// nodeToken 为本节点连接其他节点时携带的令牌（为空时只依赖 mTLS 证书身份）
+// 必须在 Start 之前调用
+func (s *Server) SetAuthConfig(enabled bool, tokens, certIdentities map[string]AuthIdentity, nodeToken string) {
+	s.authEnabled = enabled
+	s.authTokens = tokens
+	s.authCertIdentities = certIdentities
+	if s.nodeConnector != nil {
+		s.nodeConnector.SetAuthToken(nodeToken)
+	}
+}
+
+// authenticate 从请求元数据中的 Bearer 令牌或 mTLS 客户端证书识别调用方身份
+// 携带了令牌时只按令牌认证（无效令牌不回退到证书）
+func (s *Server) authenticate(ctx context.Context) (AuthIdentity, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token, found := strings.CutPrefix(values[0], "Bearer ")
+			if !found {
+				return AuthIdentity{}, status.Error(codes.Unauthenticated, "authorization 元数据格式错误，应为 Bearer <令牌>")
+			}
+			if identity, ok := s.tokenIdentity(token); ok {
+				return identity, nil
+			}
+			return AuthIdentity{}, status.Error(codes.Unauthenticated, "无效的 API 令牌")
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			if identity, ok := s.certIdentity(tlsInfo.State.VerifiedChains[0][0]); ok {
+				return identity, nil
+			}
+		}
+	}
+	return AuthIdentity{}, status.Error(codes.Unauthenticated, "缺少有效的 API 令牌或客户端证书")
+}
+
+// tokenIdentity 按 API 令牌查找身份（常量时间比较）
+func (s *Server) tokenIdentity(token string) (AuthIdentity, bool) {
+	for known, identity := range s.authTokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			return identity, true
+		}
+	}
+	return AuthIdentity{}, false
+}
+
+// certIdentity 按证书 CN 与 DNS 名称查找身份（只使用已通过 CA 验证的证书）
+func (s *Server) certIdentity(cert *x509.Certificate) (AuthIdentity, bool) {
+	if identity, ok := s.authCertIdentities[cert.Subject.CommonName]; ok {
+		return identity, true
+	}
+	for _, name := range cert.DNSNames {
+		if identity, ok := s.authCertIdentities[name]; ok {
+			return identity, true
+		}
+	}
+	return AuthIdentity{}, false
+}
+
+// authorize 认证调用方并检查其角色是否允许调用该接口，返回带身份的上下文
+func (s *Server) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	if publicMethods[fullMethod] {
+		return ctx, nil
+	}
+	identity, err := s.authenticate(ctx)
+	if err != nil {
+		s.logger.Warn("拒绝未认证的调用: %s: %v", fullMethod, err)
+		return nil, err
+	}
+	if identity.Role != RoleAdmin {
+		allowed := false
+		for _, role := range methodRoles[fullMethod] {
+			if role == identity.Role {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			s.logger.Warn("拒绝未授权的调用: %s, 身份: %s (%s)", fullMethod, identity.Name, identity.Role)
+			return nil, status.Errorf(codes.PermissionDenied, "身份 %s (%s) 无权调用 %s", identity.Name, identity.Role, fullMethod)
+		}
+	}
+	return context.WithValue(ctx, authIdentityKey{}, identity), nil
+}
+
+// authUnaryInterceptor 一元调用的认证与授权拦截器
+func (s *Server) authUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authServerStream 携带认证身份上下文的服务端流
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// authStreamInterceptor 流式调用的认证与授权拦截器
+func (s *Server) authStreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authorize(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authServerStream{ServerStream: stream, ctx: ctx})
+}
+
+// tokenCredentials 以 Bearer 令牌形式携带的每次调用凭证（节点连接其他节点时使用）
+type tokenCredentials struct {
+	token string
+}
+
+func (c tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+// RequireTransportSecurity 令牌只通过 TLS 连接发送（明文连接上 gRPC 会拒绝携带该凭证）
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,65 @@
+package grpcserver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+)
+
+// bodyConsumer 读取上游响应体，retryable 表示读取失败时能否换连接重试
+type bodyConsumer func(resp *http.Response) (body []byte, retryable bool, err error)
+
+// SetMaxBodyBytes 设置上游响应体的最大字节数（<=0 表示不限制，任务的 max_body_bytes 只能进一步降低）
+func (s *Server) SetMaxBodyBytes(n int64) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.maxBodyBytes = n
+}
+
+// bodyLimit 返回任务的响应体大小限制（服务器限制与任务限制中较小的一个，<=0 表示不限制）
+func (s *Server) bodyLimit(req *tasksmanager.TaskRequest) int64 {
+	s.configMu.RLock()
+	limit := s.maxBodyBytes
+	s.configMu.RUnlock()
+	if n := req.GetMaxBodyBytes(); n > 0 && (limit <= 0 || n < limit) {
+		limit = n
+	}
+	return limit
+}
+
+// bufferedBody 将响应体读入内存，超过 limit 时中止读取（超过限制不重试）
+func bufferedBody(limit int64) bodyConsumer {
+	return func(resp *http.Response) ([]byte, bool, error) {
+		body, err := readLimitedBody(resp, limit)
+		if err != nil {
+			return nil, !errors.Is(err, ErrBodyTooLarge), err
+		}
+		return body, true, nil
+	}
+}
+
+// readLimitedBody 读取响应体，Content-Length 或实际读取的数据超过 limit 时返回 ErrBodyTooLarge
+func readLimitedBody(resp *http.Response, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(resp.Body)
+	}
+	if resp.ContentLength > limit {
+		return nil, bodyTooLarge(limit)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, bodyTooLarge(limit)
+	}
+	return body, nil
+}
+
+// bodyTooLarge 生成响应体超过大小限制的错误
+func bodyTooLarge(limit int64) error {
+	return fmt.Errorf("响应体超过 %d 字节: %w", limit, ErrBodyTooLarge)
+}
@@ -0,0 +1,133 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"crawler-platform/GoogleEarth"
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+)
+
+const (
+	// cryptKeySyncInterval 解密密钥定期从 dbRoot 重新同步的间隔
+	cryptKeySyncInterval = time.Hour
+	// cryptKeyRetryInterval 两次同步尝试的最小间隔（同步失败或解码失败触发同步时避免频繁请求 dbRoot）
+	cryptKeyRetryInterval = time.Minute
+)
+
+// validateDecodeMode 校验任务请求的解码方式（只有 Google Earth Desktop 数据任务支持解码）
+func validateDecodeMode(req *tasksmanager.TaskRequest) error {
+	if req.GetDecode() == tasksmanager.TaskDecodeMode_TASK_DECODE_MODE_NONE {
+		return nil
+	}
+	if _, ok := tasksmanager.TaskDecodeMode_name[int32(req.GetDecode())]; !ok {
+		return fmt.Errorf("不支持的解码方式: %v", req.GetDecode())
+	}
+	switch req.TaskType {
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_QP,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY_HISTORY,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_TERRAIN:
+		return nil
+	default:
+		return fmt.Errorf("任务类型不支持解码: %v", req.TaskType)
+	}
+}
+
+// decodeTaskBody 按解码方式解码 Google Earth 数据
+// 解码失败时（通常是 dbRoot 密钥已更新）强制同步一次密钥后重试
+func (s *Server) decodeTaskBody(taskType tasksmanager.TaskType, mode tasksmanager.TaskDecodeMode, tileKey string, body []byte) ([]byte, error) {
+	if mode == tasksmanager.TaskDecodeMode_TASK_DECODE_MODE_NONE {
+		return body, nil
+	}
+	s.syncCryptKey(false)
+	decoded, err := s.decodeGEPayload(taskType, mode, tileKey, body)
+	if err != nil && s.syncCryptKey(true) {
+		decoded, err = s.decodeGEPayload(taskType, mode, tileKey, body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecodeFailed, err)
+	}
+	return decoded, nil
+}
+
+// decodeGEPayload 使用当前密钥解码 Google Earth 数据
+func (s *Server) decodeGEPayload(taskType tasksmanager.TaskType, mode tasksmanager.TaskDecodeMode, tileKey string, body []byte) ([]byte, error) {
+	s.cryptKeyMu.RLock()
+	defer s.cryptKeyMu.RUnlock()
+
+	switch taskType {
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY,
+		tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY_HISTORY:
+		return GoogleEarth.DecryptImagery(body)
+	}
+
+	data, err := GoogleEarth.UnpackGEZlib(body)
+	if err != nil {
+		return nil, fmt.Errorf("解包数据失败: %w", err)
+	}
+	if taskType != tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2 || mode != tasksmanager.TaskDecodeMode_TASK_DECODE_MODE_JSON {
+		return data, nil
+	}
+
+	out, err := GoogleEarth.ParseQ2Body(data, tileKey, len(tileKey) < 4)
+	if err != nil {
+		return nil, err
+	}
+	var q2 GoogleEarth.Q2Response
+	if err := json.Unmarshal([]byte(out), &q2); err != nil {
+		return nil, fmt.Errorf("解析 Q2 结果失败: %w", err)
+	}
+	if !q2.Success {
+		return nil, fmt.Errorf("解析 Q2 数据失败: %s", q2.Error)
+	}
+	return []byte(out), nil
+}
+
+// syncCryptKey 从 dbRoot 同步解密密钥（GoogleEarth.UpdateCryptKeyFromDBRoot）
+// force=false 时只在距上次同步成功超过 cryptKeySyncInterval 时同步；已有同步在进行时直接返回
+// 返回本次是否成功同步了密钥
+func (s *Server) syncCryptKey(force bool) bool {
+	if !s.cryptKeySyncMu.TryLock() {
+		return false
+	}
+	defer s.cryptKeySyncMu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.cryptKeyAttempted) < cryptKeyRetryInterval {
+		return false
+	}
+	if !force && now.Sub(s.cryptKeySynced) < cryptKeySyncInterval {
+		return false
+	}
+	s.configMu.RLock()
+	enabled, hostName := s.googleEarthDesktopDataEnable, s.googleEarthDesktopDataHostName
+	s.configMu.RUnlock()
+	if !enabled || hostName == "" {
+		return false
+	}
+	s.cryptKeyAttempted = now
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.taskTimeout(tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2))
+	defer cancel()
+	body, statusCode, _, err := s.executeTaskWithHotPool(ctx, "GoogleEarthDesktopData", hostName, GoogleEarth.DBROOT_PATH, &tasksmanager.TaskRequest{}, nil)
+	if err != nil || statusCode != http.StatusOK {
+		s.logger.Warn("获取 dbRoot 失败，继续使用当前解密密钥: 状态码: %d, 错误: %v", statusCode, err)
+		return false
+	}
+
+	s.cryptKeyMu.Lock()
+	version, err := GoogleEarth.UpdateCryptKeyFromDBRoot(body)
+	s.cryptKeyMu.Unlock()
+	if err != nil {
+		s.logger.Warn("解析 dbRoot 失败，继续使用当前解密密钥: %v", err)
+		return false
+	}
+	s.cryptKeySynced = now
+	s.logger.Info("已从 dbRoot 同步解密密钥，dbRoot 版本: %d", version)
+	return true
+}
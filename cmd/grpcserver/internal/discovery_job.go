@@ -0,0 +1,231 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"crawler-platform/GoogleEarth"
+	"crawler-platform/Store"
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// discoveryJobSource Q2 发现作业的任务来源（CreateDiscoveryJob 创建）
+// 待执行任务按后进先出的顺序执行（深度优先），使前沿大小与层级数成正比而不是与瓦片总数成正比
+type discoveryJobSource struct {
+	spec    *tasksmanager.CreateDiscoveryJobRequest
+	queue   []*tasksmanager.TaskRequest          // 待执行的任务（前沿）
+	running map[uint64]*tasksmanager.TaskRequest // 正在执行的任务
+	nextID  uint64
+}
+
+// newDiscoveryJobSource 创建 Q2 发现作业的任务来源（不包含起始任务）
+func newDiscoveryJobSource(spec *tasksmanager.CreateDiscoveryJobRequest) *discoveryJobSource {
+	return &discoveryJobSource{spec: spec, running: make(map[uint64]*tasksmanager.TaskRequest)}
+}
+
+func (d *discoveryJobSource) next() (uint64, *tasksmanager.TaskRequest, bool) {
+	if len(d.queue) == 0 {
+		return 0, nil, false
+	}
+	task := d.queue[len(d.queue)-1]
+	d.queue = d.queue[:len(d.queue)-1]
+	id := d.nextID
+	d.nextID++
+	d.running[id] = task
+	return id, proto.Clone(task).(*tasksmanager.TaskRequest), true
+}
+
+func (d *discoveryJobSource) done(id uint64, taskStatus tasksmanager.TaskStatus, discovered []*tasksmanager.TaskRequest) {
+	delete(d.running, id)
+	d.queue = append(d.queue, discovered...)
+}
+
+func (d *discoveryJobSource) reset() {
+	for _, task := range d.running {
+		d.queue = append(d.queue, task)
+	}
+	d.running = make(map[uint64]*tasksmanager.TaskRequest)
+}
+
+func (d *discoveryJobSource) save(state *jobState) error {
+	raw, err := protojson.Marshal(d.spec)
+	if err != nil {
+		return fmt.Errorf("序列化发现作业参数失败: %w", err)
+	}
+	state.Discovery = raw
+	// 正在执行的任务与前沿一起保存，恢复时重新执行
+	state.Frontier = make([]json.RawMessage, 0, len(d.queue)+len(d.running))
+	for _, task := range d.queue {
+		if raw, err = protojson.Marshal(task); err != nil {
+			return fmt.Errorf("序列化发现作业任务失败: %w", err)
+		}
+		state.Frontier = append(state.Frontier, raw)
+	}
+	for _, task := range d.running {
+		if raw, err = protojson.Marshal(task); err != nil {
+			return fmt.Errorf("序列化发现作业任务失败: %w", err)
+		}
+		state.Frontier = append(state.Frontier, raw)
+	}
+	return nil
+}
+
+// execute 执行发现作业中的任务
+// Q2 任务成功后解析数据包并返回新发现的子 Q2、影像、地形任务；其他任务与普通作业相同
+func (d *discoveryJobSource) execute(s *Server, task *tasksmanager.TaskRequest) (tasksmanager.TaskStatus, []*tasksmanager.TaskRequest) {
+	if task.TaskType != tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2 {
+		return s.executeJobTask(task), nil
+	}
+
+	// 存储中已标记处理完成的 Q2 直接使用存储的数据展开，不再请求上游
+	body, ok := s.processedQ2Body(task.TileKey, task.Epoch)
+	if !ok {
+		resp, err := s.runTask(context.Background(), bulkPriority(task))
+		if err != nil || resp.GetTaskResponseStatusCode() != http.StatusOK {
+			return tasksmanager.TaskStatus_TASK_STATUS_FAILED, nil
+		}
+		body = resp.GetTaskResponseBody()
+	}
+
+	data, err := s.decodeTaskBody(task.TaskType, tasksmanager.TaskDecodeMode_TASK_DECODE_MODE_RAW, task.TileKey, body)
+	if err != nil {
+		s.logger.Warn("Q2 数据解包失败: %s, 错误: %v", task.TileKey, err)
+		return tasksmanager.TaskStatus_TASK_STATUS_FAILED, nil
+	}
+	discovered, err := d.expand(task.TileKey, data)
+	if err != nil {
+		s.logger.Warn("Q2 数据展开失败: %s, 错误: %v", task.TileKey, err)
+		return tasksmanager.TaskStatus_TASK_STATUS_FAILED, nil
+	}
+	s.markQ2Processed(task.TileKey)
+	return tasksmanager.TaskStatus_TASK_STATUS_SUCCESS, discovered
+}
+
+// expand 解析解包后的 Q2 数据包，返回不超过最大层级的子 Q2、影像、地形任务
+func (d *discoveryJobSource) expand(tileKey string, data []byte) ([]*tasksmanager.TaskRequest, error) {
+	out, err := GoogleEarth.ParseQ2BodyWithOptions(data, tileKey, len(tileKey) < 4, GoogleEarth.Q2ParseOptions{
+		IncludeImagery: d.spec.GetIncludeImagery(),
+		IncludeTerrain: d.spec.GetIncludeTerrain(),
+		IncludeQ2:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var q2 GoogleEarth.Q2Response
+	if err := json.Unmarshal([]byte(out), &q2); err != nil {
+		return nil, fmt.Errorf("解析 Q2 结果失败: %w", err)
+	}
+	if !q2.Success {
+		return nil, fmt.Errorf("解析 Q2 数据失败: %s", q2.Error)
+	}
+
+	withinLevel := func(tk string) bool { return len(tk)-1 <= int(d.spec.GetMaxLevel()) }
+	providerID := func(provider uint16) *int32 {
+		if provider == 0 {
+			return nil
+		}
+		id := int32(provider)
+		return &id
+	}
+
+	var tasks []*tasksmanager.TaskRequest
+	// 先加入子 Q2，后加入瓦片任务，使同一数据包中的瓦片先于更深层的 Q2 执行
+	for _, ref := range q2.Q2List {
+		if ref.Tilekey == tileKey || !withinLevel(ref.Tilekey) {
+			continue
+		}
+		tasks = append(tasks, &tasksmanager.TaskRequest{
+			TaskType: tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2,
+			TileKey:  ref.Tilekey,
+			Epoch:    int32(ref.Version),
+		})
+	}
+	for _, ref := range q2.ImageryList {
+		if !withinLevel(ref.Tilekey) {
+			continue
+		}
+		imageryEpoch := int32(ref.Version)
+		tasks = append(tasks, &tasksmanager.TaskRequest{
+			TaskType:     tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY,
+			TileKey:      ref.Tilekey,
+			Epoch:        d.spec.GetEpoch(),
+			ImageryEpoch: &imageryEpoch,
+			ProviderId:   providerID(ref.Provider),
+		})
+	}
+	for _, ref := range q2.TerrainList {
+		if !withinLevel(ref.Tilekey) {
+			continue
+		}
+		tasks = append(tasks, &tasksmanager.TaskRequest{
+			TaskType:   tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_TERRAIN,
+			TileKey:    ref.Tilekey,
+			Epoch:      int32(ref.Version),
+			ProviderId: providerID(ref.Provider),
+		})
+	}
+	return tasks, nil
+}
+
+// processedQ2Body 如果 Q2 已在 SQLite 存储中标记为处理完成且存储的版本与请求一致，返回存储的 Q2 数据
+func (s *Server) processedQ2Body(tileKey string, epoch int32) ([]byte, bool) {
+	if s.tileStorage == nil || s.tileStorage.GetBackend() != Store.BackendSQLite {
+		return nil, false
+	}
+	processed, err := Store.IsQ2Processed(s.tileStorage.GetDBDir(), "q2", tileKey)
+	if err != nil || !processed {
+		return nil, false
+	}
+	body, metadata, err := s.tileStorage.GetWithMetadata("q2", tileKey)
+	if err != nil || len(body) == 0 || metadata == nil || metadata.Epoch != int(epoch) {
+		return nil, false
+	}
+	return body, true
+}
+
+// markQ2Processed 在 SQLite 存储中将 Q2 标记为处理完成（Q2 数据由写穿存储写入，未写入时不做任何修改）
+func (s *Server) markQ2Processed(tileKey string) {
+	if s.tileStorage == nil || s.tileStorage.GetBackend() != Store.BackendSQLite {
+		return
+	}
+	if err := Store.MarkQ2AsProcessed(s.tileStorage.GetDBDir(), "q2", tileKey); err != nil {
+		s.logger.Debug("标记 Q2 处理完成失败: %s, 错误: %v", tileKey, err)
+	}
+}
+
+// CreateDiscoveryJob 创建 Q2 发现作业并立即在后台开始执行
+func (s *Server) CreateDiscoveryJob(ctx context.Context, req *tasksmanager.CreateDiscoveryJobRequest) (*tasksmanager.JobInfo, error) {
+	spec := proto.Clone(req).(*tasksmanager.CreateDiscoveryJobRequest)
+	if spec.RootTileKey == "" {
+		spec.RootTileKey = "0"
+	}
+	if _, _, level := GoogleEarth.ConvertFromQtNode(spec.RootTileKey); level == GoogleEarth.MAX_LEVEL {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的起始 Q2 瓦片键: %q", spec.RootTileKey)
+	}
+	if !isQ2PacketKeyLength(len(spec.RootTileKey)) {
+		return nil, status.Errorf(codes.InvalidArgument, "起始瓦片键 %q 不是 Q2 数据包节点（需为 \"0\" 或长度为 4 的倍数）", spec.RootTileKey)
+	}
+	if spec.GetMaxLevel() < int32(len(spec.RootTileKey)-1) || spec.GetMaxLevel() > maxRegionJobLevel {
+		return nil, status.Errorf(codes.InvalidArgument, "无效的最大层级: %d（允许 %d-%d）", spec.GetMaxLevel(), len(spec.RootTileKey)-1, maxRegionJobLevel)
+	}
+	if !spec.GetIncludeImagery() && !spec.GetIncludeTerrain() {
+		return nil, status.Error(codes.InvalidArgument, "发现作业至少需要执行影像或地形任务之一")
+	}
+
+	source := newDiscoveryJobSource(spec)
+	source.queue = append(source.queue, &tasksmanager.TaskRequest{
+		TaskType: tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2,
+		TileKey:  spec.RootTileKey,
+		Epoch:    spec.GetEpoch(),
+	})
+
+	j := newJob(newJobInfo(spec.GetName(), 1), source)
+	return s.startJob(j)
+}
@@ -0,0 +1,214 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// downloadChunkSize DownloadTask 每个数据分块的最大字节数
+const downloadChunkSize = 256 << 10
+
+// DownloadTask 执行任务并以分块流式返回响应体（适用于大文件，服务端不缓存完整响应体）
+// 第一条消息携带不含响应体的任务响应，最后一条消息携带总长度与 SHA-256 校验和
+// 流式下载不参与合并、转发与缓存，也不写入存储；热点缓存或存储已有数据时直接分块返回
+func (s *Server) DownloadTask(req *tasksmanager.TaskRequest, stream grpc.ServerStreamingServer[tasksmanager.TaskChunk]) error {
+	// 排空期间拒绝新任务（可重试错误，客户端应提交到其他节点）
+	if !s.enterTask() {
+		err := taskErrorStatus(&tasksmanager.TaskResponse{
+			TaskClientId: req.TaskClientId,
+			TaskType:     req.TaskType,
+			TileKey:      req.TileKey,
+		}, ErrServerDraining)
+		s.taskMetrics.observeTask(req.TaskType, nil, err, 0)
+		return err
+	}
+	defer s.exitTask()
+
+	start := time.Now()
+	resp, err := s.downloadTask(stream.Context(), req, stream)
+	duration := time.Since(start)
+	s.taskMetrics.observeTask(req.TaskType, resp, err, duration)
+	s.publishTaskEvent(req, resp, err, duration)
+	return err
+}
+
+// downloadTask 执行流式下载任务，返回第一条消息中的任务响应
+func (s *Server) downloadTask(ctx context.Context, req *tasksmanager.TaskRequest, stream grpc.ServerStreamingServer[tasksmanager.TaskChunk]) (*tasksmanager.TaskResponse, error) {
+	ctx, cancel := s.withTaskTimeout(ctx, req.TaskType)
+	defer cancel()
+
+	tileKey, epoch, imageryEpoch, err := s.extractTaskParams(req)
+	if err != nil {
+		return nil, fmt.Errorf("提取任务参数失败: %w", err)
+	}
+	dataType, hostName, path, err := s.buildPathForTask(req, tileKey, epoch, imageryEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("构建路径失败: %w", err)
+	}
+	if req.GetDecode() != tasksmanager.TaskDecodeMode_TASK_DECODE_MODE_NONE {
+		return nil, status.Error(codes.InvalidArgument, "流式下载不支持解码响应体")
+	}
+	if err := s.validateRequestHeaders(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response := &tasksmanager.TaskResponse{
+		TaskClientId: req.TaskClientId,
+		TaskType:     req.TaskType,
+	}
+	s.setResponseParams(response, tileKey, epoch, imageryEpoch)
+	w := &chunkWriter{stream: stream, response: response, limit: s.bodyLimit(req), hash: sha256.New()}
+
+	// 热点缓存或存储中已有相同版本的数据时直接返回，不访问上游
+	var localBody []byte
+	var localSource tasksmanager.TaskResponseSource
+	if upstreamKey, cacheable := upstreamKeyForTask(req, hostName, path); cacheable {
+		if cachedBody, ok := s.hotCache.get(upstreamKey); ok {
+			localBody, localSource = cachedBody, tasksmanager.TaskResponseSource_TASK_RESPONSE_SOURCE_CACHE
+		}
+	}
+	if localBody == nil {
+		if storedBody, ok := s.getTaskFromStorage(req, tileKey, epoch, imageryEpoch); ok {
+			localBody, localSource = storedBody, tasksmanager.TaskResponseSource_TASK_RESPONSE_SOURCE_STORAGE
+		}
+	}
+	if localBody != nil {
+		response.ResponseSource = localSource
+		if err := w.sendLocal(localBody); err != nil {
+			return nil, taskErrorStatus(response, err)
+		}
+		return response, w.finish()
+	}
+
+	// 上游并发已满时按任务优先级排队；上游返回的响应体边读取边发送
+	_, _, err = s.scheduler.run(ctx, req.GetPriority(), func() ([]byte, int32, error) {
+		body, statusCode, header, err := s.executeTaskWithHotPool(ctx, dataType, hostName, path, req, w.consume)
+		if err != nil && !w.started {
+			response.ResponseHeaders = taskResponseHeaders(header)
+		}
+		return body, statusCode, err
+	})
+	if err != nil {
+		if !w.started {
+			errorStatusCode, errorBody := taskFailureBody(err)
+			response.TaskResponseStatusCode = &errorStatusCode
+			response.TaskResponseBody = errorBody
+		}
+		s.logger.Warn("下载任务失败: %s%s, 错误码: %v, 错误: %v", hostName, path, taskErrorCode(err), err)
+		return nil, taskErrorStatus(response, err)
+	}
+	return response, w.finish()
+}
+
+// chunkWriter 将响应体分块发送到 DownloadTask 流，同时计算总长度与校验和
+type chunkWriter struct {
+	stream   grpc.ServerStreamingServer[tasksmanager.TaskChunk]
+	response *tasksmanager.TaskResponse
+	limit    int64 // 响应体大小限制（<=0 表示不限制）
+	hash     hash.Hash
+	size     int64
+	started  bool // 是否已发送第一条消息（之后失败不能重试）
+}
+
+// consume 作为上游响应体的读取方式：403 与 5xx 读入内存按失败处理，其他状态码边读取边发送
+func (w *chunkWriter) consume(resp *http.Response) ([]byte, bool, error) {
+	if isUpstreamErrorStatus(resp.StatusCode) {
+		return bufferedBody(w.limit)(resp)
+	}
+	if w.limit > 0 && resp.ContentLength > w.limit {
+		return nil, false, bodyTooLarge(w.limit)
+	}
+	statusCode := int32(resp.StatusCode)
+	w.response.TaskResponseStatusCode = &statusCode
+	w.response.ResponseHeaders = taskResponseHeaders(resp.Header)
+	if err := w.start(); err != nil {
+		return nil, false, err
+	}
+
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := readChunk(resp.Body, buf)
+		if n > 0 {
+			if writeErr := w.write(buf[:n]); writeErr != nil {
+				return nil, false, writeErr
+			}
+		}
+		if err == io.EOF {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+}
+
+// sendLocal 分块发送热点缓存或存储中的数据
+func (w *chunkWriter) sendLocal(body []byte) error {
+	if w.limit > 0 && int64(len(body)) > w.limit {
+		return bodyTooLarge(w.limit)
+	}
+	statusCode := int32(http.StatusOK)
+	w.response.TaskResponseStatusCode = &statusCode
+	if err := w.start(); err != nil {
+		return err
+	}
+	for len(body) > 0 {
+		n := min(len(body), downloadChunkSize)
+		if err := w.write(body[:n]); err != nil {
+			return err
+		}
+		body = body[n:]
+	}
+	return nil
+}
+
+// start 发送携带任务响应的第一条消息
+func (w *chunkWriter) start() error {
+	w.started = true
+	return w.stream.Send(&tasksmanager.TaskChunk{Response: w.response})
+}
+
+// write 发送一个数据分块（累计长度超过限制时返回 ErrBodyTooLarge）
+func (w *chunkWriter) write(data []byte) error {
+	if w.limit > 0 && w.size+int64(len(data)) > w.limit {
+		return bodyTooLarge(w.limit)
+	}
+	w.hash.Write(data)
+	chunk := &tasksmanager.TaskChunk{Data: append([]byte(nil), data...), Offset: w.size}
+	w.size += int64(len(data))
+	return w.stream.Send(chunk)
+}
+
+// finish 发送携带总长度与校验和的最后一条消息
+func (w *chunkWriter) finish() error {
+	return w.stream.Send(&tasksmanager.TaskChunk{
+		Last:      true,
+		TotalSize: w.size,
+		Sha256:    hex.EncodeToString(w.hash.Sum(nil)),
+	})
+}
+
+// readChunk 读满 buf 或读到响应体结束（结束时返回 io.EOF）
+func readChunk(r io.Reader, buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		m, err := r.Read(buf[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,172 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+)
+
+const (
+	// nodeLeavingMessageType 节点离开通知的消息类型（接收方移除该节点，不再向其转发任务）
+	nodeLeavingMessageType = "NODE_LEAVING"
+	// nodeLeavingNotifyTimeout 通知单个节点离开的超时时间
+	nodeLeavingNotifyTimeout = 3 * time.Second
+	// drainPollInterval 等待正在执行的任务完成时的检查间隔
+	drainPollInterval = 100 * time.Millisecond
+)
+
+// nodeLeavingPayload 节点离开通知的消息负载（与 NODE_DISCOVERED 相同格式）
+type nodeLeavingPayload struct {
+	NodeUUID string `json:"node_uuid"`
+	NodeIP   string `json:"node_ip"`
+	NodePort string `json:"node_port"`
+}
+
+// enterTask 记录一个正在执行的任务，服务器正在排空时返回 false（不执行任务）
+// 先计数再检查排空标志，保证 Drain 看到排空标志之前开始的任务都已计入
+func (s *Server) enterTask() bool {
+	s.inFlight.Add(1)
+	if s.draining.Load() {
+		s.inFlight.Add(-1)
+		return false
+	}
+	return true
+}
+
+// exitTask 任务执行结束
+func (s *Server) exitTask() {
+	s.inFlight.Add(-1)
+}
+
+// Drain 排空服务器：健康检查返回 NOT_SERVING，从节点列表中移除自己并通知其他节点，
+// 拒绝新任务（TASK_ERROR_CODE_SERVER_DRAINING，可提交到其他节点重试），
+// 然后等待正在执行的任务完成、瓦片存储的异步持久化队列写完，直到 ctx 结束
+// 排空后仍需调用 Stop 停止服务器
+func (s *Server) Drain(ctx context.Context) error {
+	if !s.draining.CompareAndSwap(false, true) {
+		return nil
+	}
+	start := time.Now()
+	s.logger.Info("开始排空服务器，正在执行的任务数: %d", s.inFlight.Load())
+
+	if s.healthServer != nil {
+		s.healthServer.Shutdown()
+	}
+	s.leaveCluster()
+	s.wakeJobs()
+
+	if err := s.waitInFlightTasks(ctx); err != nil {
+		return err
+	}
+	if s.tileStorage != nil {
+		if err := s.tileStorage.Flush(ctx); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("服务器排空完成，耗时 %v", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// waitInFlightTasks 等待正在执行的任务（包括作业中的任务）全部完成
+func (s *Server) waitInFlightTasks(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		remaining := s.inFlight.Load()
+		if remaining <= 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("等待 %d 个正在执行的任务完成超时: %w", remaining, ctx.Err())
+		}
+	}
+}
+
+// leaveCluster 从节点列表中移除自己，停止节点心跳，并通知所有已连接的节点
+// 客户端在下次心跳时（向本节点或其他节点）通过 removed_server_nodes 得知本节点已离开
+func (s *Server) leaveCluster() {
+	selfAddr := s.getSelfAddr()
+	payload := nodeLeavingPayload{NodeUUID: s.nodeID, NodeIP: s.address, NodePort: s.port}
+	s.nodesMu.Lock()
+	if self, ok := s.nodes[selfAddr]; ok {
+		payload.NodeIP = self.NodeIp
+		delete(s.nodes, selfAddr)
+	}
+	delete(s.nodeRegisterTimes, selfAddr)
+	s.nodesMu.Unlock()
+
+	if s.nodeConnector == nil {
+		return
+	}
+	s.nodeConnector.Leave()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		s.logger.Warn("编码节点离开通知失败: %v", err)
+		return
+	}
+	connectedNodes := s.nodeConnector.GetConnectedNodes()
+	var wg sync.WaitGroup
+	for nodeAddr, client := range connectedNodes {
+		wg.Add(1)
+		go func(addr string, c tasksmanager.TasksManagerClient) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), nodeLeavingNotifyTimeout)
+			defer cancel()
+
+			msg := &tasksmanager.NodeMessage{
+				MessageId:    fmt.Sprintf("node-leaving-%s-%d", s.nodeID, time.Now().Unix()),
+				FromNodeUuid: s.nodeID,
+				MessageType:  nodeLeavingMessageType,
+				Payload:      data,
+				Timestamp:    time.Now().UnixMilli(),
+			}
+			if _, err := c.SendNodeMessage(ctx, &tasksmanager.NodeMessageRequest{Message: msg}); err != nil {
+				s.logger.Warn("通知节点 %s 本节点离开失败: %v", addr, err)
+				return
+			}
+			s.logger.Debug("已通知节点 %s 本节点离开", addr)
+		}(nodeAddr, client)
+	}
+	wg.Wait()
+	s.logger.Info("已通知 %d 个节点本节点离开", len(connectedNodes))
+}
+
+// removeLeavingNode 处理其他节点的离开通知：从节点列表中移除该节点并断开连接
+func (s *Server) removeLeavingNode(msg *tasksmanager.NodeMessage) {
+	var payload nodeLeavingPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		s.logger.Warn("解析节点离开通知失败: %v", err)
+		return
+	}
+	nodeAddr := s.getNodeAddr(&tasksmanager.GrpcServerNodeInfo{NodeIp: payload.NodeIP, NodePort: payload.NodePort})
+	if nodeAddr == s.getSelfAddr() {
+		return
+	}
+
+	s.nodesMu.Lock()
+	node, exists := s.nodes[nodeAddr]
+	// 同一地址上已经是重启后的新节点时不移除
+	if exists && node.NodeUuid != payload.NodeUUID {
+		s.nodesMu.Unlock()
+		return
+	}
+	delete(s.nodes, nodeAddr)
+	delete(s.nodeRegisterTimes, nodeAddr)
+	s.nodesMu.Unlock()
+
+	if s.nodeConnector != nil {
+		s.nodeConnector.DisconnectNode(nodeAddr)
+	}
+	s.logger.Info("节点已离开: %s (UUID: %s)", nodeAddr, payload.NodeUUID)
+	if exists {
+		s.publishNodeEvent(nodeAddr, payload.NodeUUID, "left")
+	}
+}
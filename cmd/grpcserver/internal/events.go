@@ -0,0 +1,346 @@
+package grpcserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+	"crawler-platform/utlsclient"
+)
+
+const (
+	// eventSubscriberBuffer 每个订阅者的事件缓冲区大小（缓冲区已满时丢弃新事件）
+	eventSubscriberBuffer = 256
+	// eventPoolWatchInterval 热连接池状态的采样间隔（有订阅者时运行）
+	eventPoolWatchInterval = time.Second
+	// eventMetricsInterval 向每个订阅者推送指标快照的间隔
+	eventMetricsInterval = 5 * time.Second
+	// eventKeepAliveInterval SSE 注释心跳间隔（避免代理断开空闲连接）
+	eventKeepAliveInterval = 15 * time.Second
+)
+
+// 推送事件类型
+const (
+	eventTypeTask    = "task"    // SubmitTask 完成
+	eventTypeJob     = "job"     // 作业状态变更（仪表盘任务格式）
+	eventTypePool    = "pool"    // 热连接池中 IP 的状态变化
+	eventTypeNode    = "node"    // 服务器节点加入、离线、恢复或离开
+	eventTypeMetrics = "metrics" // 定期指标快照
+)
+
+// adminEvent 推送给仪表盘的事件
+type adminEvent struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data"`
+}
+
+// taskEvent SubmitTask 完成事件
+type taskEvent struct {
+	TaskType   string `json:"taskType"`
+	TileKey    string `json:"tileKey"`
+	StatusCode int32  `json:"statusCode"`
+	Source     string `json:"source"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// poolEvent 热连接池中 IP 的状态变化
+type poolEvent struct {
+	IP    string `json:"ip"`
+	Host  string `json:"host,omitempty"`
+	State string `json:"state"` // added / removed / healthy / unhealthy / blacklisted / unblacklisted
+}
+
+// nodeEvent 服务器节点事件
+type nodeEvent struct {
+	Address  string `json:"address"`
+	NodeUUID string `json:"nodeUuid"`
+	Action   string `json:"action"` // joined / offline / online / left
+}
+
+// metricsEvent 定期指标快照
+type metricsEvent struct {
+	Pool        *adminPoolStats `json:"pool,omitempty"`
+	Scheduler   json.RawMessage `json:"scheduler"` // SchedulerStats（protojson 格式）
+	HotCache    json.RawMessage `json:"hotCache"`  // CacheStats（protojson 格式）
+	Nodes       int             `json:"nodes"`
+	Clients     int             `json:"clients"`
+	RunningJobs int             `json:"runningJobs"`
+}
+
+// eventHub 推送事件的订阅管理（订阅者处理过慢时丢弃事件，不阻塞发布方）
+type eventHub struct {
+	mu          sync.Mutex
+	subscribers map[chan adminEvent]struct{}
+	closed      bool
+	count       atomic.Int32 // 订阅者数量（发布方据此跳过无人订阅的事件）
+	dropped     atomic.Int64 // 因缓冲区已满被丢弃的事件数
+}
+
+// subscribe 添加订阅者，返回的通道在 unsubscribe 或 close 时关闭
+func (h *eventHub) subscribe() chan adminEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := make(chan adminEvent, eventSubscriberBuffer)
+	if h.closed {
+		close(ch)
+		return ch
+	}
+	if h.subscribers == nil {
+		h.subscribers = make(map[chan adminEvent]struct{})
+	}
+	h.subscribers[ch] = struct{}{}
+	h.count.Store(int32(len(h.subscribers)))
+	return ch
+}
+
+// unsubscribe 移除订阅者
+func (h *eventHub) unsubscribe(ch chan adminEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[ch]; ok {
+		delete(h.subscribers, ch)
+		close(ch)
+		h.count.Store(int32(len(h.subscribers)))
+	}
+}
+
+// active 是否有订阅者
+func (h *eventHub) active() bool {
+	return h.count.Load() > 0
+}
+
+// publish 向所有订阅者发布事件
+func (h *eventHub) publish(eventType string, data any) {
+	if !h.active() {
+		return
+	}
+	ev := adminEvent{Type: eventType, Time: time.Now(), Data: data}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			h.dropped.Add(1)
+		}
+	}
+}
+
+// close 关闭所有订阅（HTTP 服务器关闭时调用，使 SSE 连接退出）
+func (h *eventHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for ch := range h.subscribers {
+		close(ch)
+	}
+	h.subscribers = nil
+	h.count.Store(0)
+}
+
+// publishTaskEvent 发布 SubmitTask 完成事件
+func (s *Server) publishTaskEvent(req *tasksmanager.TaskRequest, resp *tasksmanager.TaskResponse, err error, duration time.Duration) {
+	if !s.events.active() {
+		return
+	}
+	if err != nil {
+		resp = taskResponseFromError(err)
+	}
+	ev := taskEvent{
+		TaskType:   req.GetTaskType().String(),
+		TileKey:    req.GetTileKey(),
+		StatusCode: resp.GetTaskResponseStatusCode(),
+		Source:     resp.GetResponseSource().String(),
+		DurationMs: duration.Milliseconds(),
+	}
+	if err != nil {
+		ev.Source = "ERROR"
+		ev.Error = err.Error()
+	}
+	s.events.publish(eventTypeTask, ev)
+}
+
+// publishJobEvent 发布作业状态变更事件
+func (s *Server) publishJobEvent(info *tasksmanager.JobInfo) {
+	s.events.publish(eventTypeJob, adminTaskFromJob(info))
+}
+
+// publishNodeEvent 发布服务器节点事件
+func (s *Server) publishNodeEvent(address, nodeUUID, action string) {
+	s.events.publish(eventTypeNode, nodeEvent{Address: address, NodeUUID: nodeUUID, Action: action})
+}
+
+// poolState 热连接池状态采样（IP -> 连接状态，黑名单 IP 集合）
+type poolState struct {
+	conns     map[string]utlsclient.ConnectionInfo
+	blacklist map[string]bool
+}
+
+// samplePoolState 采样热连接池当前状态
+func samplePoolState(client *utlsclient.Client) poolState {
+	state := poolState{conns: make(map[string]utlsclient.ConnectionInfo), blacklist: make(map[string]bool)}
+	for _, conn := range client.Connections() {
+		state.conns[conn.IP] = conn
+	}
+	for _, ip := range client.BlacklistedIPs() {
+		state.blacklist[ip] = true
+	}
+	return state
+}
+
+// startPoolWatcher 启动热连接池状态采样（已在运行时不重复启动）
+func (s *Server) startPoolWatcher() {
+	if s.utlsClient != nil && s.poolWatching.CompareAndSwap(false, true) {
+		go s.watchPool()
+	}
+}
+
+// watchPool 有订阅者时定期采样热连接池状态，发布连接增删、健康状态与黑名单变化（最后一个订阅者离开后退出）
+func (s *Server) watchPool() {
+	ticker := time.NewTicker(eventPoolWatchInterval)
+	defer ticker.Stop()
+
+	prev := samplePoolState(s.utlsClient)
+	for range ticker.C {
+		if !s.events.active() {
+			// 退出前再次检查，避免与新订阅者的 startPoolWatcher 竞争导致没有采样协程
+			s.poolWatching.Store(false)
+			if !s.events.active() || !s.poolWatching.CompareAndSwap(false, true) {
+				return
+			}
+		}
+		cur := samplePoolState(s.utlsClient)
+		for ip, conn := range cur.conns {
+			old, existed := prev.conns[ip]
+			switch {
+			case !existed:
+				s.events.publish(eventTypePool, poolEvent{IP: ip, Host: conn.Host, State: "added"})
+			case old.Healthy && !conn.Healthy:
+				s.events.publish(eventTypePool, poolEvent{IP: ip, Host: conn.Host, State: "unhealthy"})
+			case !old.Healthy && conn.Healthy:
+				s.events.publish(eventTypePool, poolEvent{IP: ip, Host: conn.Host, State: "healthy"})
+			}
+		}
+		for ip, conn := range prev.conns {
+			if _, ok := cur.conns[ip]; !ok {
+				s.events.publish(eventTypePool, poolEvent{IP: ip, Host: conn.Host, State: "removed"})
+			}
+		}
+		for ip := range cur.blacklist {
+			if !prev.blacklist[ip] {
+				s.events.publish(eventTypePool, poolEvent{IP: ip, State: "blacklisted"})
+			}
+		}
+		for ip := range prev.blacklist {
+			if !cur.blacklist[ip] {
+				s.events.publish(eventTypePool, poolEvent{IP: ip, State: "unblacklisted"})
+			}
+		}
+		prev = cur
+	}
+}
+
+// metricsSnapshot 返回当前指标快照
+func (s *Server) metricsSnapshot() metricsEvent {
+	var snapshot metricsEvent
+	snapshot.Scheduler, _ = adminProtoJSON(s.scheduler.stats())
+	snapshot.HotCache, _ = adminProtoJSON(s.hotCache.stats())
+	if stats, ok := s.poolStats(); ok {
+		snapshot.Pool = &stats
+	}
+	s.nodesMu.RLock()
+	snapshot.Nodes = len(s.nodes)
+	s.nodesMu.RUnlock()
+	s.clientsMu.RLock()
+	snapshot.Clients = len(s.clients)
+	s.clientsMu.RUnlock()
+	s.jobsMu.RLock()
+	for _, j := range s.jobs {
+		j.mu.Lock()
+		if j.info.Status == tasksmanager.TasksStatus_TASKS_STATUS_RUNNING {
+			snapshot.RunningJobs++
+		}
+		j.mu.Unlock()
+	}
+	s.jobsMu.RUnlock()
+	return snapshot
+}
+
+// handleAdminEvents 以 Server-Sent Events 推送任务、作业、热连接池、节点事件与定期指标快照
+// 可通过 ?types=task,pool 只订阅部分事件类型
+func (s *Server) handleAdminEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAdminError(w, fmt.Errorf("HTTP 连接不支持流式响应"))
+		return
+	}
+	var types map[string]bool
+	if filter := r.URL.Query().Get("types"); filter != "" {
+		types = make(map[string]bool)
+		for _, t := range strings.Split(filter, ",") {
+			types[strings.TrimSpace(t)] = true
+		}
+	}
+
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+	s.startPoolWatcher()
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(ev adminEvent) error {
+		if types != nil && !types[ev.Type] {
+			return nil
+		}
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	metricsTicker := time.NewTicker(eventMetricsInterval)
+	defer metricsTicker.Stop()
+	keepAlive := time.NewTicker(eventKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	if err := send(adminEvent{Type: eventTypeMetrics, Time: time.Now(), Data: s.metricsSnapshot()}); err != nil {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := send(ev); err != nil {
+				return
+			}
+		case <-metricsTicker.C:
+			if err := send(adminEvent{Type: eventTypeMetrics, Time: time.Now(), Data: s.metricsSnapshot()}); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultForwardMaxHops 任务在服务器节点间转发的默认最大跳数
+	defaultForwardMaxHops = 2
+	// forwardTimeout 转发到其他节点的任务的超时时间
+	forwardTimeout = 60 * time.Second
+)
+
+// SetForwardMaxHops 设置任务在服务器节点间转发的最大跳数（<=0 时不转发）
+func (s *Server) SetForwardMaxHops(maxHops int) {
+	if maxHops < 0 {
+		maxHops = 0
+	}
+	s.forwardMaxHops = maxHops
+}
+
+// isForwardableError 判断任务执行错误是否应转发到其他节点（本节点热连接池饱和、超过速率限制、等待队列已满或没有该主机的可用连接）
+func isForwardableError(err error) bool {
+	switch taskErrorCode(err) {
+	case tasksmanager.TaskErrorCode_TASK_ERROR_CODE_POOL_WARMING,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_ALL_CONNECTIONS_BUSY,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_RATE_LIMITED,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_QUEUE_FULL,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_ALL_CONNECTIONS_UNHEALTHY,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_NO_AVAILABLE_CONNECTION:
+		return true
+	default:
+		return false
+	}
+}
+
+// nodeLoadScore 计算节点负载分数（越小负载越低）
+// CPU 使用率与内存使用率为百分比，网络带宽按候选节点中的最大值归一化到 0-100
+func nodeLoadScore(node *tasksmanager.GrpcServerNodeInfo, maxNetwork float64) float64 {
+	score := node.GetCpuUsagePercent()
+	if node.GetMemoryTotalBytes() > 0 {
+		score += float64(node.GetMemoryUsedBytes()) * 100 / float64(node.GetMemoryTotalBytes())
+	}
+	if maxNetwork > 0 {
+		score += (node.GetNetworkRxBytesPerSec() + node.GetNetworkTxBytesPerSec()) * 100 / maxNetwork
+	}
+	return score
+}
+
+// pickForwardPeer 选择负载最低的已连接服务器节点（排除转发路径中已经过的节点）
+func (s *Server) pickForwardPeer(visited map[string]bool) (*tasksmanager.GrpcServerNodeInfo, tasksmanager.TasksManagerClient) {
+	if s.nodeConnector == nil {
+		return nil, nil
+	}
+	connected := s.nodeConnector.GetConnectedNodes()
+
+	type candidate struct {
+		node   *tasksmanager.GrpcServerNodeInfo
+		client tasksmanager.TasksManagerClient
+	}
+	var candidates []candidate
+	maxNetwork := 0.0
+	s.nodesMu.RLock()
+	for nodeAddr, node := range s.nodes {
+		client, ok := connected[nodeAddr]
+		if !ok || node.NodeUuid == s.nodeID || visited[node.NodeUuid] || !s.isServerNode(node.NodeUuid) {
+			continue
+		}
+		candidates = append(candidates, candidate{node: proto.Clone(node).(*tasksmanager.GrpcServerNodeInfo), client: client})
+		maxNetwork = max(maxNetwork, node.GetNetworkRxBytesPerSec()+node.GetNetworkTxBytesPerSec())
+	}
+	s.nodesMu.RUnlock()
+
+	var best *candidate
+	bestScore := 0.0
+	for i := range candidates {
+		score := nodeLoadScore(candidates[i].node, maxNetwork)
+		if best == nil || score < bestScore {
+			best, bestScore = &candidates[i], score
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	return best.node, best.client
+}
+
+// forwardTask 将任务转发到负载最低的其他服务器节点执行，返回对端的原始响应体（不解码）与上游响应头
+// 返回 ok=false 表示没有转发（已达到最大跳数、没有可用节点或对端未执行任务），调用方应返回本地的执行错误
+func (s *Server) forwardTask(ctx context.Context, req *tasksmanager.TaskRequest) (body []byte, statusCode int32, headers []*tasksmanager.TaskHeader, err error, ok bool) {
+	if len(req.ForwardPath) >= s.forwardMaxHops {
+		return nil, 0, nil, nil, false
+	}
+	visited := make(map[string]bool, len(req.ForwardPath)+1)
+	visited[s.nodeID] = true
+	for _, nodeUUID := range req.ForwardPath {
+		visited[nodeUUID] = true
+	}
+	node, client := s.pickForwardPeer(visited)
+	if node == nil {
+		return nil, 0, nil, nil, false
+	}
+
+	// 对端返回原始数据，由本节点解码并写入存储
+	forwardReq := proto.Clone(req).(*tasksmanager.TaskRequest)
+	forwardReq.ForwardPath = append(forwardReq.ForwardPath, s.nodeID)
+	forwardReq.Decode = tasksmanager.TaskDecodeMode_TASK_DECODE_MODE_NONE
+
+	ctx, cancel := context.WithTimeout(ctx, forwardTimeout)
+	defer cancel()
+	resp, err := client.SubmitTask(ctx, forwardReq)
+	if err != nil {
+		s.logger.Debug("任务转发到节点 %s 失败: %v", node.NodeUuid, err)
+		// 对端未执行任务（连接失败、参数错误、对端正在停止等）时视为没有转发，返回本地的执行错误
+		if peerResponse := taskResponseFromError(err); peerResponse == nil ||
+			peerResponse.GetErrorCode() == tasksmanager.TaskErrorCode_TASK_ERROR_CODE_SERVER_DRAINING {
+			return nil, 0, nil, nil, false
+		}
+		return nil, 0, nil, fmt.Errorf("转发到节点 %s 失败: %w", node.NodeUuid, err), true
+	}
+	s.logger.Debug("任务已转发到节点 %s 执行: %s", node.NodeUuid, req.TileKey)
+	statusCode = int32(http.StatusOK)
+	if resp.TaskResponseStatusCode != nil {
+		statusCode = resp.GetTaskResponseStatusCode()
+	}
+	return resp.GetTaskResponseBody(), statusCode, resp.GetResponseHeaders(), nil, true
+}
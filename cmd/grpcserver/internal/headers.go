@@ -0,0 +1,87 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// hopByHopHeaders 由连接本身决定的请求头与响应头，任务不能设置，也不返回给客户端
+var hopByHopHeaders = map[string]bool{
+	"Connection":          true,
+	"Content-Length":      true,
+	"Host":                true,
+	"Keep-Alive":          true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Proxy-Connection":    true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+}
+
+// ValidateAllowedRequestHeaders 检查请求头允许列表（名称必须合法，且不能包含 Host、Content-Length 等逐跳头）
+func ValidateAllowedRequestHeaders(names []string) error {
+	for _, name := range names {
+		if !httpguts.ValidHeaderFieldName(name) || hopByHopHeaders[http.CanonicalHeaderKey(name)] {
+			return fmt.Errorf("不能允许请求头 %q", name)
+		}
+	}
+	return nil
+}
+
+// SetAllowedRequestHeaders 设置任务可以附加的请求头（名称不区分大小写，为空时不允许附加请求头）
+func (s *Server) SetAllowedRequestHeaders(names []string) error {
+	if err := ValidateAllowedRequestHeaders(names); err != nil {
+		return err
+	}
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[http.CanonicalHeaderKey(name)] = true
+	}
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.allowedRequestHeaders = allowed
+	return nil
+}
+
+// validateRequestHeaders 校验任务附加的请求头（必须在允许列表中，名称与值必须是合法的 HTTP 头）
+func (s *Server) validateRequestHeaders(req *tasksmanager.TaskRequest) error {
+	if len(req.GetRequestHeaders()) == 0 {
+		return nil
+	}
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	for name, value := range req.GetRequestHeaders() {
+		if !s.allowedRequestHeaders[http.CanonicalHeaderKey(name)] {
+			return fmt.Errorf("不允许的请求头: %q", name)
+		}
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf("无效的请求头: %q", name)
+		}
+	}
+	return nil
+}
+
+// taskResponseHeaders 将上游响应头转换为任务响应头（跳过逐跳头，按名称排序）
+func taskResponseHeaders(header http.Header) []*tasksmanager.TaskHeader {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if !hopByHopHeaders[http.CanonicalHeaderKey(name)] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	headers := make([]*tasksmanager.TaskHeader, 0, len(names))
+	for _, name := range names {
+		for _, value := range header[name] {
+			headers = append(headers, &tasksmanager.TaskHeader{Name: name, Value: value})
+		}
+	}
+	return headers
+}
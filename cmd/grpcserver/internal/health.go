@@ -0,0 +1,121 @@
+package grpcserver
+
+import (
+	"fmt"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckInterval 健康状态的重新评估间隔
+const healthCheckInterval = 5 * time.Second
+
+// SetIPPoolTarget 设置本地 IP 池的目标地址数（健康检查要求活跃 IPv6 地址数达到目标后才返回 SERVING，<=0 表示不检查）
+func (s *Server) SetIPPoolTarget(count int) {
+	s.ipPoolTarget.Store(int64(count))
+}
+
+// readiness 评估服务器是否可以执行任务，不可以时返回原因
+func (s *Server) readiness() (bool, string) {
+	if s.draining.Load() {
+		return false, "服务器正在排空"
+	}
+	if !s.IsUTLSClientReady() {
+		return false, "UTLS 热连接池未初始化"
+	}
+	if target := s.ipPoolTarget.Load(); target > 0 && s.ipPool != nil {
+		if active := len(s.ipPool.GetActiveIPv6Addresses()); int64(active) < target {
+			return false, fmt.Sprintf("本地 IP 池未达到目标数量 (%d/%d)", active, target)
+		}
+	}
+
+	hosts := s.configuredHosts()
+	if len(hosts) == 0 {
+		return true, ""
+	}
+	hostStats := s.utlsClient.HostStats()
+	for _, host := range hosts {
+		if hostStats[host].Healthy > 0 {
+			return true, ""
+		}
+	}
+	return false, fmt.Sprintf("主机 %v 没有健康的热连接", hosts)
+}
+
+// configuredHosts 返回已启用的数据类型与通用站点对应的上游主机
+func (s *Server) configuredHosts() []string {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
+	var hosts []string
+	add := func(host string) {
+		for _, h := range hosts {
+			if h == host {
+				return
+			}
+		}
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	if s.rockTreeDataEnable {
+		add(s.rockTreeDataHostName)
+	}
+	if s.googleEarthDesktopDataEnable {
+		add(s.googleEarthDesktopDataHostName)
+		add(s.googleEarthTMHostName)
+	}
+	for _, site := range s.sites {
+		add(site.HostName)
+	}
+	return hosts
+}
+
+// updateHealth 重新评估并更新 gRPC 健康状态（整体状态与 TasksManager 服务状态相同）
+func (s *Server) updateHealth() {
+	ready, reason := s.readiness()
+	servingStatus := healthpb.HealthCheckResponse_NOT_SERVING
+	if ready {
+		servingStatus = healthpb.HealthCheckResponse_SERVING
+	}
+
+	s.healthMu.Lock()
+	changed := s.healthStatus != servingStatus
+	s.healthStatus = servingStatus
+	s.healthMu.Unlock()
+
+	s.healthServer.SetServingStatus("", servingStatus)
+	s.healthServer.SetServingStatus(tasksmanager.TasksManager_ServiceDesc.ServiceName, servingStatus)
+	if changed {
+		if ready {
+			s.logger.Info("gRPC 健康状态: SERVING")
+		} else {
+			s.logger.Warn("gRPC 健康状态: NOT_SERVING（%s）", reason)
+		}
+	}
+}
+
+// runHealthChecker 定期更新 gRPC 健康状态，服务器开始停止后退出
+func (s *Server) runHealthChecker() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	s.updateHealth()
+	for range ticker.C {
+		if s.draining.Load() {
+			return
+		}
+		s.updateHealth()
+	}
+}
+
+// newHealthServer 创建初始为 NOT_SERVING 的健康检查服务
+func newHealthServer() *health.Server {
+	h := health.NewServer()
+	h.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	h.SetServingStatus(tasksmanager.TasksManager_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
@@ -0,0 +1,216 @@
+package grpcserver
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+)
+
+// defaultHotCacheMaxBytes 热点瓦片缓存默认容量（256 MiB）
+const defaultHotCacheMaxBytes = 256 << 20
+
+// hotCacheEntry 热点瓦片缓存条目（上游成功返回的原始响应体）
+type hotCacheEntry struct {
+	key  string
+	body []byte
+}
+
+// hotCache 按字节数限制容量的 LRU 缓存，保存最近成功的上游响应
+type hotCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	bytes    int64
+	ll       *list.List               // 最近使用的条目在前
+	items    map[string]*list.Element // key -> 链表元素
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// newHotCache 创建热点瓦片缓存（maxBytes<=0 时不缓存任何数据）
+func newHotCache(maxBytes int64) *hotCache {
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	return &hotCache{maxBytes: maxBytes, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+// get 读取缓存（返回的切片只读）
+func (c *hotCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxBytes <= 0 {
+		return nil, false
+	}
+	if elem, ok := c.items[key]; ok {
+		c.ll.MoveToFront(elem)
+		c.hits++
+		return elem.Value.(*hotCacheEntry).body, true
+	}
+	c.misses++
+	return nil, false
+}
+
+// put 写入缓存，超出容量时淘汰最久未使用的条目（超过总容量的单个响应不缓存）
+func (c *hotCache) put(key string, body []byte) {
+	size := int64(len(body))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if size == 0 || size > c.maxBytes {
+		return
+	}
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*hotCacheEntry)
+		c.bytes += size - int64(len(entry.body))
+		entry.body = body
+		c.ll.MoveToFront(elem)
+	} else {
+		c.items[key] = c.ll.PushFront(&hotCacheEntry{key: key, body: body})
+		c.bytes += size
+	}
+	c.evictLocked()
+}
+
+// setMaxBytes 修改缓存容量（缩小时立即淘汰多余条目）
+func (c *hotCache) setMaxBytes(maxBytes int64) {
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxBytes = maxBytes
+	c.evictLocked()
+}
+
+// evictLocked 淘汰最久未使用的条目直到不超过容量（调用方持有 mu）
+func (c *hotCache) evictLocked() {
+	for c.bytes > c.maxBytes && c.ll.Len() > 0 {
+		elem := c.ll.Back()
+		entry := elem.Value.(*hotCacheEntry)
+		c.ll.Remove(elem)
+		delete(c.items, entry.key)
+		c.bytes -= int64(len(entry.body))
+		c.evictions++
+	}
+}
+
+// stats 返回缓存统计信息（不含相同任务合并次数）
+func (c *hotCache) stats() *tasksmanager.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &tasksmanager.CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   int64(c.ll.Len()),
+		Bytes:     c.bytes,
+		MaxBytes:  c.maxBytes,
+	}
+}
+
+// flightCall 正在执行的上游请求，相同任务的调用方等待其结果
+type flightCall struct {
+	done       chan struct{}
+	body       []byte
+	statusCode int32
+	headers    []*tasksmanager.TaskHeader
+	err        error
+	waiters    int                // 仍在等待结果的调用方数量（受 flightGroup.mu 保护）
+	cancel     context.CancelFunc // 取消共享的上游请求
+}
+
+// flightGroup 合并相同的并发上游请求：同一 key 同时只执行一次，其他调用方共享结果
+type flightGroup struct {
+	mu        sync.Mutex
+	calls     map[string]*flightCall
+	coalesced int64 // 共享其他调用方结果的次数
+}
+
+// do 执行 fn 或等待正在执行的相同请求，返回结果以及结果是否来自其他调用方（共享的结果不含上游响应头）
+// fn 在独立的 goroutine 中执行，使用不随调用方取消、最长 timeout 的上下文；
+// 每个调用方（包括发起请求的调用方）在 ctx 取消或超时时提前返回上下文错误，最后一个调用方离开时取消上游请求
+// 返回的响应体在调用方之间共享，只能读取
+func (g *flightGroup) do(ctx context.Context, key string, timeout time.Duration, fn func(ctx context.Context) ([]byte, int32, []*tasksmanager.TaskHeader, error)) ([]byte, int32, []*tasksmanager.TaskHeader, error, bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, shared := g.calls[key]
+	if shared {
+		call.waiters++
+		atomic.AddInt64(&g.coalesced, 1)
+	} else {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		call = &flightCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = call
+		go g.run(fetchCtx, key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if shared {
+			return call.body, call.statusCode, nil, call.err, true
+		}
+		return call.body, call.statusCode, call.headers, call.err, false
+	case <-ctx.Done():
+		g.leave(key, call)
+		return nil, 0, nil, fmt.Errorf("等待相同任务的结果已取消: %w", ctx.Err()), shared
+	}
+}
+
+// run 执行共享的上游请求并通知等待的调用方
+func (g *flightGroup) run(ctx context.Context, key string, call *flightCall, fn func(ctx context.Context) ([]byte, int32, []*tasksmanager.TaskHeader, error)) {
+	defer call.cancel()
+	call.body, call.statusCode, call.headers, call.err = fn(ctx)
+
+	g.mu.Lock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+	close(call.done)
+}
+
+// leave 调用方不再等待结果；最后一个调用方离开时取消上游请求，之后的相同任务重新发起请求
+func (g *flightGroup) leave(key string, call *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	call.cancel()
+}
+
+// SetHotCacheMaxBytes 设置热点瓦片缓存容量（字节，<=0 时不缓存）
+func (s *Server) SetHotCacheMaxBytes(maxBytes int64) {
+	s.hotCache.setMaxBytes(maxBytes)
+}
+
+// upstreamKeyForTask 返回任务的上游请求键（任务类型 + 主机 + 路径，路径已包含瓦片键、epoch、imageryEpoch 等参数）
+// 返回 ok=false 表示该任务不参与合并与缓存（带请求体或非 GET 方法的请求不是幂等读取，附加了请求头的请求响应取决于请求头，
+// 设置了响应体大小限制的请求可能因限制失败，不能与其他任务共享结果）
+func upstreamKeyForTask(req *tasksmanager.TaskRequest, hostName, path string) (string, bool) {
+	if len(req.TaskBody) > 0 || len(req.GetRequestHeaders()) > 0 || req.GetMaxBodyBytes() > 0 ||
+		req.GetTaskMethod() != tasksmanager.TaskMethod_TASK_METHOD_GET {
+		return "", false
+	}
+	return req.TaskType.String() + "|" + hostName + path, true
+}
+
+// GetCacheStats 获取热点瓦片缓存统计
+func (s *Server) GetCacheStats(ctx context.Context, req *tasksmanager.CacheStatsRequest) (*tasksmanager.CacheStats, error) {
+	stats := s.hotCache.stats()
+	stats.Coalesced = atomic.LoadInt64(&s.flights.coalesced)
+	return stats, nil
+}
@@ -0,0 +1,67 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StartHTTPServer 启动 HTTP 服务器，监听失败时返回错误
+// metrics 为 true 时提供 /metrics 指标导出，admin 为 true 时提供 /api 管理接口与仪表盘静态文件
+// 可以多次调用以在不同地址上分别提供指标与管理接口
+func (s *Server) StartHTTPServer(address string, metrics, admin bool) error {
+	mux := http.NewServeMux()
+	var routes []string
+	if metrics {
+		mux.HandleFunc("GET /metrics", s.handleMetrics)
+		routes = append(routes, "指标: /metrics")
+	}
+	if admin {
+		s.registerAdminRoutes(mux)
+		routes = append(routes, "管理接口: /api")
+		if s.adminWebDir != "" {
+			routes = append(routes, "仪表盘: "+s.adminWebDir)
+		}
+	}
+
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("HTTP 服务器监听失败: %w", err)
+	}
+	httpServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// 关闭时结束所有事件推送连接（否则 Shutdown 会一直等待 SSE 连接）
+	httpServer.RegisterOnShutdown(s.events.close)
+	s.httpServersMu.Lock()
+	s.httpServers = append(s.httpServers, httpServer)
+	s.httpServersMu.Unlock()
+	go func() {
+		if err := httpServer.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Warn("HTTP 服务器异常退出: %v", err)
+		}
+	}()
+	s.logger.Info("HTTP 服务器启动在 %s（%s）", lis.Addr(), strings.Join(routes, ", "))
+	return nil
+}
+
+// StopHTTPServer 停止所有 HTTP 服务器（最多等待 5 秒）
+func (s *Server) StopHTTPServer() {
+	s.httpServersMu.Lock()
+	servers := s.httpServers
+	s.httpServers = nil
+	s.httpServersMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, httpServer := range servers {
+		if err := httpServer.Shutdown(ctx); err != nil {
+			s.logger.Warn("停止 HTTP 服务器时出错: %v", err)
+		}
+	}
+}
@@ -0,0 +1,600 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultJobConcurrency 单个作业默认同时执行的任务数
+	defaultJobConcurrency = 16
+	// jobSaveInterval 作业运行期间持久化状态的间隔
+	jobSaveInterval = 5 * time.Second
+)
+
+// job 服务器后台执行的作业
+// 任务来自 jobSource（任务列表或区域枚举），作业只维护整体状态和计数
+type job struct {
+	mu     sync.Mutex
+	cond   *sync.Cond // 作业状态变化（暂停/恢复/取消）或任务完成时唤醒等待中的 worker
+	saveMu sync.Mutex // 串行化状态文件写入
+	info   *tasksmanager.JobInfo
+	source jobSource
+	dirty  bool // 是否有未持久化的状态变化
+}
+
+// jobSource 作业任务来源
+// 所有方法都在持有 job.mu 时调用
+type jobSource interface {
+	// next 领取下一个待执行的任务，没有剩余任务时返回 false
+	next() (id uint64, task *tasksmanager.TaskRequest, ok bool)
+	// done 记录任务执行结果，discovered 为执行过程中新发现的任务（仅 jobExecutor 会产生）
+	done(id uint64, taskStatus tasksmanager.TaskStatus, discovered []*tasksmanager.TaskRequest)
+	// reset 将正在执行的任务重新置为等待中（从持久化状态恢复时调用）
+	reset()
+	// save 将任务进度写入持久化状态
+	save(state *jobState) error
+}
+
+// jobExecutor 可选接口：自定义任务的执行方式（在 job.mu 之外调用）
+// 返回任务最终状态和执行过程中新发现的任务
+type jobExecutor interface {
+	execute(s *Server, task *tasksmanager.TaskRequest) (tasksmanager.TaskStatus, []*tasksmanager.TaskRequest)
+}
+
+// jobState 作业持久化格式（protojson 编码的 JobInfo 与任务来源的进度）
+type jobState struct {
+	Info json.RawMessage `json:"info"`
+
+	// 任务列表作业：每个任务的执行状态记录在 TaskRequest.TaskStatus 中
+	Tasks []json.RawMessage `json:"tasks,omitempty"`
+
+	// 区域作业：作业参数、下一个未领取的任务序号和需要重新执行的任务序号
+	Region json.RawMessage `json:"region,omitempty"`
+	Cursor uint64          `json:"cursor,omitempty"`
+	Retry  []uint64        `json:"retry,omitempty"`
+
+	// Q2 发现作业：作业参数和待执行的任务（前沿）
+	Discovery json.RawMessage   `json:"discovery,omitempty"`
+	Frontier  []json.RawMessage `json:"frontier,omitempty"`
+}
+
+// newJob 创建作业（info 中的计数需与 source 的进度一致）
+func newJob(info *tasksmanager.JobInfo, source jobSource) *job {
+	j := &job{info: info, source: source}
+	j.cond = sync.NewCond(&j.mu)
+	return j
+}
+
+// counter 返回任务状态对应的作业计数字段（调用方需持有 j.mu）
+func (j *job) counter(taskStatus tasksmanager.TaskStatus) *int64 {
+	switch taskStatus {
+	case tasksmanager.TaskStatus_TASK_STATUS_RUNNING:
+		return &j.info.RunningCount
+	case tasksmanager.TaskStatus_TASK_STATUS_SUCCESS:
+		return &j.info.SuccessCount
+	case tasksmanager.TaskStatus_TASK_STATUS_FAILED:
+		return &j.info.FailedCount
+	default:
+		return &j.info.PendingCount
+	}
+}
+
+// moveCount 将一个任务的计数从 from 状态移到 to 状态（调用方需持有 j.mu）
+func (j *job) moveCount(from, to tasksmanager.TaskStatus) {
+	*j.counter(from)--
+	*j.counter(to)++
+	j.info.UpdateTime = time.Now().Format(time.RFC3339)
+	j.dirty = true
+}
+
+// snapshot 返回作业信息的副本
+func (j *job) snapshot() *tasksmanager.JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return proto.Clone(j.info).(*tasksmanager.JobInfo)
+}
+
+// acquireTask 领取下一个等待中的任务，领取成功时已通过 enterTask 计入正在执行的任务（执行完毕后调用 exitTask）
+// 作业暂停时阻塞等待；暂无等待中的任务但仍有任务在执行时（可能发现新任务）阻塞等待其完成；
+// 作业停止、服务器排空或等待中与执行中的任务都没有时返回 false
+// 阻塞等待期间不计入正在执行的任务，Drain 会唤醒等待中的 worker
+func (j *job) acquireTask(s *Server) (uint64, *tasksmanager.TaskRequest, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for {
+		// 服务器排空时不再领取新任务，剩余任务在下次启动时继续执行
+		if s.draining.Load() {
+			return 0, nil, false
+		}
+		switch j.info.Status {
+		case tasksmanager.TasksStatus_TASKS_STATUS_PAUSED:
+			j.cond.Wait()
+			continue
+		case tasksmanager.TasksStatus_TASKS_STATUS_RUNNING:
+		default:
+			return 0, nil, false
+		}
+
+		if !s.enterTask() {
+			return 0, nil, false
+		}
+		id, task, ok := j.source.next()
+		if !ok {
+			s.exitTask()
+			if j.info.RunningCount > 0 {
+				j.cond.Wait()
+				continue
+			}
+			return 0, nil, false
+		}
+		j.moveCount(tasksmanager.TaskStatus_TASK_STATUS_PENDING, tasksmanager.TaskStatus_TASK_STATUS_RUNNING)
+		j.info.CurrentTileKey = task.GetTileKey()
+		return id, task, true
+	}
+}
+
+// completeTask 记录任务执行结果，新发现的任务计入等待中，并唤醒等待领取任务的 worker
+func (j *job) completeTask(id uint64, taskStatus tasksmanager.TaskStatus, discovered []*tasksmanager.TaskRequest) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.source.done(id, taskStatus, discovered)
+	j.info.TotalCount += int64(len(discovered))
+	j.info.PendingCount += int64(len(discovered))
+	j.moveCount(tasksmanager.TaskStatus_TASK_STATUS_RUNNING, taskStatus)
+	j.cond.Broadcast()
+}
+
+// listJobSource 任务列表作业的任务来源（CreateJob 创建）
+type listJobSource struct {
+	tasks  []*tasksmanager.TaskRequest
+	cursor int // 下一个待检查的任务下标（之前的任务均已被领取）
+}
+
+func (l *listJobSource) next() (uint64, *tasksmanager.TaskRequest, bool) {
+	for ; l.cursor < len(l.tasks); l.cursor++ {
+		if l.tasks[l.cursor].GetTaskStatus() == tasksmanager.TaskStatus_TASK_STATUS_PENDING {
+			idx := l.cursor
+			l.cursor++
+			running := tasksmanager.TaskStatus_TASK_STATUS_RUNNING
+			l.tasks[idx].TaskStatus = &running
+			return uint64(idx), proto.Clone(l.tasks[idx]).(*tasksmanager.TaskRequest), true
+		}
+	}
+	return 0, nil, false
+}
+
+func (l *listJobSource) done(id uint64, taskStatus tasksmanager.TaskStatus, discovered []*tasksmanager.TaskRequest) {
+	l.tasks[id].TaskStatus = &taskStatus
+}
+
+func (l *listJobSource) reset() {
+	pending := tasksmanager.TaskStatus_TASK_STATUS_PENDING
+	for _, task := range l.tasks {
+		if task.GetTaskStatus() == tasksmanager.TaskStatus_TASK_STATUS_RUNNING {
+			task.TaskStatus = &pending
+		}
+	}
+	l.cursor = 0
+}
+
+func (l *listJobSource) save(state *jobState) error {
+	state.Tasks = make([]json.RawMessage, 0, len(l.tasks))
+	for _, task := range l.tasks {
+		raw, err := protojson.Marshal(task)
+		if err != nil {
+			return fmt.Errorf("序列化作业任务失败: %w", err)
+		}
+		state.Tasks = append(state.Tasks, raw)
+	}
+	return nil
+}
+
+// SetJobConfig 设置作业配置
+// stateDir 为空时作业状态不持久化；concurrency <= 0 时使用默认值
+func (s *Server) SetJobConfig(stateDir string, concurrency int) {
+	if concurrency <= 0 {
+		concurrency = defaultJobConcurrency
+	}
+	s.jobStateDir = stateDir
+	s.jobConcurrency = concurrency
+}
+
+// LoadJobs 从状态目录加载持久化的作业，并继续执行未完成的作业
+// 上次退出时正在执行的任务重新置为等待中
+func (s *Server) LoadJobs() error {
+	if s.jobStateDir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(s.jobStateDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("读取作业状态目录失败: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(s.jobStateDir, entry.Name())
+		j, err := loadJobState(path)
+		if err != nil {
+			s.logger.Warn("加载作业状态失败: %s, 错误: %v", path, err)
+			continue
+		}
+
+		j.mu.Lock()
+		j.source.reset()
+		j.info.PendingCount += j.info.RunningCount
+		j.info.RunningCount = 0
+		jobStatus := j.info.Status
+		j.mu.Unlock()
+
+		s.jobsMu.Lock()
+		s.jobs[j.info.JobId] = j
+		s.jobsMu.Unlock()
+
+		if jobStatus == tasksmanager.TasksStatus_TASKS_STATUS_RUNNING || jobStatus == tasksmanager.TasksStatus_TASKS_STATUS_PAUSED {
+			go s.runJob(j)
+		}
+		s.logger.Info("已加载作业: %s (%s), 状态: %v", j.info.JobId, j.info.Name, jobStatus)
+	}
+	return nil
+}
+
+// loadJobState 从文件加载作业状态
+func loadJobState(path string) (*job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var state jobState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("解析作业状态失败: %w", err)
+	}
+
+	info := &tasksmanager.JobInfo{}
+	if err := protojson.Unmarshal(state.Info, info); err != nil {
+		return nil, fmt.Errorf("解析作业信息失败: %w", err)
+	}
+
+	if state.Discovery != nil {
+		spec := &tasksmanager.CreateDiscoveryJobRequest{}
+		if err := protojson.Unmarshal(state.Discovery, spec); err != nil {
+			return nil, fmt.Errorf("解析发现作业参数失败: %w", err)
+		}
+		source := newDiscoveryJobSource(spec)
+		for _, raw := range state.Frontier {
+			task := &tasksmanager.TaskRequest{}
+			if err := protojson.Unmarshal(raw, task); err != nil {
+				return nil, fmt.Errorf("解析发现作业任务失败: %w", err)
+			}
+			source.queue = append(source.queue, task)
+		}
+		return newJob(info, source), nil
+	}
+
+	if state.Region != nil {
+		spec := &tasksmanager.CreateRegionJobRequest{}
+		if err := protojson.Unmarshal(state.Region, spec); err != nil {
+			return nil, fmt.Errorf("解析区域作业参数失败: %w", err)
+		}
+		source, _, err := newRegionJobSource(spec)
+		if err != nil {
+			return nil, err
+		}
+		source.cursor = state.Cursor
+		source.retry = state.Retry
+		return newJob(info, source), nil
+	}
+
+	tasks := make([]*tasksmanager.TaskRequest, 0, len(state.Tasks))
+	for _, raw := range state.Tasks {
+		task := &tasksmanager.TaskRequest{}
+		if err := protojson.Unmarshal(raw, task); err != nil {
+			return nil, fmt.Errorf("解析作业任务失败: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	return newJob(info, &listJobSource{tasks: tasks}), nil
+}
+
+// saveJob 持久化作业状态（先写临时文件再重命名，避免写入中断导致状态文件损坏）
+func (s *Server) saveJob(j *job) error {
+	if s.jobStateDir == "" {
+		return nil
+	}
+	j.saveMu.Lock()
+	defer j.saveMu.Unlock()
+
+	j.mu.Lock()
+	var state jobState
+	info, err := protojson.Marshal(j.info)
+	if err != nil {
+		j.mu.Unlock()
+		return fmt.Errorf("序列化作业信息失败: %w", err)
+	}
+	state.Info = info
+	if err := j.source.save(&state); err != nil {
+		j.mu.Unlock()
+		return err
+	}
+	jobID := j.info.JobId
+	j.dirty = false
+	j.mu.Unlock()
+
+	out, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("序列化作业状态失败: %w", err)
+	}
+	if err := os.MkdirAll(s.jobStateDir, 0755); err != nil {
+		return fmt.Errorf("无法创建作业状态目录 %s: %w", s.jobStateDir, err)
+	}
+	path := filepath.Join(s.jobStateDir, jobID+".json")
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, out, 0644); err != nil {
+		return fmt.Errorf("写入作业状态失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("写入作业状态失败: %w", err)
+	}
+	return nil
+}
+
+// saveJobLogged 持久化作业状态，失败时只记录日志
+func (s *Server) saveJobLogged(j *job) {
+	if err := s.saveJob(j); err != nil {
+		s.logger.Warn("保存作业状态失败: %s, 错误: %v", j.info.JobId, err)
+	}
+}
+
+// wakeJobs 唤醒所有作业中等待领取任务的 worker（服务器排空时调用）
+func (s *Server) wakeJobs() {
+	s.jobsMu.RLock()
+	defer s.jobsMu.RUnlock()
+	for _, j := range s.jobs {
+		j.mu.Lock()
+		j.cond.Broadcast()
+		j.mu.Unlock()
+	}
+}
+
+// saveAllJobs 持久化所有作业状态（服务器停止时调用）
+func (s *Server) saveAllJobs() {
+	s.jobsMu.RLock()
+	jobs := make([]*job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		jobs = append(jobs, j)
+	}
+	s.jobsMu.RUnlock()
+
+	for _, j := range jobs {
+		s.saveJobLogged(j)
+	}
+}
+
+// runJob 在后台执行作业，直到所有任务执行完毕或作业被取消
+func (s *Server) runJob(j *job) {
+	var wg sync.WaitGroup
+	for i := 0; i < s.jobConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				id, task, ok := j.acquireTask(s)
+				if !ok {
+					return
+				}
+				if executor, ok := j.source.(jobExecutor); ok {
+					taskStatus, discovered := executor.execute(s, task)
+					j.completeTask(id, taskStatus, discovered)
+				} else {
+					j.completeTask(id, s.executeJobTask(task), nil)
+				}
+				s.exitTask()
+			}
+		}()
+	}
+
+	// 运行期间定期持久化状态
+	stopSaver := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(jobSaveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				j.mu.Lock()
+				dirty := j.dirty
+				j.mu.Unlock()
+				if dirty {
+					s.saveJobLogged(j)
+				}
+			case <-stopSaver:
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(stopSaver)
+
+	j.mu.Lock()
+	// 服务器排空导致提前退出时保持运行状态，剩余任务在下次启动时继续执行
+	interrupted := s.draining.Load() && j.info.PendingCount > 0
+	if j.info.Status == tasksmanager.TasksStatus_TASKS_STATUS_RUNNING && !interrupted {
+		j.info.Status = tasksmanager.TasksStatus_TASKS_STATUS_COMPLETED
+		j.info.UpdateTime = time.Now().Format(time.RFC3339)
+	}
+	info := proto.Clone(j.info).(*tasksmanager.JobInfo)
+	j.mu.Unlock()
+
+	s.saveJobLogged(j)
+	if interrupted {
+		s.logger.Info("服务器排空，作业暂停执行: %s (%s), 剩余任务: %d", info.JobId, info.Name, info.PendingCount)
+		return
+	}
+	s.publishJobEvent(info)
+	s.logger.Info("作业结束: %s (%s), 状态: %v, 成功: %d, 失败: %d", info.JobId, info.Name, info.Status, info.SuccessCount, info.FailedCount)
+}
+
+// executeJobTask 执行作业中的单个任务（与 SubmitTask 走相同的执行路径，排空期间不拒绝已领取的任务），返回任务最终状态
+func (s *Server) executeJobTask(task *tasksmanager.TaskRequest) tasksmanager.TaskStatus {
+	resp, err := s.runTask(context.Background(), bulkPriority(task))
+	if err == nil && resp.GetTaskResponseStatusCode() == http.StatusOK {
+		return tasksmanager.TaskStatus_TASK_STATUS_SUCCESS
+	}
+	return tasksmanager.TaskStatus_TASK_STATUS_FAILED
+}
+
+// getJob 根据 ID 查找作业
+func (s *Server) getJob(jobID string) (*job, error) {
+	s.jobsMu.RLock()
+	j, ok := s.jobs[jobID]
+	s.jobsMu.RUnlock()
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "作业不存在: %s", jobID)
+	}
+	return j, nil
+}
+
+// CreateJob 创建作业并立即在后台开始执行
+func (s *Server) CreateJob(ctx context.Context, req *tasksmanager.CreateJobRequest) (*tasksmanager.JobInfo, error) {
+	if len(req.GetTasks()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "作业至少需要包含一个任务")
+	}
+
+	pending := tasksmanager.TaskStatus_TASK_STATUS_PENDING
+	tasks := make([]*tasksmanager.TaskRequest, 0, len(req.GetTasks()))
+	for _, task := range req.GetTasks() {
+		task = proto.Clone(task).(*tasksmanager.TaskRequest)
+		task.TaskStatus = &pending
+		tasks = append(tasks, task)
+	}
+
+	j := newJob(newJobInfo(req.GetName(), int64(len(tasks))), &listJobSource{tasks: tasks})
+	return s.startJob(j)
+}
+
+// newJobInfo 创建新作业的作业信息（所有任务均为等待中）
+func newJobInfo(name string, total int64) *tasksmanager.JobInfo {
+	now := time.Now().Format(time.RFC3339)
+	return &tasksmanager.JobInfo{
+		JobId:        generateTaskID(),
+		Name:         name,
+		Status:       tasksmanager.TasksStatus_TASKS_STATUS_RUNNING,
+		TotalCount:   total,
+		PendingCount: total,
+		CreateTime:   now,
+		UpdateTime:   now,
+	}
+}
+
+// startJob 持久化并注册新作业，然后在后台开始执行
+func (s *Server) startJob(j *job) (*tasksmanager.JobInfo, error) {
+	if err := s.saveJob(j); err != nil {
+		return nil, status.Errorf(codes.Internal, "保存作业状态失败: %v", err)
+	}
+
+	s.jobsMu.Lock()
+	s.jobs[j.info.JobId] = j
+	s.jobsMu.Unlock()
+
+	go s.runJob(j)
+	s.logger.Info("已创建作业: %s (%s), 任务数: %d", j.info.JobId, j.info.Name, j.info.TotalCount)
+
+	info := j.snapshot()
+	s.publishJobEvent(info)
+	return info, nil
+}
+
+// GetJob 获取作业信息
+func (s *Server) GetJob(ctx context.Context, req *tasksmanager.JobRequest) (*tasksmanager.JobInfo, error) {
+	j, err := s.getJob(req.GetJobId())
+	if err != nil {
+		return nil, err
+	}
+	return j.snapshot(), nil
+}
+
+// ListJobs 获取作业列表（按创建时间排序）
+func (s *Server) ListJobs(ctx context.Context, req *tasksmanager.ListJobsRequest) (*tasksmanager.ListJobsResponse, error) {
+	s.jobsMu.RLock()
+	items := make([]*tasksmanager.JobInfo, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		items = append(items, j.snapshot())
+	}
+	s.jobsMu.RUnlock()
+
+	sort.Slice(items, func(a, b int) bool {
+		if items[a].CreateTime != items[b].CreateTime {
+			return items[a].CreateTime < items[b].CreateTime
+		}
+		return items[a].JobId < items[b].JobId
+	})
+	return &tasksmanager.ListJobsResponse{Items: items}, nil
+}
+
+// PauseJob 暂停作业（正在执行的任务会继续完成）
+func (s *Server) PauseJob(ctx context.Context, req *tasksmanager.JobRequest) (*tasksmanager.JobInfo, error) {
+	return s.transitionJob(req.GetJobId(), tasksmanager.TasksStatus_TASKS_STATUS_RUNNING, tasksmanager.TasksStatus_TASKS_STATUS_PAUSED)
+}
+
+// ResumeJob 恢复已暂停的作业
+func (s *Server) ResumeJob(ctx context.Context, req *tasksmanager.JobRequest) (*tasksmanager.JobInfo, error) {
+	return s.transitionJob(req.GetJobId(), tasksmanager.TasksStatus_TASKS_STATUS_PAUSED, tasksmanager.TasksStatus_TASKS_STATUS_RUNNING)
+}
+
+// CancelJob 取消作业（运行中或已暂停的作业均可取消）
+func (s *Server) CancelJob(ctx context.Context, req *tasksmanager.JobRequest) (*tasksmanager.JobInfo, error) {
+	return s.transitionJob(req.GetJobId(), tasksmanager.TasksStatus_TASKS_STATUS_UNKNOWN, tasksmanager.TasksStatus_TASKS_STATUS_STOPPED)
+}
+
+// transitionJob 切换作业状态
+// from 为 TASKS_STATUS_UNKNOWN 时表示允许从运行中或已暂停切换
+func (s *Server) transitionJob(jobID string, from, to tasksmanager.TasksStatus) (*tasksmanager.JobInfo, error) {
+	j, err := s.getJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	j.mu.Lock()
+	current := j.info.Status
+	allowed := current == from
+	if from == tasksmanager.TasksStatus_TASKS_STATUS_UNKNOWN {
+		allowed = current == tasksmanager.TasksStatus_TASKS_STATUS_RUNNING || current == tasksmanager.TasksStatus_TASKS_STATUS_PAUSED
+	}
+	if !allowed {
+		j.mu.Unlock()
+		return nil, status.Errorf(codes.FailedPrecondition, "作业 %s 当前状态为 %v，无法切换到 %v", jobID, current, to)
+	}
+	j.info.Status = to
+	j.info.UpdateTime = time.Now().Format(time.RFC3339)
+	j.dirty = true
+	j.cond.Broadcast()
+	j.mu.Unlock()
+
+	s.saveJobLogged(j)
+	s.logger.Info("作业状态变更: %s, %v -> %v", jobID, current, to)
+
+	info := j.snapshot()
+	s.publishJobEvent(info)
+	return info, nil
+}
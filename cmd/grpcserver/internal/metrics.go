@@ -0,0 +1,309 @@
+package grpcserver
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+	"crawler-platform/remotedomainippool"
+)
+
+// taskDurationBuckets 任务耗时直方图的桶上限（秒）
+var taskDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// histogram 累计直方图（Prometheus histogram 语义，桶计数在导出时累加）
+type histogram struct {
+	counts []int64 // 与 taskDurationBuckets 对应，最后一个为 +Inf
+	sum    float64
+	count  int64
+}
+
+// observe 记录一次观测值
+func (h *histogram) observe(v float64) {
+	if h.counts == nil {
+		h.counts = make([]int64, len(taskDurationBuckets)+1)
+	}
+	i := sort.SearchFloat64s(taskDurationBuckets, v)
+	h.counts[i]++
+	h.sum += v
+	h.count++
+}
+
+// taskCountKey 任务计数的标签组合
+type taskCountKey struct {
+	taskType   string
+	statusCode string
+	source     string
+}
+
+// taskErrorKey 任务失败计数的标签组合
+type taskErrorKey struct {
+	taskType  string
+	errorCode string
+}
+
+// taskMetrics SubmitTask 的任务计数与耗时统计
+type taskMetrics struct {
+	mu        sync.Mutex
+	counts    map[taskCountKey]int64
+	errors    map[taskErrorKey]int64
+	canceled  map[string]int64      // 任务类型 -> 客户端取消的任务数（不计入失败）
+	durations map[string]*histogram // 任务类型 -> 耗时直方图
+}
+
+// observeTask 记录一次 SubmitTask 的结果（失败时状态码取自状态详情中的任务响应）
+func (m *taskMetrics) observeTask(taskType tasksmanager.TaskType, resp *tasksmanager.TaskResponse, err error, duration time.Duration) {
+	if err != nil {
+		resp = taskResponseFromError(err)
+	}
+	statusCode := "0"
+	if resp != nil && resp.TaskResponseStatusCode != nil {
+		statusCode = strconv.Itoa(int(resp.GetTaskResponseStatusCode()))
+	}
+	source := resp.GetResponseSource().String()
+	canceled := taskErrorCode(err) == tasksmanager.TaskErrorCode_TASK_ERROR_CODE_CANCELED
+	switch {
+	case canceled:
+		source = "CANCELED"
+	case err != nil:
+		source = "ERROR"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[taskCountKey]int64)
+		m.errors = make(map[taskErrorKey]int64)
+		m.canceled = make(map[string]int64)
+		m.durations = make(map[string]*histogram)
+	}
+	m.counts[taskCountKey{taskType.String(), statusCode, source}]++
+	switch {
+	case canceled:
+		m.canceled[taskType.String()]++
+	case err != nil:
+		m.errors[taskErrorKey{taskType.String(), taskErrorCode(err).String()}]++
+	}
+	h, ok := m.durations[taskType.String()]
+	if !ok {
+		h = &histogram{}
+		m.durations[taskType.String()] = h
+	}
+	h.observe(duration.Seconds())
+}
+
+// taskMetricsSnapshot 任务指标的副本（导出时不持有 taskMetrics.mu）
+type taskMetricsSnapshot struct {
+	counts    map[taskCountKey]int64
+	errors    map[taskErrorKey]int64
+	canceled  map[string]int64
+	durations map[string]histogram
+}
+
+// snapshot 复制当前的任务指标
+func (m *taskMetrics) snapshot() taskMetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snap := taskMetricsSnapshot{
+		counts:    maps.Clone(m.counts),
+		errors:    maps.Clone(m.errors),
+		canceled:  maps.Clone(m.canceled),
+		durations: make(map[string]histogram, len(m.durations)),
+	}
+	for taskType, h := range m.durations {
+		snap.durations[taskType] = histogram{counts: append([]int64(nil), h.counts...), sum: h.sum, count: h.count}
+	}
+	return snap
+}
+
+// metricsWriter 以 Prometheus 文本格式输出指标
+type metricsWriter struct {
+	w *bufio.Writer
+}
+
+// header 输出指标的 HELP 与 TYPE 行
+func (mw *metricsWriter) header(name, typ, help string) {
+	fmt.Fprintf(mw.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample 输出一个样本（labels 为 名称, 值, 名称, 值 ...）
+func (mw *metricsWriter) sample(name string, value float64, labels ...string) {
+	mw.w.WriteString(name)
+	if len(labels) > 0 {
+		mw.w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				mw.w.WriteByte(',')
+			}
+			fmt.Fprintf(mw.w, "%s=%q", labels[i], labels[i+1])
+		}
+		mw.w.WriteByte('}')
+	}
+	mw.w.WriteByte(' ')
+	mw.w.WriteString(formatMetricValue(value))
+	mw.w.WriteByte('\n')
+}
+
+// single 输出只有一个样本的指标
+func (mw *metricsWriter) single(name, typ, help string, value float64) {
+	mw.header(name, typ, help)
+	mw.sample(name, value)
+}
+
+// formatMetricValue 格式化样本值
+func formatMetricValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// SetDomainMonitor 设置域名 IP 监控器（用于导出各域名解析到的 IP 数量）
+func (s *Server) SetDomainMonitor(monitor remotedomainippool.DomainMonitor, domains []string) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.domainMonitor = monitor
+	s.monitoredDomains = domains
+}
+
+// handleMetrics 以 Prometheus 文本格式导出任务、热连接池、本地 IP 池、域名监控与存储指标
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	mw := &metricsWriter{w: bufio.NewWriter(w)}
+	defer mw.w.Flush()
+
+	s.writeTaskMetrics(mw)
+	s.writeServerMetrics(mw)
+	s.writePoolMetrics(mw)
+}
+
+// writeTaskMetrics 导出任务计数与耗时直方图（先复制指标再写出，抓取方读取缓慢时不阻塞任务）
+func (s *Server) writeTaskMetrics(mw *metricsWriter) {
+	m := s.taskMetrics.snapshot()
+
+	mw.header("crawler_tasks_total", "counter", "SubmitTask 完成的任务数（按任务类型、状态码与数据来源）")
+	for key, count := range m.counts {
+		mw.sample("crawler_tasks_total", float64(count), "task_type", key.taskType, "status_code", key.statusCode, "source", key.source)
+	}
+	mw.header("crawler_task_errors_total", "counter", "SubmitTask 失败的任务数（按任务类型与错误码）")
+	for key, count := range m.errors {
+		mw.sample("crawler_task_errors_total", float64(count), "task_type", key.taskType, "error_code", key.errorCode)
+	}
+	mw.header("crawler_tasks_canceled_total", "counter", "SubmitTask 被客户端取消的任务数（按任务类型，不计入失败）")
+	for taskType, count := range m.canceled {
+		mw.sample("crawler_tasks_canceled_total", float64(count), "task_type", taskType)
+	}
+	mw.header("crawler_task_duration_seconds", "histogram", "SubmitTask 任务耗时")
+	for taskType, h := range m.durations {
+		var cumulative int64
+		for i, upper := range taskDurationBuckets {
+			cumulative += h.counts[i]
+			mw.sample("crawler_task_duration_seconds_bucket", float64(cumulative), "task_type", taskType, "le", formatMetricValue(upper))
+		}
+		mw.sample("crawler_task_duration_seconds_bucket", float64(h.count), "task_type", taskType, "le", "+Inf")
+		mw.sample("crawler_task_duration_seconds_sum", h.sum, "task_type", taskType)
+		mw.sample("crawler_task_duration_seconds_count", float64(h.count), "task_type", taskType)
+	}
+}
+
+// writeServerMetrics 导出热点缓存、调度、节点与存储指标
+func (s *Server) writeServerMetrics(mw *metricsWriter) {
+	cache := s.hotCache.stats()
+	mw.single("crawler_hot_cache_hits_total", "counter", "热点瓦片缓存命中次数", float64(cache.Hits))
+	mw.single("crawler_hot_cache_misses_total", "counter", "热点瓦片缓存未命中次数", float64(cache.Misses))
+	mw.single("crawler_hot_cache_evictions_total", "counter", "热点瓦片缓存淘汰条目数", float64(cache.Evictions))
+	mw.single("crawler_hot_cache_entries", "gauge", "热点瓦片缓存当前条目数", float64(cache.Entries))
+	mw.single("crawler_hot_cache_bytes", "gauge", "热点瓦片缓存当前占用字节数", float64(cache.Bytes))
+	mw.single("crawler_tasks_coalesced_total", "counter", "与正在执行的相同任务合并的次数", float64(atomic.LoadInt64(&s.flights.coalesced)))
+
+	scheduler := s.scheduler.stats()
+	mw.single("crawler_scheduler_running", "gauge", "当前正在请求上游的任务数", float64(scheduler.Running))
+	mw.single("crawler_scheduler_max_running", "gauge", "同时请求上游的任务数上限", float64(scheduler.MaxRunning))
+	mw.header("crawler_scheduler_queued", "gauge", "当前在等待队列中的任务数")
+	for _, p := range scheduler.Priorities {
+		mw.sample("crawler_scheduler_queued", float64(p.Queued), "priority", p.Priority.String())
+	}
+	mw.header("crawler_scheduler_admitted_total", "counter", "获得执行名额的任务数")
+	for _, p := range scheduler.Priorities {
+		mw.sample("crawler_scheduler_admitted_total", float64(p.Admitted), "priority", p.Priority.String())
+	}
+	mw.header("crawler_scheduler_rejected_total", "counter", "因等待队列已满被拒绝的任务数")
+	for _, p := range scheduler.Priorities {
+		mw.sample("crawler_scheduler_rejected_total", float64(p.Rejected), "priority", p.Priority.String())
+	}
+	mw.header("crawler_scheduler_timed_out_total", "counter", "等待超时或调用方取消的任务数")
+	for _, p := range scheduler.Priorities {
+		mw.sample("crawler_scheduler_timed_out_total", float64(p.TimedOut), "priority", p.Priority.String())
+	}
+
+	mw.single("crawler_admin_event_subscribers", "gauge", "当前订阅仪表盘实时事件的连接数", float64(s.events.count.Load()))
+	mw.single("crawler_admin_events_dropped_total", "counter", "因订阅者缓冲区已满被丢弃的实时事件数", float64(s.events.dropped.Load()))
+
+	s.nodesMu.RLock()
+	nodeCount := len(s.nodes)
+	s.nodesMu.RUnlock()
+	s.clientsMu.RLock()
+	clientCount := len(s.clients)
+	s.clientsMu.RUnlock()
+	mw.single("crawler_cluster_nodes", "gauge", "已知的服务器节点数（包括本节点）", float64(nodeCount))
+	mw.single("crawler_clients", "gauge", "已注册的任务客户端数", float64(clientCount))
+
+	if s.tileStorage != nil {
+		mw.single("crawler_storage_pending_persist", "gauge", "瓦片存储异步持久化队列中等待写入的条目数", float64(s.tileStorage.GetPendingPersistCount()))
+	}
+}
+
+// writePoolMetrics 导出热连接池、本地 IP 池与域名监控指标
+func (s *Server) writePoolMetrics(mw *metricsWriter) {
+	if s.utlsClient != nil {
+		snapshot := s.utlsClient.GetMetrics()
+		hostStats := s.utlsClient.HostStats()
+		hosts := make([]string, 0, len(hostStats))
+		for host := range hostStats {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+
+		mw.header("utls_pool_connections", "gauge", "热连接池中的连接数（按主机与状态）")
+		for _, host := range hosts {
+			stats := hostStats[host]
+			mw.sample("utls_pool_connections", float64(stats.Healthy-stats.InUse), "host", host, "state", "idle")
+			mw.sample("utls_pool_connections", float64(stats.InUse), "host", host, "state", "in_use")
+			mw.sample("utls_pool_connections", float64(stats.Total-stats.Healthy), "host", host, "state", "unhealthy")
+		}
+		mw.single("utls_pool_blacklisted_ips", "gauge", "当前在黑名单中的远程 IP 数", float64(snapshot.BlacklistedIPs))
+		mw.single("utls_pool_rate_limited_total", "counter", "因限速被拒绝的连接获取次数", float64(snapshot.RateLimitedRequests))
+	}
+
+	if s.ipPool != nil {
+		mw.single("local_ip_pool_ipv6_addresses", "gauge", "本地 IP 池中活跃的 IPv6 地址数", float64(len(s.ipPool.GetActiveIPv6Addresses())))
+		mw.single("local_ip_pool_ipv4_addresses", "gauge", "本地 IP 池中的 IPv4 地址数", float64(len(s.ipPool.GetIPv4Addresses())))
+	}
+
+	s.configMu.RLock()
+	monitor, domains := s.domainMonitor, s.monitoredDomains
+	s.configMu.RUnlock()
+	if monitor != nil {
+		mw.header("dns_monitor_domain_ips", "gauge", "域名监控器解析到的 IP 数（按域名与地址族）")
+		for _, domain := range domains {
+			pool, _ := monitor.GetDomainPool(domain)
+			families := make([]string, 0, len(pool))
+			for family := range pool {
+				families = append(families, family)
+			}
+			sort.Strings(families)
+			for _, family := range families {
+				mw.sample("dns_monitor_domain_ips", float64(len(pool[family])), "domain", domain, "family", strings.ToLower(family))
+			}
+		}
+	}
+}
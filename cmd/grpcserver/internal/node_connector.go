@@ -28,6 +28,10 @@ type NodeConnector struct {
 	// TLS 配置（用于连接到其他节点）
 	tlsConfig *tls.Config
 
+	// 连接其他节点时携带的 API 令牌（为空时不携带）
+	authToken   string
+	authTokenMu sync.RWMutex
+
 	// 已连接的节点客户端映射 (nodeAddr -> client)，使用 IP:Port 作为 key
 	connectedNodes   map[string]tasksmanager.TasksManagerClient
 	connectedNodesMu sync.RWMutex
@@ -45,6 +49,10 @@ type NodeConnector struct {
 
 	// 停止通道
 	stopChan chan struct{}
+
+	// 离开通道（服务器排空时关闭：停止心跳与自动发现，保留已有连接供正在执行的任务使用）
+	leaveChan chan struct{}
+	leaveOnce sync.Once
 }
 
 // NewNodeConnector 创建新的节点连接管理器（保持向后兼容）
@@ -64,6 +72,7 @@ func NewNodeConnectorWithTLS(nodeID, address, port string, tlsConfig *tls.Config
 		knownNodes:      make(map[string]*tasksmanager.GrpcServerNodeInfo),
 		logger:          logger.GetGlobalLogger(),
 		stopChan:        make(chan struct{}),
+		leaveChan:       make(chan struct{}),
 	}
 }
 
@@ -86,6 +95,27 @@ func (nc *NodeConnector) getSelfAddr() string {
 	return fmt.Sprintf("%s:%s", addr, nc.port)
 }
 
+// SetAuthToken 设置连接其他节点时携带的 API 令牌（只影响之后建立的连接）
+func (nc *NodeConnector) SetAuthToken(token string) {
+	nc.authTokenMu.Lock()
+	defer nc.authTokenMu.Unlock()
+	nc.authToken = token
+}
+
+// dialOptions 返回连接其他节点的 gRPC 选项（传输凭证、keepalive 与 API 令牌）
+func (nc *NodeConnector) dialOptions(transportCreds credentials.TransportCredentials, keepaliveParams keepalive.ClientParameters) []grpc.DialOption {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(transportCreds),
+		grpc.WithKeepaliveParams(keepaliveParams),
+	}
+	nc.authTokenMu.RLock()
+	defer nc.authTokenMu.RUnlock()
+	if nc.authToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: nc.authToken}))
+	}
+	return opts
+}
+
 // Start 启动节点连接管理器
 // 开始自动发现和连接其他节点，并启动心跳发送
 func (nc *NodeConnector) Start() {
@@ -140,9 +170,7 @@ func (nc *NodeConnector) Bootstrap(bootstrapAddresses []string) error {
 			PermitWithoutStream: true,             // 即使没有活跃的流也发送 keepalive ping
 		}
 
-		conn, err := grpc.NewClient(actualAddr,
-			grpc.WithTransportCredentials(transportCreds),
-			grpc.WithKeepaliveParams(keepaliveParams))
+		conn, err := grpc.NewClient(actualAddr, nc.dialOptions(transportCreds, keepaliveParams)...)
 		if err != nil {
 			nc.logger.Warn("连接引导节点失败 %s: %v", addr, err)
 			continue
@@ -313,6 +341,36 @@ func (nc *NodeConnector) Stop() {
 	nc.nodeConnectionsMu.Unlock()
 }
 
+// Leave 停止向其他节点发送心跳和自动连接新节点（已有连接保持到 Stop）
+// 服务器排空时调用，避免已通知离开后又通过心跳重新注册到其他节点
+func (nc *NodeConnector) Leave() {
+	nc.leaveOnce.Do(func() {
+		close(nc.leaveChan)
+	})
+}
+
+// DisconnectNode 断开并移除指定节点（nodeAddr 为 IP:Port），其他节点通知离开时调用
+func (nc *NodeConnector) DisconnectNode(nodeAddr string) {
+	nc.connectedNodesMu.Lock()
+	delete(nc.connectedNodes, nodeAddr)
+	nc.connectedNodesMu.Unlock()
+
+	nc.nodeConnectionsMu.Lock()
+	conn, exists := nc.nodeConnections[nodeAddr]
+	delete(nc.nodeConnections, nodeAddr)
+	nc.nodeConnectionsMu.Unlock()
+	if exists && conn != nil {
+		// 延迟关闭，让已转发到该节点的任务执行完
+		time.AfterFunc(forwardTimeout, func() {
+			conn.Close()
+		})
+	}
+
+	nc.knownNodesMu.Lock()
+	delete(nc.knownNodes, nodeAddr)
+	nc.knownNodesMu.Unlock()
+}
+
 // ConnectToNode 连接到指定的节点
 func (nc *NodeConnector) ConnectToNode(nodeInfo *tasksmanager.GrpcServerNodeInfo) error {
 	// 获取节点地址（IP:Port），这是节点的唯一标识
@@ -375,9 +433,7 @@ func (nc *NodeConnector) ConnectToNode(nodeInfo *tasksmanager.GrpcServerNodeInfo
 	}
 
 	// 建立 gRPC 连接
-	conn, err := grpc.NewClient(nodeAddr,
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithKeepaliveParams(keepaliveParams))
+	conn, err := grpc.NewClient(nodeAddr, nc.dialOptions(transportCreds, keepaliveParams)...)
 	if err != nil {
 		return fmt.Errorf("连接节点失败 %s: %w", nodeAddr, err)
 	}
@@ -467,6 +523,8 @@ func (nc *NodeConnector) startNodeHeartbeat() {
 		select {
 		case <-nc.stopChan:
 			return
+		case <-nc.leaveChan:
+			return
 		case <-ticker.C:
 			// 获取系统信息（每次心跳都获取最新信息）
 			hostname, _ := GetRealHostname()
@@ -610,6 +668,8 @@ func (nc *NodeConnector) autoDiscoverAndConnect() {
 		select {
 		case <-nc.stopChan:
 			return
+		case <-nc.leaveChan:
+			return
 		case <-ticker.C:
 			// 定期同步已知节点列表
 			selfAddr := nc.getSelfAddr()
@@ -0,0 +1,179 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"crawler-platform/GoogleEarth"
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxRegionJobLevel 区域作业允许的最大层级（瓦片键长度不超过 24，与 Store 的瓦片键限制一致）
+const maxRegionJobLevel = 23
+
+// regionSegment 区域作业中一个（边界框, 层级）组合覆盖的瓦片范围
+type regionSegment struct {
+	rng   GoogleEarth.QtNodeRange
+	types []tasksmanager.TaskType // 该层级要执行的任务类型
+	start uint64                  // 该分段第一个任务的序号
+}
+
+// regionJobSource 区域作业的任务来源（CreateRegionJob 创建）
+// 任务按序号惰性生成：序号依次对应 边界框 -> 层级 -> 瓦片 -> 任务类型，不在内存中保存任务列表
+// Q2/QP 四叉树数据包只存在于根节点 "0" 与瓦片键长度为 4 的倍数的节点（层级 0、3、7、11...），其他层级不生成 Q2/QP 任务
+type regionJobSource struct {
+	spec     *tasksmanager.CreateRegionJobRequest
+	segments []regionSegment
+	total    uint64
+
+	cursor  uint64              // 下一个未领取的任务序号
+	retry   []uint64            // 需要重新执行的任务序号（上次退出时正在执行）
+	running map[uint64]struct{} // 正在执行的任务序号
+}
+
+// newRegionJobSource 校验区域作业参数并计算各分段的瓦片范围，返回任务来源和任务总数
+func newRegionJobSource(spec *tasksmanager.CreateRegionJobRequest) (*regionJobSource, uint64, error) {
+	if len(spec.GetBboxes()) == 0 {
+		return nil, 0, fmt.Errorf("区域作业至少需要一个边界框")
+	}
+	if spec.GetMinLevel() < 0 || spec.GetMaxLevel() > maxRegionJobLevel || spec.GetMinLevel() > spec.GetMaxLevel() {
+		return nil, 0, fmt.Errorf("无效的层级范围: %d-%d（允许 0-%d）", spec.GetMinLevel(), spec.GetMaxLevel(), maxRegionJobLevel)
+	}
+	if len(spec.GetTaskTypes()) == 0 {
+		return nil, 0, fmt.Errorf("区域作业至少需要一个任务类型")
+	}
+	for _, taskType := range spec.GetTaskTypes() {
+		switch taskType {
+		case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2,
+			tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_QP,
+			tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_TERRAIN:
+		case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY:
+			if spec.ImageryEpoch == nil {
+				return nil, 0, fmt.Errorf("Imagery 任务需要 imageryEpoch 参数")
+			}
+		default:
+			return nil, 0, fmt.Errorf("区域作业不支持的任务类型: %v", taskType)
+		}
+	}
+
+	source := &regionJobSource{spec: spec, running: make(map[uint64]struct{})}
+	for _, bbox := range spec.GetBboxes() {
+		for level := spec.GetMinLevel(); level <= spec.GetMaxLevel(); level++ {
+			rng, ok := GoogleEarth.NewQtNodeRangeFromBounds(bbox.GetMinLat(), bbox.GetMinLon(), bbox.GetMaxLat(), bbox.GetMaxLon(), uint(level))
+			if !ok {
+				return nil, 0, fmt.Errorf("无效的边界框: %v", bbox)
+			}
+			types := regionLevelTaskTypes(spec.GetTaskTypes(), level)
+			if len(types) == 0 {
+				continue
+			}
+			source.segments = append(source.segments, regionSegment{rng: rng, types: types, start: source.total})
+			source.total += rng.Count() * uint64(len(types))
+		}
+	}
+	if source.total == 0 {
+		return nil, 0, fmt.Errorf("层级 %d-%d 中没有 Q2/QP 数据包所在的层级（层级需为 0 或 4n-1，即瓦片键长度为 4 的倍数）", spec.GetMinLevel(), spec.GetMaxLevel())
+	}
+	return source, source.total, nil
+}
+
+// isQ2PacketKeyLength 判断该长度的瓦片键是否为 Q2/QP 数据包所在节点（根节点 "0" 或长度为 4 的倍数）
+func isQ2PacketKeyLength(n int) bool {
+	return n == 1 || n%4 == 0
+}
+
+// regionLevelTaskTypes 返回层级要执行的任务类型（层级 level 的瓦片键长度为 level+1，Q2/QP 只在数据包所在层级执行）
+func regionLevelTaskTypes(taskTypes []tasksmanager.TaskType, level int32) []tasksmanager.TaskType {
+	types := make([]tasksmanager.TaskType, 0, len(taskTypes))
+	for _, taskType := range taskTypes {
+		switch taskType {
+		case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2, tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_QP:
+			if !isQ2PacketKeyLength(int(level) + 1) {
+				continue
+			}
+		}
+		types = append(types, taskType)
+	}
+	return types
+}
+
+// taskAt 生成序号对应的任务
+func (r *regionJobSource) taskAt(id uint64) *tasksmanager.TaskRequest {
+	// 找到最后一个 start <= id 的分段
+	idx := sort.Search(len(r.segments), func(i int) bool { return r.segments[i].start > id }) - 1
+	seg := r.segments[idx]
+	offset := id - seg.start
+	typeCount := uint64(len(seg.types))
+
+	return &tasksmanager.TaskRequest{
+		TaskType:     seg.types[offset%typeCount],
+		TileKey:      seg.rng.At(offset / typeCount),
+		Epoch:        r.spec.GetEpoch(),
+		ImageryEpoch: r.spec.ImageryEpoch,
+		DbName:       r.spec.DbName,
+	}
+}
+
+func (r *regionJobSource) next() (uint64, *tasksmanager.TaskRequest, bool) {
+	var id uint64
+	switch {
+	case len(r.retry) > 0:
+		id = r.retry[0]
+		r.retry = r.retry[1:]
+	case r.cursor < r.total:
+		id = r.cursor
+		r.cursor++
+	default:
+		return 0, nil, false
+	}
+	r.running[id] = struct{}{}
+	return id, r.taskAt(id), true
+}
+
+func (r *regionJobSource) done(id uint64, taskStatus tasksmanager.TaskStatus, discovered []*tasksmanager.TaskRequest) {
+	delete(r.running, id)
+}
+
+func (r *regionJobSource) reset() {
+	for id := range r.running {
+		r.retry = append(r.retry, id)
+	}
+	r.running = make(map[uint64]struct{})
+	sort.Slice(r.retry, func(a, b int) bool { return r.retry[a] < r.retry[b] })
+}
+
+func (r *regionJobSource) save(state *jobState) error {
+	raw, err := protojson.Marshal(r.spec)
+	if err != nil {
+		return fmt.Errorf("序列化区域作业参数失败: %w", err)
+	}
+	state.Region = raw
+	state.Cursor = r.cursor
+	// 正在执行的任务与待重试的任务一起保存，恢复时重新执行
+	state.Retry = append([]uint64(nil), r.retry...)
+	for id := range r.running {
+		state.Retry = append(state.Retry, id)
+	}
+	sort.Slice(state.Retry, func(a, b int) bool { return state.Retry[a] < state.Retry[b] })
+	return nil
+}
+
+// CreateRegionJob 创建区域爬取作业并立即在后台开始执行
+func (s *Server) CreateRegionJob(ctx context.Context, req *tasksmanager.CreateRegionJobRequest) (*tasksmanager.JobInfo, error) {
+	source, total, err := newRegionJobSource(proto.Clone(req).(*tasksmanager.CreateRegionJobRequest))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if total == 0 {
+		return nil, status.Error(codes.InvalidArgument, "区域作业没有需要执行的任务")
+	}
+
+	j := newJob(newJobInfo(req.GetName(), int64(total)), source)
+	return s.startJob(j)
+}
@@ -0,0 +1,38 @@
+package grpcserver
+
+import (
+	"context"
+	"net/http"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetConfigReloader 设置配置重新加载函数（ReloadConfig RPC 与管理接口调用）
+func (s *Server) SetConfigReloader(reloader func() (*tasksmanager.ReloadConfigResponse, error)) {
+	s.configReloader = reloader
+}
+
+// ReloadConfig 重新加载配置文件，返回已生效与需要重启才能生效的配置项
+func (s *Server) ReloadConfig(ctx context.Context, req *tasksmanager.ReloadConfigRequest) (*tasksmanager.ReloadConfigResponse, error) {
+	if s.configReloader == nil {
+		return nil, status.Error(codes.Unimplemented, "服务器不支持重新加载配置")
+	}
+	resp, err := s.configReloader()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "重新加载配置失败: %v", err)
+	}
+	return resp, nil
+}
+
+// handleAdminReloadConfig 重新加载配置文件
+func (s *Server) handleAdminReloadConfig(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.ReloadConfig(r.Context(), &tasksmanager.ReloadConfigRequest{})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, resp)
+}
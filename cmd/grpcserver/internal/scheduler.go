@@ -0,0 +1,248 @@
+package grpcserver
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+	"crawler-platform/utlsclient"
+)
+
+const (
+	// defaultSchedulerMaxRunning 同时请求上游的任务数默认上限
+	defaultSchedulerMaxRunning = 64
+	// defaultSchedulerMaxQueued 等待队列默认容量
+	defaultSchedulerMaxQueued = 1024
+	// defaultSchedulerMaxWait 任务在等待队列中的默认最长等待时间
+	defaultSchedulerMaxWait = 10 * time.Second
+	// schedulerBusyRetryInterval 所有连接都在使用中时，任务重新排队前的等待时间
+	schedulerBusyRetryInterval = 50 * time.Millisecond
+)
+
+// 调度优先级（下标越小优先级越高）
+const (
+	priorityInteractive = iota
+	priorityNormal
+	priorityBulk
+	numTaskPriorities
+)
+
+// priorityIndex 返回任务优先级对应的队列下标（未设置或未知的优先级按 NORMAL 处理）
+func priorityIndex(priority tasksmanager.TaskPriority) int {
+	switch priority {
+	case tasksmanager.TaskPriority_TASK_PRIORITY_INTERACTIVE:
+		return priorityInteractive
+	case tasksmanager.TaskPriority_TASK_PRIORITY_BULK:
+		return priorityBulk
+	default:
+		return priorityNormal
+	}
+}
+
+// schedulerWaiter 等待队列中的任务
+type schedulerWaiter struct {
+	priority int
+	elem     *list.Element // 在队列中的位置（获得名额或离开队列后为 nil）
+	granted  chan struct{} // 获得执行名额时关闭
+}
+
+// taskScheduler 上游请求调度器：限制同时请求上游的任务数，超出时按优先级排队
+// 名额释放时交给优先级最高、等待最久的任务；队列已满时立即拒绝
+type taskScheduler struct {
+	mu         sync.Mutex
+	maxRunning int
+	maxQueued  int
+	maxWait    time.Duration
+	running    int
+	queued     int
+	queues     [numTaskPriorities]*list.List
+
+	admitted [numTaskPriorities]int64
+	rejected [numTaskPriorities]int64
+	timedOut [numTaskPriorities]int64
+}
+
+// newTaskScheduler 创建使用默认配置的调度器
+func newTaskScheduler() *taskScheduler {
+	t := &taskScheduler{
+		maxRunning: defaultSchedulerMaxRunning,
+		maxQueued:  defaultSchedulerMaxQueued,
+		maxWait:    defaultSchedulerMaxWait,
+	}
+	for i := range t.queues {
+		t.queues[i] = list.New()
+	}
+	return t
+}
+
+// setLimits 修改调度配置（提高上限时立即放行排队中的任务）
+func (t *taskScheduler) setLimits(maxRunning, maxQueued int, maxWait time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.maxRunning = maxRunning
+	t.maxQueued = maxQueued
+	t.maxWait = maxWait
+	t.grantLocked()
+}
+
+// acquire 获取一个执行名额，名额已满时按优先级排队直到获得名额、超过 deadline 或 ctx 结束
+func (t *taskScheduler) acquire(ctx context.Context, priority int, deadline time.Time) error {
+	t.mu.Lock()
+	if t.running < t.maxRunning && t.queued == 0 {
+		t.running++
+		t.admitted[priority]++
+		t.mu.Unlock()
+		return nil
+	}
+	if t.queued >= t.maxQueued {
+		t.rejected[priority]++
+		t.mu.Unlock()
+		return fmt.Errorf("%w: 当前 %d 个任务排队中", ErrQueueFull, t.queued)
+	}
+	w := &schedulerWaiter{priority: priority, granted: make(chan struct{})}
+	w.elem = t.queues[priority].PushBack(w)
+	t.queued++
+	t.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-w.granted:
+		return nil
+	case <-timer.C:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	t.mu.Lock()
+	if w.elem == nil {
+		// 超时的同时已获得名额：交还给下一个任务
+		t.mu.Unlock()
+		t.release()
+		return err
+	}
+	t.queues[priority].Remove(w.elem)
+	w.elem = nil
+	t.queued--
+	t.timedOut[priority]++
+	t.mu.Unlock()
+	return err
+}
+
+// release 释放执行名额
+func (t *taskScheduler) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running--
+	t.grantLocked()
+}
+
+// grantLocked 在名额未满时按优先级放行排队中的任务（调用方持有 mu）
+func (t *taskScheduler) grantLocked() {
+	for t.running < t.maxRunning && t.queued > 0 {
+		for _, queue := range t.queues {
+			if front := queue.Front(); front != nil {
+				w := queue.Remove(front).(*schedulerWaiter)
+				w.elem = nil
+				t.queued--
+				t.running++
+				t.admitted[w.priority]++
+				close(w.granted)
+				break
+			}
+		}
+	}
+}
+
+// run 获得执行名额后执行 fn
+// 所有连接都在使用中时释放名额并重新排队，直到执行成功或超过最长等待时间（此时返回最后一次的执行错误）
+func (t *taskScheduler) run(ctx context.Context, priority tasksmanager.TaskPriority, fn func() ([]byte, int32, error)) ([]byte, int32, error) {
+	index := priorityIndex(priority)
+	t.mu.Lock()
+	deadline := time.Now().Add(t.maxWait)
+	t.mu.Unlock()
+
+	var lastErr error
+	for {
+		if err := t.acquire(ctx, index, deadline); err != nil {
+			if lastErr != nil && !errors.Is(err, ErrQueueFull) {
+				return nil, 0, lastErr
+			}
+			return nil, 0, fmt.Errorf("任务排队失败: %w", err)
+		}
+		body, statusCode, err := fn()
+		t.release()
+		if !errors.Is(err, utlsclient.ErrConnectionInUse) || time.Now().Add(schedulerBusyRetryInterval).After(deadline) {
+			return body, statusCode, err
+		}
+		lastErr = err
+
+		select {
+		case <-time.After(schedulerBusyRetryInterval):
+		case <-ctx.Done():
+			return nil, 0, lastErr
+		}
+	}
+}
+
+// stats 返回调度统计
+func (t *taskScheduler) stats() *tasksmanager.SchedulerStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := &tasksmanager.SchedulerStats{
+		Running:    int64(t.running),
+		MaxRunning: int64(t.maxRunning),
+		Queued:     int64(t.queued),
+		MaxQueued:  int64(t.maxQueued),
+	}
+	priorities := []tasksmanager.TaskPriority{
+		tasksmanager.TaskPriority_TASK_PRIORITY_INTERACTIVE,
+		tasksmanager.TaskPriority_TASK_PRIORITY_NORMAL,
+		tasksmanager.TaskPriority_TASK_PRIORITY_BULK,
+	}
+	for i, priority := range priorities {
+		stats.Priorities = append(stats.Priorities, &tasksmanager.SchedulerPriorityStats{
+			Priority: priority,
+			Queued:   int64(t.queues[i].Len()),
+			Admitted: t.admitted[i],
+			Rejected: t.rejected[i],
+			TimedOut: t.timedOut[i],
+		})
+	}
+	return stats
+}
+
+// SetSchedulerConfig 设置上游请求调度配置
+// maxRunning<=0、maxWait<=0 时使用默认值；maxQueued<0 时使用默认值，=0 时不排队（名额已满立即拒绝）
+func (s *Server) SetSchedulerConfig(maxRunning, maxQueued int, maxWait time.Duration) {
+	if maxRunning <= 0 {
+		maxRunning = defaultSchedulerMaxRunning
+	}
+	if maxQueued < 0 {
+		maxQueued = defaultSchedulerMaxQueued
+	}
+	if maxWait <= 0 {
+		maxWait = defaultSchedulerMaxWait
+	}
+	s.scheduler.setLimits(maxRunning, maxQueued, maxWait)
+}
+
+// bulkPriority 作业中未设置优先级的任务按 BULK 执行，避免后台回填挤占交互任务
+func bulkPriority(task *tasksmanager.TaskRequest) *tasksmanager.TaskRequest {
+	if task.GetPriority() == tasksmanager.TaskPriority_TASK_PRIORITY_UNSPECIFIED {
+		task.Priority = tasksmanager.TaskPriority_TASK_PRIORITY_BULK
+	}
+	return task
+}
+
+// GetSchedulerStats 获取任务调度统计
+func (s *Server) GetSchedulerStats(ctx context.Context, req *tasksmanager.SchedulerStatsRequest) (*tasksmanager.SchedulerStats, error) {
+	return s.scheduler.stats(), nil
+}
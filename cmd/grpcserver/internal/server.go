@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -14,18 +15,28 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
-	"strings"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"crawler-platform/GoogleEarth"
+	pb "crawler-platform/GoogleEarth/pb"
+	"crawler-platform/Store"
 	"crawler-platform/cmd/grpcserver/tasksmanager"
 	"crawler-platform/logger"
+	"crawler-platform/remotedomainippool"
 	"crawler-platform/utlsclient"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 // Server gRPC 服务器结构
@@ -44,7 +55,7 @@ type Server struct {
 	nodes   map[string]*tasksmanager.GrpcServerNodeInfo // 节点信息映射 (key: IP:Port，而不是 UUID)
 	nodesMu sync.RWMutex
 
-	tasks   map[string]*tasksmanager.TaskRequest // 任务映射
+	tasks   map[string]*tasksmanager.TaskRequest // 正在执行的任务映射
 	tasksMu sync.RWMutex
 
 	messages   map[string]*tasksmanager.NodeMessage // 消息队列
@@ -77,7 +88,8 @@ type Server struct {
 	utlsClient *utlsclient.Client
 
 	// 任务执行配置（用于构建 URL 和选择热连接池）
-	// 这些配置字段从 config.go 中的 Config 结构体传递过来
+	// 这些配置字段从 config.go 中的 Config 结构体传递过来，重新加载配置时可修改，由 configMu 保护
+	configMu                     sync.RWMutex
 	rockTreeDataEnable           bool
 	rockTreeDataHostName         string
 	rockTreeDataBulkMetadataPath string
@@ -90,6 +102,81 @@ type Server struct {
 	googleEarthDesktopDataImageryPath string
 	googleEarthDesktopDataTerrainPath string
 
+	// 多数据库（tm/mars/moon/sky）数据配置
+	googleEarthTMHostName         string
+	googleEarthQPPath             string
+	googleEarthImageryHistoryPath string
+
+	// 通用 HTTP 站点（HTTP_SITE 任务，按名称索引）
+	sites map[string]Site
+
+	// 任务可以附加的请求头（规范化名称）
+	allowedRequestHeaders map[string]bool
+
+	// 各任务类型的默认超时时间（客户端未设置截止时间时使用）
+	taskTimeouts map[tasksmanager.TaskType]time.Duration
+
+	// 上游响应体的最大字节数（<=0 表示不限制）
+	maxBodyBytes int64
+
+	// 服务器端瓦片存储（可选，用于持久化上游响应并直接响应重复请求）
+	tileStorage *Store.TileStorage
+
+	// Google Earth 解密密钥同步（任务请求解码时从 dbRoot 获取）
+	cryptKeyMu        sync.RWMutex // 保护 GoogleEarth.CryptKey：解码时持有读锁，更新时持有写锁
+	cryptKeySyncMu    sync.Mutex   // 同一时间只进行一次同步
+	cryptKeySynced    time.Time    // 最近一次同步成功的时间
+	cryptKeyAttempted time.Time    // 最近一次尝试同步的时间
+
+	// 热点瓦片缓存（最近成功的上游响应）与相同并发任务合并
+	hotCache *hotCache
+	flights  flightGroup
+
+	// 上游请求调度（按优先级排队，限制同时请求上游的任务数）
+	scheduler *taskScheduler
+
+	// SubmitTaskStream 单个流允许同时执行的任务数（流控额度）
+	streamMaxInFlight int
+
+	// 任务在服务器节点间转发的最大跳数（0 表示不转发）
+	forwardMaxHops int
+
+	// 作业（服务器后台执行的批量任务）
+	jobs           map[string]*job
+	jobsMu         sync.RWMutex
+	jobStateDir    string // 作业状态持久化目录（为空时不持久化）
+	jobConcurrency int    // 单个作业同时执行的任务数
+
+	// 指标导出（Prometheus 文本格式，通过 HTTP 服务器的 /metrics 访问）
+	taskMetrics      taskMetrics
+	domainMonitor    remotedomainippool.DomainMonitor // 域名 IP 监控器（可选）
+	monitoredDomains []string                         // 域名 IP 监控器监控的域名（由 configMu 保护）
+
+	// HTTP 服务器（指标导出与管理接口）
+	httpServers   []*http.Server
+	httpServersMu sync.Mutex
+	adminWebDir   string // 管理仪表盘静态文件目录
+
+	// 仪表盘实时事件推送（SSE）
+	events       eventHub
+	poolWatching atomic.Bool // 热连接池状态采样协程是否在运行
+
+	// gRPC 健康检查（grpc.health.v1.Health），状态由就绪检查驱动
+	healthServer *health.Server
+	healthStatus healthpb.HealthCheckResponse_ServingStatus // 最近一次设置的状态（用于记录状态变化）
+	healthMu     sync.Mutex
+	ipPoolTarget atomic.Int64 // 本地 IP 池的目标地址数（<=0 表示不检查）
+	draining     atomic.Bool  // 服务器正在排空或停止，不再接受新任务
+	inFlight     atomic.Int64 // 正在执行的任务数（排空时等待其归零）
+
+	// 配置重新加载（由 main 包实现：读取配置文件并应用可在运行时修改的配置）
+	configReloader func() (*tasksmanager.ReloadConfigResponse, error)
+
+	// 客户端认证与授权（API 令牌 -> 身份，客户端证书 CN/DNS 名称 -> 身份）
+	authEnabled        bool
+	authTokens         map[string]AuthIdentity
+	authCertIdentities map[string]AuthIdentity
+
 	// TUIC 服务器配置（用于 GetTUICConfig RPC）
 	tuicEnabled    bool
 	tuicAddress    string
@@ -106,6 +193,8 @@ type IPPoolInterface interface {
 	ReleaseIP(ip net.IP)
 	MarkIPUnused(ip net.IP)
 	SetTargetIPCount(count int)
+	GetActiveIPv6Addresses() []string
+	GetIPv4Addresses() []string
 	Close() error
 }
 
@@ -130,6 +219,12 @@ func NewServerWithTLS(address, port string, tlsConfig *tls.Config) *Server {
 		lastHeartbeatNodes: make(map[string]map[string]bool),
 		tlsConfig:          tlsConfig,
 		logger:             logger.GetGlobalLogger(),
+		hotCache:           newHotCache(defaultHotCacheMaxBytes),
+		scheduler:          newTaskScheduler(),
+		streamMaxInFlight:  defaultStreamMaxInFlight,
+		forwardMaxHops:     defaultForwardMaxHops,
+		jobs:               make(map[string]*job),
+		jobConcurrency:     defaultJobConcurrency,
 	}
 
 	// 注册自己为节点
@@ -213,8 +308,15 @@ func (s *Server) SetUTLSClient(client *utlsclient.Client) {
 	s.utlsClient = client
 }
 
+// GetUTLSClient 获取 UTLS 客户端（可能为 nil）
+func (s *Server) GetUTLSClient() *utlsclient.Client {
+	return s.utlsClient
+}
+
 // SetRockTreeDataConfig 设置 RockTree 数据配置（从 config.go 的 Config 结构体传递）
 func (s *Server) SetRockTreeDataConfig(enable bool, hostName, bulkMetadataPath, nodeDataPath, imageryDataPath string) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
 	s.rockTreeDataEnable = enable
 	s.rockTreeDataHostName = hostName
 	s.rockTreeDataBulkMetadataPath = bulkMetadataPath
@@ -224,6 +326,8 @@ func (s *Server) SetRockTreeDataConfig(enable bool, hostName, bulkMetadataPath,
 
 // SetGoogleEarthDesktopDataConfig 设置 Google Earth Desktop 数据配置（从 config.go 的 Config 结构体传递）
 func (s *Server) SetGoogleEarthDesktopDataConfig(enable bool, hostName, q2Path, imageryPath, terrainPath string) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
 	s.googleEarthDesktopDataEnable = enable
 	s.googleEarthDesktopDataHostName = hostName
 	s.googleEarthDesktopDataQ2Path = q2Path
@@ -231,6 +335,15 @@ func (s *Server) SetGoogleEarthDesktopDataConfig(enable bool, hostName, q2Path,
 	s.googleEarthDesktopDataTerrainPath = terrainPath
 }
 
+// SetGoogleEarthMultiDBDataConfig 设置多数据库（QP、历史影像）数据配置
+func (s *Server) SetGoogleEarthMultiDBDataConfig(tmHostName, qpPath, imageryHistoryPath string) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.googleEarthTMHostName = tmHostName
+	s.googleEarthQPPath = qpPath
+	s.googleEarthImageryHistoryPath = imageryHistoryPath
+}
+
 // generateNodeID 生成节点 ID
 func generateNodeID() string {
 	return uuid.New().String()
@@ -326,14 +439,29 @@ func (s *Server) Start() error {
 	opts = append(opts, grpc.KeepaliveEnforcementPolicy(keepaliveEnforcementPolicy))
 	opts = append(opts, grpc.KeepaliveParams(keepaliveServerParams))
 
+	// 启用认证时，所有调用都需要携带有效的 API 令牌或客户端证书，并按接口检查角色
+	if s.authEnabled {
+		opts = append(opts, grpc.ChainUnaryInterceptor(s.authUnaryInterceptor))
+		opts = append(opts, grpc.ChainStreamInterceptor(s.authStreamInterceptor))
+		s.logger.Info("gRPC 服务器已启用认证: %d 个 API 令牌, %d 个证书身份", len(s.authTokens), len(s.authCertIdentities))
+	}
+
 	s.grpcServer = grpc.NewServer(opts...)
 	tasksmanager.RegisterTasksManagerServer(s.grpcServer, s)
 
+	// 注册健康检查（供负载均衡器探测）与服务反射（供 grpcurl 等工具使用）
+	s.healthServer = newHealthServer()
+	healthpb.RegisterHealthServer(s.grpcServer, s.healthServer)
+	reflection.Register(s.grpcServer)
+
 	s.logger.Info("gRPC 服务器启动在 %s:%s", s.address, s.port)
 
 	// 启动心跳检查
 	go s.startHeartbeatChecker()
 
+	// 启动健康状态检查
+	go s.runHealthChecker()
+
 	return s.grpcServer.Serve(lis)
 }
 
@@ -341,6 +469,12 @@ func (s *Server) Start() error {
 func (s *Server) Stop() {
 	s.logger.Info("开始停止服务器...")
 
+	// 未经 Drain 直接停止时同样拒绝新任务，健康检查立即返回 NOT_SERVING
+	s.draining.Store(true)
+	if s.healthServer != nil {
+		s.healthServer.Shutdown()
+	}
+
 	// 停止节点连接管理器
 	if s.nodeConnector != nil {
 		s.logger.Info("正在停止节点连接管理器...")
@@ -348,16 +482,6 @@ func (s *Server) Stop() {
 		s.logger.Info("节点连接管理器已停止")
 	}
 
-	// 关闭 IP 池（如果存在）
-	if s.ipPool != nil {
-		s.logger.Info("正在关闭 IP 池...")
-		if err := s.ipPool.Close(); err != nil {
-			s.logger.Warn("关闭 IP 池时出错: %v", err)
-		} else {
-			s.logger.Info("IP 池已关闭")
-		}
-	}
-
 	if s.grpcServer != nil {
 		s.logger.Info("正在停止 gRPC 服务器（优雅关闭，最多等待 10 秒）...")
 		// 使用带超时的优雅关闭
@@ -378,6 +502,25 @@ func (s *Server) Stop() {
 		}
 	}
 
+	// 保存作业状态（正在执行的任务在下次启动时重新执行）
+	s.saveAllJobs()
+
+	// 先关闭热连接池，再清理 IP 池中创建的 IPv6 地址（热连接绑定在这些地址上）
+	if s.utlsClient != nil {
+		s.logger.Info("正在停止 UTLS 热连接池...")
+		s.utlsClient.Stop()
+	}
+
+	// 关闭 IP 池（如果存在）
+	if s.ipPool != nil {
+		s.logger.Info("正在关闭 IP 池...")
+		if err := s.ipPool.Close(); err != nil {
+			s.logger.Warn("关闭 IP 池时出错: %v", err)
+		} else {
+			s.logger.Info("IP 池已关闭")
+		}
+	}
+
 	s.logger.Info("服务器停止完成")
 }
 
@@ -474,9 +617,68 @@ func (s *Server) GetGrpcServerNodeInfoList(ctx context.Context, req *tasksmanage
 
 // buildPathForTask 根据任务类型和参数构建路径（不包含域名，只返回路径部分）
 // 返回: dataType, hostName, path, error
-func (s *Server) buildPathForTask(taskType tasksmanager.TaskType, tileKey string, epoch int32, imageryEpoch *int32) (string, string, string, error) {
+func (s *Server) buildPathForTask(req *tasksmanager.TaskRequest, tileKey string, epoch int32, imageryEpoch *int32) (string, string, string, error) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+
 	// 根据任务类型选择配置和路径模板
-	switch taskType {
+	switch taskType := req.TaskType; taskType {
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_ROCKTREE_BULK_METADATA:
+		if !s.rockTreeDataEnable {
+			return "", "", "", fmt.Errorf("RockTreeData 配置未启用")
+		}
+		// BulkMetadata 以 4 级为一个批次，路径长度必须是 4 的倍数（根节点为空路径）
+		if err := validateOctantPath(tileKey); err != nil {
+			return "", "", "", err
+		}
+		if len(tileKey)%4 != 0 {
+			return "", "", "", fmt.Errorf("BulkMetadata 路径长度必须是 4 的倍数: %q", tileKey)
+		}
+		hostName := s.rockTreeDataHostName
+		pathTemplate := s.rockTreeDataBulkMetadataPath
+		if hostName == "" || pathTemplate == "" {
+			return "", "", "", fmt.Errorf("RockTreeData BulkMetadata 配置不完整")
+		}
+		path := fmt.Sprintf(pathTemplate, tileKey, epoch)
+		return "RockTreeData", hostName, path, nil
+
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_ROCKTREE_NODE_DATA:
+		if !s.rockTreeDataEnable {
+			return "", "", "", fmt.Errorf("RockTreeData 配置未启用")
+		}
+		if err := validateOctantPath(tileKey); err != nil {
+			return "", "", "", err
+		}
+		if tileKey == "" {
+			return "", "", "", fmt.Errorf("NodeData 任务需要非空的八叉树路径")
+		}
+		textureFormat := int32(pb.Texture_JPG)
+		if req.TextureFormat != nil {
+			textureFormat = *req.TextureFormat
+		}
+		if _, ok := pb.Texture_Format_name[textureFormat]; !ok {
+			return "", "", "", fmt.Errorf("不支持的纹理格式: %d", textureFormat)
+		}
+		hostName := s.rockTreeDataHostName
+		if hostName == "" {
+			return "", "", "", fmt.Errorf("RockTreeData NodeData 配置不完整")
+		}
+		// 指定了影像版本时使用带 imageryEpoch 的模板，否则使用普通 NodeData 模板
+		if imageryEpoch != nil {
+			pathTemplate := s.rockTreeDataImageryDataPath
+			if pathTemplate == "" {
+				return "", "", "", fmt.Errorf("RockTreeData ImageryData 配置不完整")
+			}
+			path := fmt.Sprintf(pathTemplate, tileKey, epoch, textureFormat, *imageryEpoch)
+			return "RockTreeData", hostName, path, nil
+		}
+		pathTemplate := s.rockTreeDataNodeDataPath
+		if pathTemplate == "" {
+			return "", "", "", fmt.Errorf("RockTreeData NodeData 配置不完整")
+		}
+		path := fmt.Sprintf(pathTemplate, tileKey, epoch, textureFormat)
+		return "RockTreeData", hostName, path, nil
+
 	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2:
 		if !s.googleEarthDesktopDataEnable {
 			return "", "", "", fmt.Errorf("GoogleEarthDesktopData 配置未启用")
@@ -516,16 +718,98 @@ func (s *Server) buildPathForTask(taskType tasksmanager.TaskType, tileKey string
 		path := fmt.Sprintf(pathTemplate, tileKey, epoch)
 		return "GoogleEarthDesktopData", hostName, path, nil
 
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_QP:
+		if !s.googleEarthDesktopDataEnable {
+			return "", "", "", fmt.Errorf("GoogleEarthDesktopData 配置未启用")
+		}
+		dbName := GoogleEarth.TM
+		if req.GetDbName() != "" {
+			dbName = req.GetDbName()
+		}
+		switch dbName {
+		case GoogleEarth.TM, GoogleEarth.MARS, GoogleEarth.MOON, GoogleEarth.SKY:
+		default:
+			return "", "", "", fmt.Errorf("不支持的数据库名称: %s", dbName)
+		}
+		hostName := s.googleEarthTMHostName
+		pathTemplate := s.googleEarthQPPath
+		if hostName == "" || pathTemplate == "" {
+			return "", "", "", fmt.Errorf("GoogleEarthDesktopData QP 配置不完整")
+		}
+		path := fmt.Sprintf(pathTemplate, dbName, tileKey, epoch)
+		return "GoogleEarthDesktopData", hostName, path, nil
+
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY_HISTORY:
+		if !s.googleEarthDesktopDataEnable {
+			return "", "", "", fmt.Errorf("GoogleEarthDesktopData 配置未启用")
+		}
+		if imageryEpoch == nil {
+			return "", "", "", fmt.Errorf("ImageryHistory 任务需要 imageryEpoch 参数")
+		}
+		dateHex := req.GetDateHex()
+		if dateHex == "" {
+			return "", "", "", fmt.Errorf("ImageryHistory 任务需要 date_hex 参数")
+		}
+		if _, err := strconv.ParseUint(dateHex, 16, 64); err != nil {
+			return "", "", "", fmt.Errorf("无效的十六进制日期: %q", dateHex)
+		}
+		hostName := s.googleEarthTMHostName
+		pathTemplate := s.googleEarthImageryHistoryPath
+		if hostName == "" || pathTemplate == "" {
+			return "", "", "", fmt.Errorf("GoogleEarthDesktopData ImageryHistory 配置不完整")
+		}
+		path := fmt.Sprintf(pathTemplate, tileKey, *imageryEpoch, dateHex)
+		return "GoogleEarthDesktopData", hostName, path, nil
+
+	case tasksmanager.TaskType_TASK_TYPE_HTTP_SITE:
+		return s.buildSitePath(req)
+
 	default:
 		return "", "", "", fmt.Errorf("不支持的任务类型: %v", taskType)
 	}
 }
 
+// taskMethodName 返回任务的 HTTP 方法（未指定时为 GET）
+func taskMethodName(req *tasksmanager.TaskRequest) string {
+	switch req.GetTaskMethod() {
+	case tasksmanager.TaskMethod_TASK_METHOD_POST:
+		return http.MethodPost
+	case tasksmanager.TaskMethod_TASK_METHOD_PUT:
+		return http.MethodPut
+	case tasksmanager.TaskMethod_TASK_METHOD_DELETE:
+		return http.MethodDelete
+	case tasksmanager.TaskMethod_TASK_METHOD_PATCH:
+		return http.MethodPatch
+	case tasksmanager.TaskMethod_TASK_METHOD_HEAD:
+		return http.MethodHead
+	case tasksmanager.TaskMethod_TASK_METHOD_OPTIONS:
+		return http.MethodOptions
+	default:
+		return http.MethodGet
+	}
+}
+
+// validateOctantPath 校验 RockTree 八叉树路径（每一级为 0-7 的数字）
+func validateOctantPath(path string) error {
+	for i := 0; i < len(path); i++ {
+		if path[i] < '0' || path[i] > '7' {
+			return fmt.Errorf("无效的八叉树路径: %q", path)
+		}
+	}
+	return nil
+}
+
 // executeTaskWithHotPool 使用热连接池执行任务
 // 参数: dataType - 数据类型, hostName - 主机名（用于从池中获取连接）, path - 请求路径（包含查询参数）
-func (s *Server) executeTaskWithHotPool(dataType, hostName, path string, req *tasksmanager.TaskRequest) ([]byte, int32, error) {
+// 返回: 响应体, 状态码, 上游响应头, error
+// ctx 取消或超时时停止获取连接、中断正在进行的请求并不再重试
+// consume 读取上游响应体（为 nil 时按任务的响应体大小限制读入内存）
+func (s *Server) executeTaskWithHotPool(ctx context.Context, dataType, hostName, path string, req *tasksmanager.TaskRequest, consume bodyConsumer) ([]byte, int32, http.Header, error) {
 	if s.utlsClient == nil {
-		return nil, 0, fmt.Errorf("UTLS 客户端未设置")
+		return nil, 0, nil, fmt.Errorf("UTLS 客户端未设置")
+	}
+	if consume == nil {
+		consume = bufferedBody(s.bodyLimit(req))
 	}
 
 	// 记录各个阶段的时间
@@ -535,17 +819,13 @@ func (s *Server) executeTaskWithHotPool(dataType, hostName, path string, req *ta
 	requestURL := fmt.Sprintf("https://%s%s", hostName, path)
 
 	// 确定 HTTP 方法
-	method := "GET"
-	if req.TaskMethod != nil {
-		switch *req.TaskMethod {
-		case tasksmanager.TaskMethod_TASK_METHOD_POST:
-			method = "POST"
-		case tasksmanager.TaskMethod_TASK_METHOD_PUT:
-			method = "PUT"
-		case tasksmanager.TaskMethod_TASK_METHOD_DELETE:
-			method = "DELETE"
-		default:
-			method = "GET"
+	method := taskMethodName(req)
+
+	// 通用站点任务携带站点配置的默认请求头
+	var siteHeaders map[string]string
+	if dataType == siteDataType {
+		if site, ok := s.getSite(req.GetSite()); ok {
+			siteHeaders = site.Headers
 		}
 	}
 
@@ -558,26 +838,25 @@ func (s *Server) executeTaskWithHotPool(dataType, hostName, path string, req *ta
 	for attempt := 1; attempt <= maxHTTPRetries; attempt++ {
 		// 每次重试都获取新连接（如果连接已标记为不健康，会获取新连接）
 		connStart := time.Now()
-		newConn, err := s.utlsClient.GetConnectionForHost(hostName)
+		newConn, err := s.utlsClient.GetConnectionForHostContext(ctx, hostName)
 		if err != nil {
 			lastErr = err
-			errStr := err.Error()
 
 			// 统一判断是否需要重试
 			shouldRetry := false
 			var waitTime time.Duration
 
-			// 连接正在使用中：立即返回错误，不重试，减少阻塞
-			if strings.Contains(errStr, "connection is in use") || strings.Contains(errStr, "所有连接当前都在使用中") {
+			// 连接正在使用中或超过速率限制：立即返回错误，不重试，减少阻塞
+			if errors.Is(err, utlsclient.ErrConnectionInUse) || errors.Is(err, utlsclient.ErrRateLimited) {
 				// 立即返回错误，不重试
-				return nil, 0, fmt.Errorf("获取连接失败: %w", err)
+				return nil, 0, nil, fmt.Errorf("获取连接失败: %w", err)
 			}
 
-			if strings.Contains(errStr, "PoolManager正在预热中") {
+			if errors.Is(err, utlsclient.ErrPoolWarming) {
 				// 预热中：等待后重试
 				shouldRetry = true
 				waitTime = 200 * time.Millisecond // 短暂等待，让预热有机会完成
-			} else if strings.Contains(errStr, "所有连接都不健康，正在异步激活") {
+			} else if errors.Is(err, utlsclient.ErrAllConnectionsUnhealthy) {
 				// 连接正在异步激活：短暂等待后重试
 				shouldRetry = true
 				waitTime = 100 * time.Millisecond // 短暂等待，让异步激活有机会完成
@@ -585,12 +864,14 @@ func (s *Server) executeTaskWithHotPool(dataType, hostName, path string, req *ta
 
 			if shouldRetry && attempt < maxHTTPRetries {
 				s.logger.Debug("连接获取失败，等待 %v 后重试 (第 %d 次): %v", waitTime, attempt, err)
-				time.Sleep(waitTime)
+				if ctxErr := sleepContext(ctx, waitTime); ctxErr != nil {
+					return nil, 0, nil, fmt.Errorf("等待连接已取消: %w", ctxErr)
+				}
 				continue
 			}
 
 			// 不需要重试或已达到最大重试次数，立即返回错误
-			return nil, 0, fmt.Errorf("获取连接失败(重试 %d 次后仍失败): %w", attempt-1, err)
+			return nil, 0, nil, fmt.Errorf("获取连接失败(重试 %d 次后仍失败): %w", attempt-1, err)
 		}
 
 		// 释放旧连接（如果有）
@@ -610,10 +891,22 @@ func (s *Server) executeTaskWithHotPool(dataType, hostName, path string, req *ta
 			bodyReader = bytes.NewReader(req.TaskBody)
 		}
 
-		httpReq, err := http.NewRequest(method, requestURL, bodyReader)
+		httpReq, err := http.NewRequestWithContext(ctx, method, requestURL, bodyReader)
 		if err != nil {
 			s.utlsClient.ReleaseConnection(conn)
-			return nil, 0, fmt.Errorf("创建 HTTP 请求失败: %w", err)
+			return nil, 0, nil, fmt.Errorf("创建 HTTP 请求失败: %w", err)
+		}
+
+		// RockTree 接口与通用站点不需要 geauth 会话，避免携带 flatfile 的 SessionId Cookie
+		if dataType == "RockTreeData" || dataType == siteDataType {
+			httpReq = utlsclient.WithoutSessionID(httpReq)
+		}
+		// 请求头优先级：任务附加的请求头 > 站点默认请求头 > 连接补全的指纹默认请求头（HTTP/1.1 请求按指纹的请求头顺序写出）
+		for name, value := range siteHeaders {
+			httpReq.Header.Set(name, value)
+		}
+		for name, value := range req.GetRequestHeaders() {
+			httpReq.Header.Set(name, value)
 		}
 
 		// 设置 Host 头为域名（用于 SNI 和 Host 头）
@@ -642,16 +935,21 @@ func (s *Server) executeTaskWithHotPool(dataType, hostName, path string, req *ta
 			// 释放连接（连接可能已损坏）
 			s.utlsClient.ReleaseConnection(conn)
 			conn = nil
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, 0, nil, fmt.Errorf("HTTP 请求已取消: %w", ctxErr)
+			}
 
 			// 对于网络错误，短暂等待后重试（让连接池有机会恢复）
 			if attempt < maxHTTPRetries {
 				waitTime := 50 * time.Millisecond // 减少等待时间，从200ms减少到50ms
 				s.logger.Debug("HTTP 请求失败，等待 %v 后重试 (第 %d 次): %v", waitTime, attempt, err)
-				time.Sleep(waitTime)
+				if ctxErr := sleepContext(ctx, waitTime); ctxErr != nil {
+					return nil, 0, nil, fmt.Errorf("HTTP 请求已取消: %w", ctxErr)
+				}
 				continue
 			}
 			// 最后一次重试失败，返回错误
-			return nil, 0, fmt.Errorf("HTTP 请求失败(重试 %d 次后仍失败): %w", attempt, err)
+			return nil, 0, nil, fmt.Errorf("HTTP 请求失败(重试 %d 次后仍失败): %w", attempt, err)
 		}
 
 		// 确保响应体在所有情况下都被关闭，防止文件描述符泄漏
@@ -667,19 +965,30 @@ func (s *Server) executeTaskWithHotPool(dataType, hostName, path string, req *ta
 		s.logger.Info("[任务请求成功] 本地IPv6=%s, 远程IPv6=%s, 已完成请求数=%d, 耗时=%v, 状态码=%d, 路径=%s",
 			getIPDisplay(localIP), getIPDisplay(remoteIP), requestCount, requestDuration, resp.StatusCode, path)
 
-		// 成功拿到响应，读取响应体
-		responseBody, readErr := io.ReadAll(resp.Body)
+		// 成功拿到响应，读取响应体（释放连接前关闭响应体，未读完的 HTTP/1.1 连接不会被复用）
+		responseBody, retryable, readErr := consume(resp)
 		if readErr != nil {
+			resp.Body.Close()
+			readErr = utlsclient.WrapTransportError(readErr)
 			lastErr = readErr
 			// 释放连接
 			s.utlsClient.ReleaseConnection(conn)
 			conn = nil
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, 0, nil, fmt.Errorf("读取响应体已取消: %w", ctxErr)
+			}
+			// 响应体超过大小限制或已开始流式发送时不重试
+			if !retryable {
+				return nil, 0, nil, fmt.Errorf("读取响应体失败: %w", readErr)
+			}
 			if attempt < maxHTTPRetries {
 				s.logger.Warn("读取响应体失败(第 %d 次，将重试): %v", attempt, readErr)
-				time.Sleep(50 * time.Millisecond) // 减少等待时间
+				if ctxErr := sleepContext(ctx, 50*time.Millisecond); ctxErr != nil { // 减少等待时间
+					return nil, 0, nil, fmt.Errorf("读取响应体已取消: %w", ctxErr)
+				}
 				continue
 			}
-			return nil, 0, fmt.Errorf("读取响应体失败(重试 %d 次后仍失败): %w", attempt, readErr)
+			return nil, 0, nil, fmt.Errorf("读取响应体失败(重试 %d 次后仍失败): %w", attempt, readErr)
 		}
 
 		// 对 5xx 做有限次重试
@@ -688,26 +997,68 @@ func (s *Server) executeTaskWithHotPool(dataType, hostName, path string, req *ta
 			s.utlsClient.ReleaseConnection(conn)
 			conn = nil
 			s.logger.Warn("后端返回状态码 %d (第 %d 次)，将重试，请求URL=%s", resp.StatusCode, attempt, requestURL)
-			time.Sleep(50 * time.Millisecond) // 减少等待时间
+			if ctxErr := sleepContext(ctx, 50*time.Millisecond); ctxErr != nil { // 减少等待时间
+				return nil, 0, nil, fmt.Errorf("HTTP 请求已取消: %w", ctxErr)
+			}
 			continue
 		}
 
 		// 正常返回，释放连接
 		s.utlsClient.ReleaseConnection(conn)
-		return responseBody, int32(resp.StatusCode), nil
+		// 403 与重试后仍为 5xx 的响应作为任务执行失败返回（保留状态码和响应体）
+		if isUpstreamErrorStatus(resp.StatusCode) {
+			return responseBody, int32(resp.StatusCode), resp.Header, &upstreamStatusError{statusCode: int32(resp.StatusCode), body: responseBody}
+		}
+		return responseBody, int32(resp.StatusCode), resp.Header, nil
 	}
 
 	// 理论上不会走到这里，如果走到这里，返回最后一次错误
-	return nil, 0, fmt.Errorf("HTTP 请求失败: %v", lastErr)
+	return nil, 0, nil, fmt.Errorf("HTTP 请求失败: %w", lastErr)
 }
 
-// SubmitTask 提交任务请求
+// SubmitTask 提交任务请求（记录任务计数与耗时指标）
 func (s *Server) SubmitTask(ctx context.Context, req *tasksmanager.TaskRequest) (*tasksmanager.TaskResponse, error) {
+	// 排空期间拒绝新任务（可重试错误，客户端应提交到其他节点）
+	if !s.enterTask() {
+		err := taskErrorStatus(&tasksmanager.TaskResponse{
+			TaskClientId: req.TaskClientId,
+			TaskType:     req.TaskType,
+			TileKey:      req.TileKey,
+		}, ErrServerDraining)
+		s.taskMetrics.observeTask(req.TaskType, nil, err, 0)
+		return nil, err
+	}
+	defer s.exitTask()
+	return s.runTask(ctx, req)
+}
+
+// runTask 执行任务并记录指标与事件（作业中的任务直接调用，由作业自己记录正在执行的任务）
+func (s *Server) runTask(ctx context.Context, req *tasksmanager.TaskRequest) (*tasksmanager.TaskResponse, error) {
+	start := time.Now()
+	resp, err := s.submitTask(ctx, req)
+	duration := time.Since(start)
+	s.taskMetrics.observeTask(req.TaskType, resp, err, duration)
+	s.publishTaskEvent(req, resp, err, duration)
+	return resp, err
+}
+
+// submitTask 执行任务请求
+func (s *Server) submitTask(ctx context.Context, req *tasksmanager.TaskRequest) (*tasksmanager.TaskResponse, error) {
 	taskID := generateTaskID()
 
+	// 客户端未设置截止时间时使用该任务类型的默认超时，客户端取消后不再占用上游连接
+	ctx, cancel := s.withTaskTimeout(ctx, req.TaskType)
+	defer cancel()
+
+	// tasks 只记录正在执行的任务，任务结束后移除（批量任务的历史状态由作业记录）
 	s.tasksMu.Lock()
 	s.tasks[taskID] = req
 	s.tasksMu.Unlock()
+	defer func() {
+		s.tasksMu.Lock()
+		delete(s.tasks, taskID)
+		s.tasksMu.Unlock()
+	}()
 
 	// 使用反射或直接字段访问获取 TileKey、epoch 等字段
 	// 注意：proto 文件需要重新生成后才能访问这些字段
@@ -735,53 +1086,99 @@ func (s *Server) SubmitTask(ctx context.Context, req *tasksmanager.TaskRequest)
 	//	taskID, req.TaskType, tileKey, epoch, taskLength, totalLength, formatBytes(totalLength))
 
 	// 构建路径（不包含域名，只返回路径部分）
-	dataType, hostName, path, err := s.buildPathForTask(req.TaskType, tileKey, epoch, imageryEpoch)
+	dataType, hostName, path, err := s.buildPathForTask(req, tileKey, epoch, imageryEpoch)
 	if err != nil {
 		return nil, fmt.Errorf("构建路径失败: %w", err)
 	}
+	if err := validateDecodeMode(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.validateRequestHeaders(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	//s.logger.Debug("任务 %s 构建的路径: %s (数据类型: %s, 主机名: %s)", taskID, path, dataType, hostName)
 
+	// 热点缓存或存储中已有相同版本的数据时直接返回，不访问上游
+	localResponse := func(body []byte, source tasksmanager.TaskResponseSource) (*tasksmanager.TaskResponse, error) {
+		statusCode := int32(http.StatusOK)
+		response := &tasksmanager.TaskResponse{
+			TaskClientId:           req.TaskClientId,
+			TaskType:               req.TaskType,
+			TaskResponseStatusCode: &statusCode,
+			ResponseSource:         source,
+		}
+		s.setResponseParams(response, tileKey, epoch, imageryEpoch)
+		if limit := s.bodyLimit(req); limit > 0 && int64(len(body)) > limit {
+			return nil, taskErrorStatus(response, bodyTooLarge(limit))
+		}
+		decodedBody, err := s.decodeTaskBody(req.TaskType, req.GetDecode(), tileKey, body)
+		if err != nil {
+			return nil, taskErrorStatus(response, err)
+		}
+		response.TaskResponseBody = decodedBody
+		return response, nil
+	}
+	upstreamKey, coalesce := upstreamKeyForTask(req, hostName, path)
+	if coalesce {
+		if cachedBody, ok := s.hotCache.get(upstreamKey); ok {
+			return localResponse(cachedBody, tasksmanager.TaskResponseSource_TASK_RESPONSE_SOURCE_CACHE)
+		}
+	}
+	if storedBody, ok := s.getTaskFromStorage(req, tileKey, epoch, imageryEpoch); ok {
+		if coalesce {
+			s.hotCache.put(upstreamKey, storedBody)
+		}
+		return localResponse(storedBody, tasksmanager.TaskResponseSource_TASK_RESPONSE_SOURCE_STORAGE)
+	}
+
 	// 使用热连接池执行任务（通过主机名获取连接，使用 IP 地址直接访问）
-	responseBody, statusCode, err := s.executeTaskWithHotPool(dataType, hostName, path, req)
+	// 上游并发已满时按任务优先级排队；存储中始终保存上游原始数据，解码只作用于返回给客户端的响应体
+	// 上游响应头只返回给实际请求上游的任务（合并的任务与缓存、存储命中不返回）
+	fetch := func(fetchCtx context.Context) ([]byte, int32, []*tasksmanager.TaskHeader, error) {
+		var headers []*tasksmanager.TaskHeader
+		body, statusCode, err := s.scheduler.run(fetchCtx, req.GetPriority(), func() ([]byte, int32, error) {
+			body, statusCode, header, err := s.executeTaskWithHotPool(fetchCtx, dataType, hostName, path, req, nil)
+			headers = taskResponseHeaders(header)
+			return body, statusCode, err
+		})
+		// 本节点热连接池饱和、等待队列已满或没有该主机的可用连接时，转发到负载最低的其他节点
+		if err != nil && isForwardableError(err) {
+			if forwardBody, forwardStatusCode, forwardHeaders, forwardErr, forwarded := s.forwardTask(fetchCtx, req); forwarded {
+				body, statusCode, headers, err = forwardBody, forwardStatusCode, forwardHeaders, forwardErr
+			}
+		}
+		if err == nil && statusCode == http.StatusOK {
+			s.putTaskToStorage(req, tileKey, epoch, imageryEpoch, body)
+			if coalesce {
+				s.hotCache.put(upstreamKey, body)
+			}
+		}
+		return body, statusCode, headers, err
+	}
+	// 相同的并发任务只请求一次上游，其他调用方等待并共享结果
+	// 合并的上游请求不因单个任务被取消而中断（受调用方截止时间与该任务类型默认超时中较晚者限制），所有等待的任务都离开后才取消
+	var responseBody []byte
+	var statusCode int32
+	var responseHeaders []*tasksmanager.TaskHeader
+	if coalesce {
+		responseBody, statusCode, responseHeaders, err, _ = s.flights.do(ctx, upstreamKey, s.flightTimeout(ctx, req.TaskType), fetch)
+	} else {
+		responseBody, statusCode, responseHeaders, err = fetch(ctx)
+	}
 	if err != nil {
-		// 检查是否是连接问题（应该继续重试，而不是返回 500）
-		errStr := err.Error()
-		isConnectionError := strings.Contains(errStr, "获取连接失败") ||
-			strings.Contains(errStr, "没有可用连接") ||
-			strings.Contains(errStr, "所有连接都不健康") ||
-			strings.Contains(errStr, "HTTP 请求失败") ||
-			strings.Contains(errStr, "use of closed network connection") ||
-			strings.Contains(errStr, "connection closed") ||
-			strings.Contains(errStr, "broken pipe") ||
-			strings.Contains(errStr, "连接已标记为不健康")
-
-		if isConnectionError {
-			// 连接问题，不应该返回 500，应该继续重试
-			// 但这里已经重试了 10 次，说明连接池可能有问题
-			// 记录详细日志，但不返回 500（返回一个临时错误，让客户端知道需要重试）
-			s.logger.Warn("任务执行失败（连接问题，已重试10次）: %s, 错误: %v", taskID, err)
-			// 返回 503 Service Unavailable，表示服务暂时不可用，客户端可以重试
-			errorStatusCode := int32(503)
-			errorBody := []byte(fmt.Sprintf("服务暂时不可用，请稍后重试: %v", err))
-			return &tasksmanager.TaskResponse{
-				TaskClientId:           req.TaskClientId,
-				TaskType:               req.TaskType,
-				TaskResponseBody:       errorBody,
-				TaskResponseStatusCode: &errorStatusCode,
-			}, nil
-		}
-
-		// 其他错误（如配置错误），返回 500
-		s.logger.Warn("任务执行失败: %s, 错误: %v", taskID, err)
-		errorStatusCode := int32(500)
-		errorBody := []byte(fmt.Sprintf("任务执行失败: %v", err))
-		return &tasksmanager.TaskResponse{
+		errorStatusCode, errorBody := taskFailureBody(err)
+		s.logger.Warn("任务执行失败: %s, 错误码: %v, 错误: %v", taskID, taskErrorCode(err), err)
+
+		response := &tasksmanager.TaskResponse{
 			TaskClientId:           req.TaskClientId,
 			TaskType:               req.TaskType,
 			TaskResponseBody:       errorBody,
 			TaskResponseStatusCode: &errorStatusCode,
-		}, nil
+			ResponseHeaders:        responseHeaders,
+		}
+		s.setResponseParams(response, tileKey, epoch, imageryEpoch)
+		return nil, taskErrorStatus(response, err)
 	}
 
 	//s.logger.Debug("任务 %s 执行成功，状态码: %d, 响应体长度: %d 字节", taskID, statusCode, len(responseBody))
@@ -790,13 +1187,20 @@ func (s *Server) SubmitTask(ctx context.Context, req *tasksmanager.TaskRequest)
 	response := &tasksmanager.TaskResponse{
 		TaskClientId:           req.TaskClientId,
 		TaskType:               req.TaskType,
-		TaskResponseBody:       responseBody,
 		TaskResponseStatusCode: &statusCode,
+		ResponseHeaders:        responseHeaders,
 	}
 
 	// 设置 TileKey、epoch 等字段（需要 proto 重新生成后支持）
 	s.setResponseParams(response, tileKey, epoch, imageryEpoch)
 
+	if statusCode == http.StatusOK {
+		if responseBody, err = s.decodeTaskBody(req.TaskType, req.GetDecode(), tileKey, responseBody); err != nil {
+			return nil, taskErrorStatus(response, err)
+		}
+	}
+	response.TaskResponseBody = responseBody
+
 	return response, nil
 }
 
@@ -973,6 +1377,14 @@ func (s *Server) ClientHeartbeat(ctx context.Context, clientInfo *tasksmanager.T
 		s.logger.Debug("向客户端 %s 返回新发现的节点: %s (UUID: %s)", clientInfo.ClientUuid, nodeAddr, node.NodeUuid)
 	}
 
+	// 找出客户端已知但已离开（或正在停止）的服务器节点
+	var removedServerNodes []string
+	for nodeAddr := range knownNodes {
+		if node, ok := s.nodes[nodeAddr]; !ok || !s.isServerNode(node.NodeUuid) {
+			removedServerNodes = append(removedServerNodes, nodeAddr)
+		}
+	}
+
 	// 更新该客户端已知的服务器节点列表（使用 IP:Port 作为 key）
 	s.lastHeartbeatNodes[clientKnownNodesKey] = make(map[string]bool)
 	for nodeAddr, node := range s.nodes {
@@ -989,9 +1401,14 @@ func (s *Server) ClientHeartbeat(ctx context.Context, clientInfo *tasksmanager.T
 		}
 	}
 
+	if len(removedServerNodes) > 0 {
+		s.logger.Info("向客户端 %s 返回 %d 个已离开的服务器节点: %v", clientInfo.ClientUuid, len(removedServerNodes), removedServerNodes)
+	}
+
 	return &tasksmanager.ClientHeartbeatResponse{
-		Success:        true,
-		NewServerNodes: newServerNodes,
+		Success:            true,
+		NewServerNodes:     newServerNodes,
+		RemovedServerNodes: removedServerNodes,
 	}, nil
 }
 
@@ -1006,7 +1423,8 @@ func (s *Server) RegisterNode(ctx context.Context, req *tasksmanager.NodeRegistr
 	// 只处理服务器节点
 	s.nodesMu.Lock()
 	// 如果节点已存在（相同 IP:Port），更新节点信息（UUID 可能已变化）
-	if existingNode, exists := s.nodes[nodeAddr]; exists {
+	existingNode, exists := s.nodes[nodeAddr]
+	if exists {
 		s.logger.Info("节点 %s 已存在，更新节点信息 (UUID: %s -> %s)", nodeAddr, existingNode.NodeUuid, nodeInfo.NodeUuid)
 	}
 	s.nodes[nodeAddr] = nodeInfo
@@ -1015,6 +1433,9 @@ func (s *Server) RegisterNode(ctx context.Context, req *tasksmanager.NodeRegistr
 	s.nodesMu.Unlock()
 
 	s.logger.Info("服务器节点注册: %s (UUID: %s)", nodeAddr, nodeInfo.NodeUuid)
+	if !exists {
+		s.publishNodeEvent(nodeAddr, nodeInfo.NodeUuid, "joined")
+	}
 
 	// 记录节点注册时间
 	s.nodeRegisterTimes[nodeInfo.NodeUuid] = time.Now()
@@ -1061,6 +1482,7 @@ func (s *Server) NodeHeartbeat(ctx context.Context, req *tasksmanager.NodeHeartb
 	nodeAddr := s.getNodeAddr(req.NodeInfo)
 
 	// 只处理服务器节点的心跳
+	joined := false
 	s.nodesMu.Lock()
 	if node, exists := s.nodes[nodeAddr]; exists {
 		// 节点已存在，更新节点信息（UUID 可能已变化）
@@ -1088,9 +1510,13 @@ func (s *Server) NodeHeartbeat(ctx context.Context, req *tasksmanager.NodeHeartb
 			req.NodeInfo.NodeLastActiveTime = time.Now().Format(time.RFC3339)
 			s.nodeRegisterTimes[nodeAddr] = time.Now()
 			s.logger.Info("通过心跳发现新节点: %s (UUID: %s)", nodeAddr, req.NodeUuid)
+			joined = true
 		}
 	}
 	s.nodesMu.Unlock()
+	if joined {
+		s.publishNodeEvent(nodeAddr, req.NodeUuid, "joined")
+	}
 
 	s.logger.Debug("收到服务器节点心跳: %s (UUID: %s)", nodeAddr, req.NodeUuid)
 
@@ -1147,6 +1573,11 @@ func (s *Server) NodeHeartbeat(ctx context.Context, req *tasksmanager.NodeHeartb
 func (s *Server) SendNodeMessage(ctx context.Context, req *tasksmanager.NodeMessageRequest) (*tasksmanager.NodeMessageResponse, error) {
 	msg := req.Message
 
+	// 其他节点正在停止，移除该节点，不再向其转发任务
+	if msg.MessageType == nodeLeavingMessageType {
+		s.removeLeavingNode(msg)
+	}
+
 	// 如果是广播消息
 	if msg.ToNodeUuid == "" {
 		// 存储消息，等待其他节点拉取
@@ -1286,8 +1717,10 @@ func (s *Server) startHeartbeatChecker() {
 	defer ticker.Stop()
 
 	selfAddr := s.getSelfAddr()
+	offline := make(map[string]bool) // 已判定为离线的节点地址（用于只在状态变化时发布事件）
 	for range ticker.C {
 		now := time.Now()
+		var events []nodeEvent
 		s.nodesMu.Lock()
 		for nodeAddr, node := range s.nodes {
 			// 跳过自己
@@ -1301,9 +1734,20 @@ func (s *Server) startHeartbeatChecker() {
 				s.logger.Warn("节点超时，可能已离线: %s (UUID: %s, 最后活跃: %s)", nodeAddr, node.NodeUuid, node.NodeLastActiveTime)
 				// 注意：这里只记录警告，不自动移除节点，因为可能是网络波动
 				// 节点会在下次心跳时自动恢复
+				if !offline[nodeAddr] {
+					offline[nodeAddr] = true
+					events = append(events, nodeEvent{Address: nodeAddr, NodeUUID: node.NodeUuid, Action: "offline"})
+				}
+			} else if offline[nodeAddr] {
+				delete(offline, nodeAddr)
+				events = append(events, nodeEvent{Address: nodeAddr, NodeUUID: node.NodeUuid, Action: "online"})
 			}
 		}
 		s.nodesMu.Unlock()
+
+		for _, ev := range events {
+			s.publishNodeEvent(ev.Address, ev.NodeUUID, ev.Action)
+		}
 	}
 }
 
@@ -0,0 +1,178 @@
+package grpcserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+)
+
+// siteDataType 通用 HTTP 站点任务的数据类型（用于日志与请求处理）
+const siteDataType = "HttpSite"
+
+// Site 通用 HTTP 站点（HTTP_SITE 任务通过名称引用，复用热连接池、指纹与 IPv6 轮换）
+type Site struct {
+	Name           string
+	HostName       string
+	PathTemplates  map[string]string // 模板名 -> 路径模板（{参数名} 为占位符）
+	Headers        map[string]string // 每个请求携带的默认请求头
+	AllowedMethods []string          // 允许的 HTTP 方法（为空时只允许 GET）
+	AllowFullPath  bool              // 是否允许任务直接指定完整路径
+}
+
+// ValidateSite 检查站点配置：名称与主机名不能为空，路径模板以 / 开头且占位符格式正确，HTTP 方法有效
+func ValidateSite(site Site) error {
+	if site.Name == "" {
+		return fmt.Errorf("站点名称不能为空")
+	}
+	if site.HostName == "" {
+		return fmt.Errorf("站点 %s 未配置主机名", site.Name)
+	}
+	for name, template := range site.PathTemplates {
+		if _, err := templateParams(template); err != nil {
+			return fmt.Errorf("站点 %s 的路径模板 %s 无效: %w", site.Name, name, err)
+		}
+	}
+	for _, method := range site.AllowedMethods {
+		if _, ok := tasksmanager.TaskMethod_value["TASK_METHOD_"+strings.ToUpper(method)]; !ok {
+			return fmt.Errorf("站点 %s 的 HTTP 方法 %q 不受支持", site.Name, method)
+		}
+	}
+	return nil
+}
+
+// SetSites 设置通用 HTTP 站点（重新加载配置时可修改）
+func (s *Server) SetSites(sites []Site) error {
+	bySite := make(map[string]Site, len(sites))
+	for _, site := range sites {
+		if err := ValidateSite(site); err != nil {
+			return err
+		}
+		if _, exists := bySite[site.Name]; exists {
+			return fmt.Errorf("站点名称重复: %s", site.Name)
+		}
+		bySite[site.Name] = site
+	}
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.sites = bySite
+	return nil
+}
+
+// getSite 按名称获取站点
+func (s *Server) getSite(name string) (Site, bool) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	site, ok := s.sites[name]
+	return site, ok
+}
+
+// buildSitePath 为 HTTP_SITE 任务构建路径：使用站点的路径模板与参数，或站点允许时直接使用完整路径
+// 调用方需持有 configMu 读锁
+func (s *Server) buildSitePath(req *tasksmanager.TaskRequest) (string, string, string, error) {
+	site, ok := s.sites[req.GetSite()]
+	if !ok {
+		return "", "", "", fmt.Errorf("未配置的站点: %q", req.GetSite())
+	}
+	method := taskMethodName(req)
+	if !site.allowsMethod(method) {
+		return "", "", "", fmt.Errorf("站点 %s 不允许 %s 请求", site.Name, method)
+	}
+
+	if req.Path != nil {
+		if req.PathTemplate != nil {
+			return "", "", "", fmt.Errorf("path 与 path_template 不能同时指定")
+		}
+		if !site.AllowFullPath {
+			return "", "", "", fmt.Errorf("站点 %s 不允许直接指定完整路径", site.Name)
+		}
+		path := req.GetPath()
+		if !strings.HasPrefix(path, "/") {
+			return "", "", "", fmt.Errorf("路径必须以 / 开头: %q", path)
+		}
+		return siteDataType, site.HostName, path, nil
+	}
+
+	template, ok := site.PathTemplates[req.GetPathTemplate()]
+	if !ok {
+		return "", "", "", fmt.Errorf("站点 %s 没有路径模板 %q", site.Name, req.GetPathTemplate())
+	}
+	path, err := expandPathTemplate(template, req.GetPathParams())
+	if err != nil {
+		return "", "", "", fmt.Errorf("站点 %s 的路径模板 %s: %w", site.Name, req.GetPathTemplate(), err)
+	}
+	return siteDataType, site.HostName, path, nil
+}
+
+// allowsMethod 站点是否允许该 HTTP 方法
+func (site Site) allowsMethod(method string) bool {
+	if len(site.AllowedMethods) == 0 {
+		return method == http.MethodGet
+	}
+	for _, m := range site.AllowedMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// templateParams 解析路径模板中的 {参数名} 占位符，返回参数名列表
+func templateParams(template string) ([]string, error) {
+	if !strings.HasPrefix(template, "/") {
+		return nil, fmt.Errorf("路径模板必须以 / 开头: %q", template)
+	}
+	var params []string
+	rest := template
+	for {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			return params, nil
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("路径模板中有多余的 }: %q", template)
+		}
+		end := strings.IndexAny(rest[open+1:], "{}")
+		if end < 0 || rest[open+1+end] != '}' {
+			return nil, fmt.Errorf("路径模板中的 { 没有闭合: %q", template)
+		}
+		name := rest[open+1 : open+1+end]
+		if name == "" {
+			return nil, fmt.Errorf("路径模板中有空的占位符: %q", template)
+		}
+		params = append(params, name)
+		rest = rest[open+end+2:]
+	}
+}
+
+// expandPathTemplate 用参数替换路径模板中的 {参数名}（? 之前按路径段转义，之后按查询参数转义）
+func expandPathTemplate(template string, params map[string]string) (string, error) {
+	names, err := templateParams(template)
+	if err != nil {
+		return "", err
+	}
+	queryStart := strings.IndexByte(template, '?')
+	var sb strings.Builder
+	rest := template
+	offset := 0
+	for _, name := range names {
+		placeholder := "{" + name + "}"
+		i := strings.Index(rest, placeholder)
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("缺少参数 %s", name)
+		}
+		sb.WriteString(rest[:i])
+		if queryStart >= 0 && offset+i > queryStart {
+			sb.WriteString(url.QueryEscape(value))
+		} else {
+			sb.WriteString(url.PathEscape(value))
+		}
+		rest = rest[i+len(placeholder):]
+		offset += i + len(placeholder)
+	}
+	sb.WriteString(rest)
+	return sb.String(), nil
+}
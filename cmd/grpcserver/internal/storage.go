@@ -0,0 +1,81 @@
+package grpcserver
+
+import (
+	"crawler-platform/GoogleEarth"
+	"crawler-platform/Store"
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+)
+
+// SetTileStorage 设置服务器端瓦片存储（可选，为 nil 时不读写存储）
+func (s *Server) SetTileStorage(tileStorage *Store.TileStorage) {
+	s.tileStorage = tileStorage
+}
+
+// storageKeyForTask 返回任务在瓦片存储中的数据类型和用于校验的版本号
+// 返回 ok=false 表示该任务类型不写入存储（RockTree 的八叉树路径、历史影像的日期维度无法映射到存储键）
+// 附加了请求头的任务（条件请求、Range、Cookie 等）的响应取决于请求头，也不读写存储
+func storageKeyForTask(req *tasksmanager.TaskRequest, epoch int32, imageryEpoch *int32) (dataType string, storageEpoch int32, ok bool) {
+	if len(req.GetRequestHeaders()) > 0 {
+		return "", 0, false
+	}
+	switch req.TaskType {
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_Q2:
+		return "q2", epoch, true
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_QP:
+		dbName := req.GetDbName()
+		if dbName == "" || dbName == GoogleEarth.TM {
+			return "qp", epoch, true
+		}
+		return "qp_" + dbName, epoch, true
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY:
+		if imageryEpoch == nil {
+			return "", 0, false
+		}
+		return "imagery", *imageryEpoch, true
+	case tasksmanager.TaskType_TASK_TYPE_GOOGLE_EARTH_TERRAIN:
+		return "terrain", epoch, true
+	default:
+		return "", 0, false
+	}
+}
+
+// getTaskFromStorage 从瓦片存储读取任务数据
+// 只有存储中记录的 epoch 与请求一致时才视为命中（无元数据的数据无法确认版本，视为未命中）
+func (s *Server) getTaskFromStorage(req *tasksmanager.TaskRequest, tileKey string, epoch int32, imageryEpoch *int32) ([]byte, bool) {
+	if s.tileStorage == nil {
+		return nil, false
+	}
+	dataType, storageEpoch, ok := storageKeyForTask(req, epoch, imageryEpoch)
+	if !ok {
+		return nil, false
+	}
+
+	data, metadata, err := s.tileStorage.GetWithMetadata(dataType, tileKey)
+	if err != nil || metadata == nil || len(data) == 0 {
+		return nil, false
+	}
+	if metadata.Epoch != int(storageEpoch) {
+		return nil, false
+	}
+	return data, true
+}
+
+// putTaskToStorage 将上游成功返回的任务数据写入瓦片存储（写入失败只记录日志，不影响任务结果）
+func (s *Server) putTaskToStorage(req *tasksmanager.TaskRequest, tileKey string, epoch int32, imageryEpoch *int32, body []byte) {
+	if s.tileStorage == nil || len(body) == 0 {
+		return
+	}
+	dataType, storageEpoch, ok := storageKeyForTask(req, epoch, imageryEpoch)
+	if !ok {
+		return
+	}
+
+	var providerID *int
+	if req.ProviderId != nil {
+		id := int(*req.ProviderId)
+		providerID = &id
+	}
+	if err := s.tileStorage.PutWithMetadata(dataType, tileKey, body, int(storageEpoch), providerID); err != nil {
+		s.logger.Warn("写入瓦片存储失败: %s/%s, 错误: %v", dataType, tileKey, err)
+	}
+}
@@ -0,0 +1,180 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+	"crawler-platform/utlsclient"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 任务执行错误定义（连接池与传输层错误见 utlsclient 包）
+var (
+	// ErrUpstreamForbidden 表示上游返回 403 Forbidden（出口 IP 被拒绝）
+	ErrUpstreamForbidden = errors.New("upstream returned 403 Forbidden")
+
+	// ErrUpstreamServerError 表示上游返回 5xx（重试后仍失败）
+	ErrUpstreamServerError = errors.New("upstream returned server error")
+
+	// ErrDecodeFailed 表示响应体解码失败（数据损坏或解密密钥不匹配）
+	ErrDecodeFailed = errors.New("decode response body failed")
+
+	// ErrQueueFull 表示上游并发已满且等待队列已满
+	ErrQueueFull = errors.New("task queue is full")
+
+	// ErrQueueTimeout 表示任务在等待队列中超过最长等待时间
+	ErrQueueTimeout = errors.New("task queue wait timeout")
+
+	// ErrServerDraining 表示服务器正在停止，不再接受新任务
+	ErrServerDraining = errors.New("server is draining")
+
+	// ErrBodyTooLarge 表示上游响应体超过服务器或任务设置的大小限制
+	ErrBodyTooLarge = errors.New("response body too large")
+)
+
+// upstreamStatusError 上游返回的错误状态码，保留状态码和响应体
+type upstreamStatusError struct {
+	statusCode int32
+	body       []byte
+}
+
+func (e *upstreamStatusError) Error() string {
+	return fmt.Sprintf("上游返回状态码 %d", e.statusCode)
+}
+
+func (e *upstreamStatusError) Unwrap() error {
+	if e.statusCode == http.StatusForbidden {
+		return ErrUpstreamForbidden
+	}
+	return ErrUpstreamServerError
+}
+
+// isUpstreamErrorStatus 判断上游状态码是否视为任务执行失败（403 与 5xx）
+func isUpstreamErrorStatus(statusCode int) bool {
+	return statusCode == http.StatusForbidden || (statusCode >= 500 && statusCode < 600)
+}
+
+// taskErrorCode 将任务执行错误归类为机器可读的错误码
+func taskErrorCode(err error) tasksmanager.TaskErrorCode {
+	if err == nil {
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_NONE
+	}
+	// 转发到其他节点后由对端返回的失败响应，沿用对端的错误码
+	if peerResponse := taskResponseFromError(err); peerResponse.GetErrorCode() != tasksmanager.TaskErrorCode_TASK_ERROR_CODE_NONE {
+		return peerResponse.GetErrorCode()
+	}
+	switch {
+	case errors.Is(err, utlsclient.ErrPoolWarming):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_POOL_WARMING
+	case errors.Is(err, utlsclient.ErrAllConnectionsUnhealthy):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_ALL_CONNECTIONS_UNHEALTHY
+	case errors.Is(err, utlsclient.ErrNoAvailableConnection):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_NO_AVAILABLE_CONNECTION
+	case errors.Is(err, utlsclient.ErrConnectionInUse):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_ALL_CONNECTIONS_BUSY
+	case errors.Is(err, utlsclient.ErrRateLimited):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_RATE_LIMITED
+	case errors.Is(err, ErrUpstreamForbidden):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_UPSTREAM_FORBIDDEN
+	case errors.Is(err, ErrUpstreamServerError):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_UPSTREAM_SERVER_ERROR
+	case errors.Is(err, ErrDecodeFailed):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_DECODE_FAILED
+	case errors.Is(err, ErrQueueFull):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_QUEUE_FULL
+	case errors.Is(err, ErrQueueTimeout):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_QUEUE_TIMEOUT
+	case errors.Is(err, ErrServerDraining):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_SERVER_DRAINING
+	case errors.Is(err, ErrBodyTooLarge):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_BODY_TOO_LARGE
+	case errors.Is(err, context.Canceled):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_CANCELED
+	case errors.Is(err, utlsclient.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_TIMEOUT
+	// 请求过程中连接被标记为不健康（如同一连接上的其他请求触发 403），与连接被关闭同样处理
+	case errors.Is(err, utlsclient.ErrTransportReset), errors.Is(err, utlsclient.ErrConnectionUnhealthy):
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_TRANSPORT_RESET
+	default:
+		return tasksmanager.TaskErrorCode_TASK_ERROR_CODE_INTERNAL
+	}
+}
+
+// taskFailureBody 返回失败响应的状态码和响应体
+// 上游错误保留上游的状态码和响应体；连接问题返回 503（客户端可以重试），其他错误返回 500
+func taskFailureBody(err error) (int32, []byte) {
+	var upstreamErr *upstreamStatusError
+	if errors.As(err, &upstreamErr) {
+		return upstreamErr.statusCode, upstreamErr.body
+	}
+	// 转发到其他节点后由对端返回的失败响应
+	if peerResponse := taskResponseFromError(err); peerResponse != nil {
+		return peerResponse.GetTaskResponseStatusCode(), peerResponse.GetTaskResponseBody()
+	}
+	if taskErrorGRPCCode(taskErrorCode(err)) != codes.Internal {
+		return http.StatusServiceUnavailable, []byte(fmt.Sprintf("服务暂时不可用，请稍后重试: %v", err))
+	}
+	return http.StatusInternalServerError, []byte(fmt.Sprintf("任务执行失败: %v", err))
+}
+
+// taskErrorGRPCCode 返回错误码对应的 gRPC 状态码
+func taskErrorGRPCCode(code tasksmanager.TaskErrorCode) codes.Code {
+	switch code {
+	case tasksmanager.TaskErrorCode_TASK_ERROR_CODE_NONE:
+		return codes.OK
+	case tasksmanager.TaskErrorCode_TASK_ERROR_CODE_POOL_WARMING,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_ALL_CONNECTIONS_UNHEALTHY,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_UPSTREAM_SERVER_ERROR,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_TRANSPORT_RESET,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_SERVER_DRAINING:
+		return codes.Unavailable
+	case tasksmanager.TaskErrorCode_TASK_ERROR_CODE_ALL_CONNECTIONS_BUSY,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_RATE_LIMITED,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_QUEUE_FULL,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_BODY_TOO_LARGE:
+		return codes.ResourceExhausted
+	case tasksmanager.TaskErrorCode_TASK_ERROR_CODE_NO_AVAILABLE_CONNECTION:
+		return codes.FailedPrecondition
+	case tasksmanager.TaskErrorCode_TASK_ERROR_CODE_UPSTREAM_FORBIDDEN:
+		return codes.PermissionDenied
+	case tasksmanager.TaskErrorCode_TASK_ERROR_CODE_TIMEOUT,
+		tasksmanager.TaskErrorCode_TASK_ERROR_CODE_QUEUE_TIMEOUT:
+		return codes.DeadlineExceeded
+	case tasksmanager.TaskErrorCode_TASK_ERROR_CODE_DECODE_FAILED:
+		return codes.DataLoss
+	case tasksmanager.TaskErrorCode_TASK_ERROR_CODE_CANCELED:
+		return codes.Canceled
+	default:
+		return codes.Internal
+	}
+}
+
+// taskErrorStatus 根据任务执行错误生成 gRPC 状态错误
+// response 的 error_code 会被设置，并作为状态详情返回给客户端
+func taskErrorStatus(response *tasksmanager.TaskResponse, err error) error {
+	response.ErrorCode = taskErrorCode(err)
+	st := status.New(taskErrorGRPCCode(response.ErrorCode), fmt.Sprintf("任务执行失败: %v", err))
+	if detailed, detailErr := st.WithDetails(response); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// taskResponseFromError 从 taskErrorStatus 生成的状态错误中取出任务响应（不存在时返回 nil）
+func taskResponseFromError(err error) *tasksmanager.TaskResponse {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	for _, detail := range st.Details() {
+		if response, ok := detail.(*tasksmanager.TaskResponse); ok {
+			return response
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultStreamMaxInFlight SubmitTaskStream 单个流默认的流控额度
+const defaultStreamMaxInFlight = 64
+
+// SetStreamMaxInFlight 设置 SubmitTaskStream 单个流允许同时执行的任务数（<=0 时使用默认值）
+func (s *Server) SetStreamMaxInFlight(maxInFlight int) {
+	if maxInFlight <= 0 {
+		maxInFlight = defaultStreamMaxInFlight
+	}
+	s.streamMaxInFlight = maxInFlight
+}
+
+// SubmitTaskStream 流式提交任务请求（双向流）
+// 流建立后先下发初始额度，每个任务在独立 goroutine 中执行，完成后连同 1 个额度一起推送响应
+// 客户端超出额度发送任务时以 RESOURCE_EXHAUSTED 结束流
+func (s *Server) SubmitTaskStream(stream grpc.BidiStreamingServer[tasksmanager.TaskStreamRequest, tasksmanager.TaskStreamResponse]) error {
+	// 任务使用可取消的 ctx：流因错误结束时先取消未完成的任务，再等待其退出
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	maxInFlight := s.streamMaxInFlight
+
+	// grpc 流的 Send 不是并发安全的，多个任务 goroutine 需要串行发送
+	var sendMu sync.Mutex
+	send := func(resp *tasksmanager.TaskStreamResponse) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(resp)
+	}
+
+	if err := send(&tasksmanager.TaskStreamResponse{Credits: int32(maxInFlight)}); err != nil {
+		return err
+	}
+
+	var (
+		inFlightMu sync.Mutex
+		inFlight   int
+		wg         sync.WaitGroup
+	)
+	// 返回前等待所有已接收的任务完成，避免流结束后继续 Send
+	defer wg.Wait()
+
+	for {
+		streamReq, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		inFlightMu.Lock()
+		if inFlight >= maxInFlight {
+			inFlightMu.Unlock()
+			cancel()
+			return status.Errorf(codes.ResourceExhausted, "超出流控额度: 最多允许 %d 个未完成任务", maxInFlight)
+		}
+		inFlight++
+		inFlightMu.Unlock()
+
+		wg.Add(1)
+		go func(streamReq *tasksmanager.TaskStreamRequest) {
+			defer wg.Done()
+
+			resp := &tasksmanager.TaskStreamResponse{CorrelationId: streamReq.GetCorrelationId()}
+			if streamReq.GetTask() == nil {
+				resp.Error = "缺少任务请求"
+			} else if taskResp, err := s.SubmitTask(ctx, streamReq.GetTask()); err != nil {
+				// 执行失败时一并返回带 error_code 的任务响应
+				resp.Error = err.Error()
+				resp.Task = taskResponseFromError(err)
+			} else {
+				resp.Task = taskResp
+			}
+
+			// 先释放额度再通知客户端，保证客户端收到额度后发送的任务不会被误判为超额
+			inFlightMu.Lock()
+			inFlight--
+			inFlightMu.Unlock()
+			resp.Credits = 1
+
+			if err := send(resp); err != nil {
+				s.logger.Debug("流式任务响应发送失败: %s, 错误: %v", streamReq.GetCorrelationId(), err)
+			}
+		}(streamReq)
+	}
+}
@@ -0,0 +1,65 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+)
+
+// defaultTaskTimeout 未配置任务类型的超时时间时使用的默认值
+const defaultTaskTimeout = 30 * time.Second
+
+// SetTaskTimeouts 设置各任务类型的默认超时时间（客户端未设置截止时间时使用，未设置或 <=0 的任务类型使用 30 秒）
+func (s *Server) SetTaskTimeouts(timeouts map[tasksmanager.TaskType]time.Duration) {
+	byType := make(map[tasksmanager.TaskType]time.Duration, len(timeouts))
+	for taskType, timeout := range timeouts {
+		if timeout > 0 {
+			byType[taskType] = timeout
+		}
+	}
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.taskTimeouts = byType
+}
+
+// taskTimeout 返回任务类型的默认超时时间
+func (s *Server) taskTimeout(taskType tasksmanager.TaskType) time.Duration {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	if timeout, ok := s.taskTimeouts[taskType]; ok {
+		return timeout
+	}
+	return defaultTaskTimeout
+}
+
+// withTaskTimeout 客户端未设置截止时间时为任务加上该任务类型的默认超时（已有更早的截止时间时保持不变）
+func (s *Server) withTaskTimeout(ctx context.Context, taskType tasksmanager.TaskType) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.taskTimeout(taskType))
+}
+
+// sleepContext 等待 d，上下文取消或超时时提前返回上下文错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flightTimeout 合并上游请求的超时：取调用方剩余截止时间与该任务类型默认超时中较晚的一个
+func (s *Server) flightTimeout(ctx context.Context, taskType tasksmanager.TaskType) time.Duration {
+	timeout := s.taskTimeout(taskType)
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining > timeout {
+			return remaining
+		}
+	}
+	return timeout
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crawler-platform/Store"
 	server "crawler-platform/cmd/grpcserver/internal"
 	"crawler-platform/localippool"
 	"crawler-platform/logger"
@@ -12,21 +13,30 @@ import (
 	"net"
 	"os"
 	"os/signal"
-	"path/filepath"
+	"slices"
 	"syscall"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// defaultConfigPath 获取配置文件路径（可通过环境变量 GRPCSERVER_CONFIG 指定，默认为 config.toml）
+func defaultConfigPath() string {
+	if configPath := os.Getenv("GRPCSERVER_CONFIG"); configPath != "" {
+		return configPath
+	}
+	// 默认使用当前目录下的 config.toml
+	return "./cmd/grpcserver/config.toml"
+}
+
 func main() {
-	// 获取配置文件路径（可通过环境变量 GRPCSERVER_CONFIG 指定，默认为 config.toml）
-	configPath := os.Getenv("GRPCSERVER_CONFIG")
-	if configPath == "" {
-		// 默认使用当前目录下的 config.toml
-		configPath = "./cmd/grpcserver/config.toml"
+	// grpcserver validate [配置文件路径]：只校验配置并打印有效配置，不启动服务器
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:]))
 	}
 
+	configPath := defaultConfigPath()
+
 	// 检查配置文件是否存在
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
 		log.Printf("警告: 配置文件 %s 不存在，将使用默认配置", configPath)
@@ -46,12 +56,13 @@ func main() {
 	}
 
 	// 初始化日志记录器（根据配置文件）
-	logger.InitGlobalLogger(logger.NewConsoleLogger(
+	consoleLogger := logger.NewConsoleLogger(
 		config.Logger.EnableDebug,
 		config.Logger.EnableInfo,
 		config.Logger.EnableWarn,
 		config.Logger.EnableError,
-	))
+	)
+	logger.InitGlobalLogger(consoleLogger)
 
 	// 加载 TLS 证书（根据配置）
 	var tlsConfig *tls.Config
@@ -81,6 +92,14 @@ func main() {
 		log.Printf("引导节点: %v", config.Server.Bootstrap)
 		srv.SetBootstrapNodes(config.Server.Bootstrap)
 	}
+	// 设置流式任务的流控额度
+	srv.SetStreamMaxInFlight(config.Server.StreamMaxInFlight)
+	if err := srv.SetAllowedRequestHeaders(config.Server.AllowedRequestHeaders); err != nil {
+		log.Fatalf("加载请求头允许列表失败: %v", err)
+	}
+	srv.SetMaxBodyBytes(config.Server.MaxBodyBytes)
+	srv.SetHotCacheMaxBytes(config.Server.HotCacheMaxBytes)
+	srv.SetForwardMaxHops(config.Server.ForwardMaxHops)
 
 	// 初始化并启动本地 IP 池
 	// 如果配置启用，或配置了IPv6子网（即使enable=false），则创建本地IP池
@@ -109,6 +128,7 @@ func main() {
 			if config.LocalIPPool.TargetIPCount > 0 {
 				log.Printf("设置本地IP池目标数量: %d", config.LocalIPPool.TargetIPCount)
 				localIPPool.SetTargetIPCount(config.LocalIPPool.TargetIPCount)
+				srv.SetIPPoolTarget(config.LocalIPPool.TargetIPCount)
 
 				// 等待IPv6地址池准备就绪（在goroutine中异步执行，不阻塞主线程）
 				// SetTargetIPCount 会立即批量创建地址，但创建需要时间，需要等待地址创建完成
@@ -143,6 +163,46 @@ func main() {
 		config.GoogleEarthDesktopData.ImageryPath,
 		config.GoogleEarthDesktopData.TerrainPath,
 	)
+	srv.SetGoogleEarthMultiDBDataConfig(
+		config.GoogleEarthDesktopData.TMHostName,
+		config.GoogleEarthDesktopData.QPPath,
+		config.GoogleEarthDesktopData.ImageryHistoryPath,
+	)
+	if err := srv.SetSites(config.ToSites()); err != nil {
+		log.Fatalf("加载站点配置失败: %v", err)
+	}
+	if len(config.Sites) > 0 {
+		log.Printf("已配置 %d 个通用 HTTP 站点", len(config.Sites))
+	}
+	srv.SetJobConfig(config.Jobs.StateDir, config.Jobs.Concurrency)
+	srv.SetSchedulerConfig(config.Scheduler.MaxRunning, config.Scheduler.MaxQueued, config.Scheduler.MaxWaitDuration())
+	srv.SetTaskTimeouts(config.TaskTimeouts.ToTaskTimeouts())
+	// 节点令牌只通过 TLS 连接发送，未启用 TLS 时拒绝启动，避免令牌以明文发送
+	if config.Auth.NodeToken != "" && !config.TLS.Enable {
+		log.Fatalf("配置了 [Auth] node_token 但未启用 [tls]，节点令牌会以明文发送")
+	}
+	if config.Auth.Enable {
+		authTokens, authCerts, err := config.Auth.ToIdentities()
+		if err != nil {
+			log.Fatalf("加载认证配置失败: %v", err)
+		}
+		srv.SetAuthConfig(true, authTokens, authCerts, config.Auth.NodeToken)
+		log.Printf("已启用客户端认证: %d 个 API 令牌, %d 个证书身份", len(authTokens), len(authCerts))
+	} else {
+		srv.SetAuthConfig(false, nil, nil, config.Auth.NodeToken)
+	}
+	// 初始化服务器端瓦片存储（如果启用）
+	var tileStorage *Store.TileStorage
+	if config.Storage.Enable {
+		tileStorage, err = Store.NewTileStorage(config.Storage.ToTileStorageConfig())
+		if err != nil {
+			log.Printf("错误: 创建瓦片存储失败: %v，将不使用服务器端存储", err)
+			tileStorage = nil
+		} else {
+			log.Printf("已启用服务器端瓦片存储（后端: %s, 目录: %s）", config.Storage.Backend, config.Storage.DBDir)
+			srv.SetTileStorage(tileStorage)
+		}
+	}
 	// 初始化并启动域名 IP 监控器（如果启用）
 	var domainMonitor remotedomainippool.DomainMonitor
 	var utlsClient *utlsclient.Client
@@ -172,6 +232,7 @@ func main() {
 			log.Printf("错误: 创建域名 IP 监控器失败: %v", err)
 		} else {
 			domainMonitor.Start()
+			srv.SetDomainMonitor(domainMonitor, config.DNSDomain.HostName)
 			log.Printf("域名 IP 监控器已启动，监控 %d 个域名，更新间隔: %d 分钟",
 				len(config.DNSDomain.HostName), config.DomainMonitor.UpdateIntervalMinutes)
 
@@ -197,6 +258,7 @@ func main() {
 					// 如果配置的目标 IP 数量为 0，且域名监控器已获取到 IP 数据，则设置为域名 IP 池的总数
 					if config.LocalIPPool.TargetIPCount == 0 && totalIPCount > 0 {
 						localIPPool.SetTargetIPCount(totalIPCount)
+						srv.SetIPPoolTarget(totalIPCount)
 						log.Printf("已根据域名 IP 池自动设置本地 IP 池目标数量: %d", totalIPCount)
 					}
 				}()
@@ -212,6 +274,15 @@ func main() {
 			if config.GoogleEarthDesktopData.Enable && config.GoogleEarthDesktopData.HostName != "" {
 				prewarmDomains = append(prewarmDomains, config.GoogleEarthDesktopData.HostName)
 			}
+			if config.GoogleEarthDesktopData.Enable && config.GoogleEarthDesktopData.TMHostName != "" &&
+				config.GoogleEarthDesktopData.TMHostName != config.GoogleEarthDesktopData.HostName {
+				prewarmDomains = append(prewarmDomains, config.GoogleEarthDesktopData.TMHostName)
+			}
+			for _, site := range config.Sites {
+				if site.HostName != "" && !slices.Contains(prewarmDomains, site.HostName) {
+					prewarmDomains = append(prewarmDomains, site.HostName)
+				}
+			}
 
 			if len(prewarmDomains) > 0 {
 				go func() {
@@ -267,10 +338,16 @@ func main() {
 					}
 					if config.RockTreeData.Enable {
 						log.Println("已启用 RockTreeData，将使用 UTLS 池")
-						poolConfig.HealthCheckPath = config.RockTreeData.HealthCheckPath
-						poolConfig.SessionIdPath = config.RockTreeData.SessionIdPath
+						// RockTree 请求不携带 SessionId，两者同时启用时保留 GoogleEarthDesktopData 的会话配置
+						if !config.GoogleEarthDesktopData.Enable {
+							poolConfig.HealthCheckPath = config.RockTreeData.HealthCheckPath
+							poolConfig.SessionIdPath = config.RockTreeData.SessionIdPath
+						}
 					}
 
+					// 通用站点按 validator_path 验证该主机的连接
+					poolConfig.HostHealthCheckPaths = config.SiteHealthCheckPaths()
+
 					client, cerr := utlsclient.NewClient(poolConfig, remotePool)
 					if cerr != nil {
 						log.Printf("错误: 创建 UTLS 客户端失败: %v", cerr)
@@ -358,6 +435,15 @@ func main() {
 		log.Fatal("TUIC 服务器需要任务执行器，但 UTLS 客户端未初始化（请启用 DomainMonitor 或等待客户端初始化）")
 	}
 
+	// 加载持久化的作业（UTLS 客户端就绪后再继续执行未完成的作业）
+	if err := srv.LoadJobs(); err != nil {
+		log.Printf("警告: 加载作业失败: %v", err)
+	}
+
+	// 配置重新加载（SIGHUP 信号、ReloadConfig RPC 与管理接口）
+	reloader := newConfigReloader(configPath, config, srv, consoleLogger, domainMonitor)
+	srv.SetConfigReloader(reloader.Reload)
+
 	// 启动 gRPC 服务器（如果启用）
 	if enableGRPC {
 		go func() {
@@ -368,37 +454,27 @@ func main() {
 		log.Printf("gRPC 服务器已启动在 %s:%s", config.Server.Address, config.Server.Port)
 	}
 
+	// 启动指标导出与管理接口 HTTP 服务器（如果启用，监听地址相同时共用一个服务器）
+	srv.SetAdminWebDir(config.Admin.WebDir)
+	sharedHTTP := config.Metrics.Enable && config.Admin.Enable && config.Metrics.Address == config.Admin.Address
+	if config.Metrics.Enable {
+		if err := srv.StartHTTPServer(config.Metrics.Address, true, sharedHTTP); err != nil {
+			log.Printf("警告: 启动指标导出 HTTP 服务器失败: %v", err)
+		}
+	}
+	if config.Admin.Enable && !sharedHTTP {
+		if err := srv.StartHTTPServer(config.Admin.Address, false, true); err != nil {
+			log.Printf("警告: 启动管理接口 HTTP 服务器失败: %v", err)
+		}
+	}
+
 	// 启动 TUIC 服务器（如果启用）
 	if enableTUIC {
 		if taskExecutor == nil {
 			log.Fatal("TUIC 服务器需要任务执行器，但 UTLS 客户端未初始化")
 		}
 		// 获取 TLS 证书路径（用于 sing-box TUIC 服务器）
-		tlsCertPath := config.TUIC.TLSCertPath
-		tlsKeyPath := config.TUIC.TLSKeyPath
-
-		// 如果配置中没有指定，尝试从 certs 目录查找
-		if tlsCertPath == "" || tlsKeyPath == "" {
-			certsDir := config.GetCertsDir()
-			if certsDir != "" {
-				// 尝试查找 cert.pem 或 server.crt
-				if _, err := os.Stat(filepath.Join(certsDir, "cert.pem")); err == nil {
-					if tlsCertPath == "" {
-						tlsCertPath = filepath.Join(certsDir, "cert.pem")
-					}
-					if tlsKeyPath == "" {
-						tlsKeyPath = filepath.Join(certsDir, "key.pem")
-					}
-				} else if _, err := os.Stat(filepath.Join(certsDir, "server.crt")); err == nil {
-					if tlsCertPath == "" {
-						tlsCertPath = filepath.Join(certsDir, "server.crt")
-					}
-					if tlsKeyPath == "" {
-						tlsKeyPath = filepath.Join(certsDir, "server.key")
-					}
-				}
-			}
-		}
+		tlsCertPath, tlsKeyPath := config.TUICCertPaths()
 
 		// 自动生成 UUID 和密码（如果未配置）
 		tuicUUID := config.TUIC.UUID
@@ -505,17 +581,36 @@ func main() {
 		}
 	}
 
+	// 收到 SIGHUP 时重新加载配置文件
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			resp, err := reloader.Reload()
+			if err != nil {
+				log.Printf("重新加载配置文件失败: %v", err)
+				continue
+			}
+			log.Printf("已重新加载配置文件，已生效: %v", resp.GetApplied())
+			if len(resp.GetRestartRequired()) > 0 {
+				log.Printf("警告: 以下配置项需要重启服务器才能生效: %v", resp.GetRestartRequired())
+			}
+		}
+	}()
+
 	// 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	log.Println("服务器已启动，按 Ctrl+C 退出...")
 	<-quit
+	signal.Stop(hup)
 
 	log.Println("\n收到退出信号，正在关闭服务器...")
 
-	// 创建关闭超时上下文（最多等待 30 秒）
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// 关闭超时包括排空时间与停止各组件的时间
+	drainTimeout := config.Server.DrainTimeoutDuration()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout+30*time.Second)
 	defer cancel()
 
 	// 在 goroutine 中执行关闭操作
@@ -523,6 +618,24 @@ func main() {
 	go func() {
 		defer close(shutdownDone)
 
+		// 排空：通知其他节点与客户端本节点离开，拒绝新任务，等待正在执行的任务与持久化队列完成
+		if enableGRPC {
+			log.Printf("正在排空服务器（最多等待 %v）...", drainTimeout)
+			drainCtx, cancelDrain := context.WithTimeout(context.Background(), drainTimeout)
+			if err := srv.Drain(drainCtx); err != nil {
+				log.Printf("排空服务器未完成: %v", err)
+			}
+			cancelDrain()
+		}
+
+		// 停止指标导出 HTTP 服务器
+		srv.StopHTTPServer()
+
+		// 关闭服务器（会自动停止 UTLS 热连接池并关闭 IP 池）
+		if enableGRPC {
+			srv.Stop()
+		}
+
 		// 停止域名 IP 监控器
 		if domainMonitor != nil {
 			log.Println("正在停止域名 IP 监控器...")
@@ -537,7 +650,7 @@ func main() {
 			log.Println("UTLS 客户端已停止")
 		}
 
-		// 关闭本地 IP 池
+		// 关闭本地 IP 池（清理创建的 IPv6 地址）
 		if localIPPool != nil {
 			log.Println("正在关闭本地 IP 池...")
 			if err := localIPPool.Close(); err != nil {
@@ -554,9 +667,14 @@ func main() {
 			log.Println("TUIC 服务器已停止")
 		}
 
-		// 关闭服务器（会自动关闭 IP 池）
-		if enableGRPC {
-			srv.Stop()
+		// 关闭瓦片存储（等待异步持久化队列写完）
+		if tileStorage != nil {
+			log.Println("正在关闭瓦片存储...")
+			if err := tileStorage.Close(); err != nil {
+				log.Printf("关闭瓦片存储时出错: %v", err)
+			} else {
+				log.Println("瓦片存储已关闭")
+			}
 		}
 	}()
 
@@ -678,6 +796,14 @@ func (w *ipPoolWrapper) SetTargetIPCount(count int) {
 	w.pool.SetTargetIPCount(count)
 }
 
+func (w *ipPoolWrapper) GetActiveIPv6Addresses() []string {
+	return w.pool.GetActiveIPv6Addresses()
+}
+
+func (w *ipPoolWrapper) GetIPv4Addresses() []string {
+	return w.pool.GetIPv4Addresses()
+}
+
 func (w *ipPoolWrapper) Close() error {
 	return w.pool.Close()
 }
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	server "crawler-platform/cmd/grpcserver/internal"
+	"crawler-platform/cmd/grpcserver/tasksmanager"
+	"crawler-platform/logger"
+	"crawler-platform/remotedomainippool"
+)
+
+// liveConfigKeys 可在运行时生效的配置项（"表名.键名"），其余配置项修改后需要重启
+var liveConfigKeys = map[string]bool{
+	"logger.enable_debug": true,
+	"logger.enable_info":  true,
+	"logger.enable_warn":  true,
+	"logger.enable_error": true,
+
+	"server.allowed_request_headers": true,
+	"server.max_body_bytes":          true,
+
+	"UtlsClient.max_conns_per_host":       true,
+	"UtlsClient.pre_warm_interval":        true,
+	"UtlsClient.max_concurrent_pre_warms": true,
+	"UtlsClient.conn_timeout":             true,
+	"UtlsClient.idle_timeout":             true,
+	"UtlsClient.health_check_interval":    true,
+	"UtlsClient.ip_blacklist_timeout":     true,
+	"UtlsClient.per_host_rate":            true,
+	"UtlsClient.per_host_burst":           true,
+	"UtlsClient.per_remote_ip_rate":       true,
+	"UtlsClient.per_remote_ip_burst":      true,
+	"UtlsClient.per_local_ip_rate":        true,
+	"UtlsClient.per_local_ip_burst":       true,
+
+	"TaskTimeouts.default":                true,
+	"TaskTimeouts.rocktree_bulk_metadata": true,
+	"TaskTimeouts.rocktree_node_data":     true,
+	"TaskTimeouts.q2":                     true,
+	"TaskTimeouts.imagery":                true,
+	"TaskTimeouts.imagery_history":        true,
+	"TaskTimeouts.qp":                     true,
+	"TaskTimeouts.terrain":                true,
+	"TaskTimeouts.http_site":              true,
+
+	"RockTreeDataConfig.BulkMetadataPath": true,
+	"RockTreeDataConfig.NodeDataPath":     true,
+	"RockTreeDataConfig.ImageryDataPath":  true,
+
+	"GoogleEarthDesktopDataConfig.q2Path":             true,
+	"GoogleEarthDesktopDataConfig.imageryPath":        true,
+	"GoogleEarthDesktopDataConfig.terrainPath":        true,
+	"GoogleEarthDesktopDataConfig.qpPath":             true,
+	"GoogleEarthDesktopDataConfig.imageryHistoryPath": true,
+
+	"DNSDomain.HostName": true,
+
+	"Sites": true,
+}
+
+// configReloader 重新加载配置文件（SIGHUP 与 ReloadConfig RPC），应用可在运行时生效的修改
+type configReloader struct {
+	mu            sync.Mutex
+	path          string
+	current       *Config // 当前生效的配置（需要重启的修改不会写入）
+	srv           *server.Server
+	consoleLogger *logger.ConsoleLogger
+	domainMonitor remotedomainippool.DomainMonitor // 可能为 nil
+}
+
+// newConfigReloader 创建配置重新加载器（保存一份配置副本，不修改 config）
+func newConfigReloader(path string, config *Config, srv *server.Server, consoleLogger *logger.ConsoleLogger, domainMonitor remotedomainippool.DomainMonitor) *configReloader {
+	current := *config
+	return &configReloader{
+		path:          path,
+		current:       &current,
+		srv:           srv,
+		consoleLogger: consoleLogger,
+		domainMonitor: domainMonitor,
+	}
+}
+
+// Reload 重新读取配置文件，应用可在运行时生效的修改，返回已生效与需要重启的配置项
+// 新配置校验失败时不应用任何修改
+func (r *configReloader) Reload() (*tasksmanager.ReloadConfigResponse, error) {
+	if r.path == "" {
+		return nil, fmt.Errorf("服务器启动时未使用配置文件")
+	}
+	next, err := LoadConfig(r.path)
+	if err != nil {
+		return nil, err
+	}
+	if err := next.Validate(); err != nil {
+		return nil, fmt.Errorf("新配置校验失败，未应用任何修改: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resp := &tasksmanager.ReloadConfigResponse{}
+	// 站点的 validator_path 只在创建热连接池时读取，修改需要重启；站点的其他修改仍可在运行时生效
+	if !maps.Equal(r.current.SiteHealthCheckPaths(), next.SiteHealthCheckPaths()) {
+		resp.RestartRequired = append(resp.RestartRequired, "Sites.validator_path")
+		keepSiteValidatorPaths(next, r.current)
+	}
+	changedSections := make(map[string]bool)
+	cur := reflect.ValueOf(r.current).Elem()
+	nxt := reflect.ValueOf(next).Elem()
+	for i := 0; i < cur.NumField(); i++ {
+		section := tomlKey(cur.Type().Field(i))
+		curSection, nextSection := cur.Field(i), nxt.Field(i)
+		// 数组表（如 [[Sites]]）整体比较，键名为表名
+		if curSection.Kind() != reflect.Struct {
+			if reflect.DeepEqual(curSection.Interface(), nextSection.Interface()) {
+				continue
+			}
+			if !r.canApply(section, next) {
+				resp.RestartRequired = append(resp.RestartRequired, section)
+				continue
+			}
+			curSection.Set(nextSection)
+			resp.Applied = append(resp.Applied, section)
+			changedSections[section] = true
+			continue
+		}
+		for j := 0; j < curSection.NumField(); j++ {
+			if reflect.DeepEqual(curSection.Field(j).Interface(), nextSection.Field(j).Interface()) {
+				continue
+			}
+			key := section + "." + tomlKey(curSection.Type().Field(j))
+			if !r.canApply(key, next) {
+				resp.RestartRequired = append(resp.RestartRequired, key)
+				continue
+			}
+			curSection.Field(j).Set(nextSection.Field(j))
+			resp.Applied = append(resp.Applied, key)
+			changedSections[section] = true
+		}
+	}
+	r.apply(changedSections)
+	return resp, nil
+}
+
+// keepSiteValidatorPaths 将 next 中各站点的 validator_path 恢复为 current 中该主机正在使用的验证路径
+func keepSiteValidatorPaths(next, current *Config) {
+	paths := current.SiteHealthCheckPaths()
+	for i := range next.Sites {
+		next.Sites[i].ValidatorPath = paths[next.Sites[i].HostName]
+	}
+}
+
+// canApply 判断配置项的修改能否在运行时生效
+func (r *configReloader) canApply(key string, next *Config) bool {
+	if !liveConfigKeys[key] {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(key, "UtlsClient."):
+		// 热连接池尚未创建时，创建时使用的仍是启动时的配置
+		return r.srv.GetUTLSClient() != nil
+	case key == "DNSDomain.HostName":
+		return r.domainMonitor != nil && len(next.DNSDomain.HostName) > 0
+	case key == "Sites":
+		// 站点主机名决定热连接池预热的域名，只有主机名集合不变时才能在运行时修改站点
+		var errs configErrors
+		next.validateSites(&errs)
+		return len(errs) == 0 && slices.Equal(siteHostNames(r.current), siteHostNames(next))
+	}
+	return true
+}
+
+// apply 将当前配置中已修改的表应用到运行中的组件
+func (r *configReloader) apply(sections map[string]bool) {
+	c := r.current
+	if sections["logger"] {
+		r.consoleLogger.SetLevels(c.Logger.EnableDebug, c.Logger.EnableInfo, c.Logger.EnableWarn, c.Logger.EnableError)
+	}
+	if sections["server"] {
+		if err := r.srv.SetAllowedRequestHeaders(c.Server.AllowedRequestHeaders); err != nil {
+			log.Printf("警告: 应用请求头允许列表失败: %v", err)
+		}
+		r.srv.SetMaxBodyBytes(c.Server.MaxBodyBytes)
+	}
+	if sections["UtlsClient"] {
+		client := r.srv.GetUTLSClient()
+		client.SetPoolLimits(c.UtlsClient.ToPoolLimits())
+		client.SetRateLimits(c.UtlsClient.ToRateLimits())
+	}
+	if sections["TaskTimeouts"] {
+		r.srv.SetTaskTimeouts(c.TaskTimeouts.ToTaskTimeouts())
+	}
+	if sections["RockTreeDataConfig"] {
+		r.srv.SetRockTreeDataConfig(
+			c.RockTreeData.Enable,
+			c.RockTreeData.HostName,
+			c.RockTreeData.BulkMetadataPath,
+			c.RockTreeData.NodeDataPath,
+			c.RockTreeData.ImageryDataPath,
+		)
+	}
+	if sections["GoogleEarthDesktopDataConfig"] {
+		r.srv.SetGoogleEarthDesktopDataConfig(
+			c.GoogleEarthDesktopData.Enable,
+			c.GoogleEarthDesktopData.HostName,
+			c.GoogleEarthDesktopData.Q2Path,
+			c.GoogleEarthDesktopData.ImageryPath,
+			c.GoogleEarthDesktopData.TerrainPath,
+		)
+		r.srv.SetGoogleEarthMultiDBDataConfig(
+			c.GoogleEarthDesktopData.TMHostName,
+			c.GoogleEarthDesktopData.QPPath,
+			c.GoogleEarthDesktopData.ImageryHistoryPath,
+		)
+	}
+	if sections["Sites"] {
+		if err := r.srv.SetSites(c.ToSites()); err != nil {
+			log.Printf("警告: 应用站点配置失败: %v", err)
+		}
+	}
+	if sections["DNSDomain"] {
+		r.domainMonitor.SetDomains(c.DNSDomain.HostName)
+		r.srv.SetDomainMonitor(r.domainMonitor, c.DNSDomain.HostName)
+	}
+}
+
+// siteHostNames 返回站点主机名集合（已排序、去重）
+func siteHostNames(c *Config) []string {
+	var hosts []string
+	for _, site := range c.Sites {
+		hosts = append(hosts, site.HostName)
+	}
+	slices.Sort(hosts)
+	return slices.Compact(hosts)
+}
+
+// tomlKey 返回结构体字段在配置文件中的键名
+func tomlKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -37,6 +37,7 @@ const (
 	TaskType_TASK_TYPE_GOOGLE_EARTH_IMAGERY_HISTORY        TaskType = 5 // 获取Imagery历史数据
 	TaskType_TASK_TYPE_GOOGLE_EARTH_QP                     TaskType = 6 // 获取qpQ2数据
 	TaskType_TASK_TYPE_GOOGLE_EARTH_TERRAIN                TaskType = 7 // 获取Terrain数据
+	TaskType_TASK_TYPE_HTTP_SITE                           TaskType = 8 // 通用 HTTP 站点任务（站点在配置文件 [[Sites]] 中声明，使用 site / path_template / path 字段）
 )
 
 // Enum value maps for TaskType.
@@ -50,6 +51,7 @@ var (
 		5: "TASK_TYPE_GOOGLE_EARTH_IMAGERY_HISTORY",
 		6: "TASK_TYPE_GOOGLE_EARTH_QP",
 		7: "TASK_TYPE_GOOGLE_EARTH_TERRAIN",
+		8: "TASK_TYPE_HTTP_SITE",
 	}
 	TaskType_value = map[string]int32{
 		"TASK_TYPE_UNKNOWN":                             0,
@@ -60,6 +62,7 @@ var (
 		"TASK_TYPE_GOOGLE_EARTH_IMAGERY_HISTORY":        5,
 		"TASK_TYPE_GOOGLE_EARTH_QP":                     6,
 		"TASK_TYPE_GOOGLE_EARTH_TERRAIN":                7,
+		"TASK_TYPE_HTTP_SITE":                           8,
 	}
 )
 
@@ -95,10 +98,11 @@ func (TaskType) EnumDescriptor() ([]byte, []int) {
 type TasksStatus int32
 
 const (
-	TasksStatus_TASKS_STATUS_UNKNOWN TasksStatus = 0 // 未知状态 - 批量任务状态未初始化或无法确定
-	TasksStatus_TASKS_STATUS_RUNNING TasksStatus = 1 // 运行中 - 批量任务正在执行
-	TasksStatus_TASKS_STATUS_PAUSED  TasksStatus = 2 // 已暂停 - 批量任务已暂停，可以恢复
-	TasksStatus_TASKS_STATUS_STOPPED TasksStatus = 3 // 已停止 - 批量任务已停止，需要重新启动
+	TasksStatus_TASKS_STATUS_UNKNOWN   TasksStatus = 0 // 未知状态 - 批量任务状态未初始化或无法确定
+	TasksStatus_TASKS_STATUS_RUNNING   TasksStatus = 1 // 运行中 - 批量任务正在执行
+	TasksStatus_TASKS_STATUS_PAUSED    TasksStatus = 2 // 已暂停 - 批量任务已暂停，可以恢复
+	TasksStatus_TASKS_STATUS_STOPPED   TasksStatus = 3 // 已停止 - 批量任务已停止，需要重新启动
+	TasksStatus_TASKS_STATUS_COMPLETED TasksStatus = 4 // 已完成 - 批量任务中的所有任务均已执行完毕
 )
 
 // Enum value maps for TasksStatus.
@@ -108,12 +112,14 @@ var (
 		1: "TASKS_STATUS_RUNNING",
 		2: "TASKS_STATUS_PAUSED",
 		3: "TASKS_STATUS_STOPPED",
+		4: "TASKS_STATUS_COMPLETED",
 	}
 	TasksStatus_value = map[string]int32{
-		"TASKS_STATUS_UNKNOWN": 0,
-		"TASKS_STATUS_RUNNING": 1,
-		"TASKS_STATUS_PAUSED":  2,
-		"TASKS_STATUS_STOPPED": 3,
+		"TASKS_STATUS_UNKNOWN":   0,
+		"TASKS_STATUS_RUNNING":   1,
+		"TASKS_STATUS_PAUSED":    2,
+		"TASKS_STATUS_STOPPED":   3,
+		"TASKS_STATUS_COMPLETED": 4,
 	}
 )
 
@@ -318,6 +324,255 @@ func (TaskMethod) EnumDescriptor() ([]byte, []int) {
 	return file_TasksManager_proto_rawDescGZIP(), []int{4}
 }
 
+// TaskResponseSource 任务响应数据来源枚举
+// 用于表示任务响应体的来源
+type TaskResponseSource int32
+
+const (
+	TaskResponseSource_TASK_RESPONSE_SOURCE_UPSTREAM TaskResponseSource = 0 // 上游 - 通过热连接池从上游服务器获取
+	TaskResponseSource_TASK_RESPONSE_SOURCE_STORAGE  TaskResponseSource = 1 // 存储 - 直接从服务器端瓦片存储读取
+	TaskResponseSource_TASK_RESPONSE_SOURCE_CACHE    TaskResponseSource = 2 // 缓存 - 直接从服务器内存中的热点瓦片缓存读取
+)
+
+// Enum value maps for TaskResponseSource.
+var (
+	TaskResponseSource_name = map[int32]string{
+		0: "TASK_RESPONSE_SOURCE_UPSTREAM",
+		1: "TASK_RESPONSE_SOURCE_STORAGE",
+		2: "TASK_RESPONSE_SOURCE_CACHE",
+	}
+	TaskResponseSource_value = map[string]int32{
+		"TASK_RESPONSE_SOURCE_UPSTREAM": 0,
+		"TASK_RESPONSE_SOURCE_STORAGE":  1,
+		"TASK_RESPONSE_SOURCE_CACHE":    2,
+	}
+)
+
+func (x TaskResponseSource) Enum() *TaskResponseSource {
+	p := new(TaskResponseSource)
+	*p = x
+	return p
+}
+
+func (x TaskResponseSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskResponseSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_TasksManager_proto_enumTypes[5].Descriptor()
+}
+
+func (TaskResponseSource) Type() protoreflect.EnumType {
+	return &file_TasksManager_proto_enumTypes[5]
+}
+
+func (x TaskResponseSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskResponseSource.Descriptor instead.
+func (TaskResponseSource) EnumDescriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{5}
+}
+
+// TaskDecodeMode 任务响应体解码方式枚举
+// 用于在服务器端解密 / 解压 Google Earth 数据，客户端无需自行实现解密与密钥同步
+type TaskDecodeMode int32
+
+const (
+	TaskDecodeMode_TASK_DECODE_MODE_NONE TaskDecodeMode = 0 // 不解码 - 返回上游原始数据
+	TaskDecodeMode_TASK_DECODE_MODE_RAW  TaskDecodeMode = 1 // 解码 - 影像返回解密后的 JPEG，Q2/QP/地形返回解密解压后的数据包
+	TaskDecodeMode_TASK_DECODE_MODE_JSON TaskDecodeMode = 2 // 解析 - Q2 返回 ParseQ2Body 输出的 JSON，其他任务类型与 RAW 相同
+)
+
+// Enum value maps for TaskDecodeMode.
+var (
+	TaskDecodeMode_name = map[int32]string{
+		0: "TASK_DECODE_MODE_NONE",
+		1: "TASK_DECODE_MODE_RAW",
+		2: "TASK_DECODE_MODE_JSON",
+	}
+	TaskDecodeMode_value = map[string]int32{
+		"TASK_DECODE_MODE_NONE": 0,
+		"TASK_DECODE_MODE_RAW":  1,
+		"TASK_DECODE_MODE_JSON": 2,
+	}
+)
+
+func (x TaskDecodeMode) Enum() *TaskDecodeMode {
+	p := new(TaskDecodeMode)
+	*p = x
+	return p
+}
+
+func (x TaskDecodeMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskDecodeMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_TasksManager_proto_enumTypes[6].Descriptor()
+}
+
+func (TaskDecodeMode) Type() protoreflect.EnumType {
+	return &file_TasksManager_proto_enumTypes[6]
+}
+
+func (x TaskDecodeMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskDecodeMode.Descriptor instead.
+func (TaskDecodeMode) EnumDescriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{6}
+}
+
+// TaskPriority 任务优先级枚举
+// 上游并发已满时任务进入服务器端等待队列，高优先级任务先于低优先级任务执行
+type TaskPriority int32
+
+const (
+	TaskPriority_TASK_PRIORITY_UNSPECIFIED TaskPriority = 0 // 未设置 - SubmitTask 按 NORMAL 处理，作业中的任务按 BULK 处理
+	TaskPriority_TASK_PRIORITY_INTERACTIVE TaskPriority = 1 // 交互 - 用户正在等待的查询（如地图浏览）
+	TaskPriority_TASK_PRIORITY_NORMAL      TaskPriority = 2 // 普通
+	TaskPriority_TASK_PRIORITY_BULK        TaskPriority = 3 // 批量 - 后台回填、区域下载、发现作业
+)
+
+// Enum value maps for TaskPriority.
+var (
+	TaskPriority_name = map[int32]string{
+		0: "TASK_PRIORITY_UNSPECIFIED",
+		1: "TASK_PRIORITY_INTERACTIVE",
+		2: "TASK_PRIORITY_NORMAL",
+		3: "TASK_PRIORITY_BULK",
+	}
+	TaskPriority_value = map[string]int32{
+		"TASK_PRIORITY_UNSPECIFIED": 0,
+		"TASK_PRIORITY_INTERACTIVE": 1,
+		"TASK_PRIORITY_NORMAL":      2,
+		"TASK_PRIORITY_BULK":        3,
+	}
+)
+
+func (x TaskPriority) Enum() *TaskPriority {
+	p := new(TaskPriority)
+	*p = x
+	return p
+}
+
+func (x TaskPriority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskPriority) Descriptor() protoreflect.EnumDescriptor {
+	return file_TasksManager_proto_enumTypes[7].Descriptor()
+}
+
+func (TaskPriority) Type() protoreflect.EnumType {
+	return &file_TasksManager_proto_enumTypes[7]
+}
+
+func (x TaskPriority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskPriority.Descriptor instead.
+func (TaskPriority) EnumDescriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{7}
+}
+
+// TaskErrorCode 任务执行错误码枚举
+// 机器可读的失败原因，客户端据此决定是否重试，无需解析错误信息文本
+type TaskErrorCode int32
+
+const (
+	TaskErrorCode_TASK_ERROR_CODE_NONE                      TaskErrorCode = 0  // 无错误
+	TaskErrorCode_TASK_ERROR_CODE_POOL_WARMING              TaskErrorCode = 1  // 连接池预热中 - 稍后重试
+	TaskErrorCode_TASK_ERROR_CODE_ALL_CONNECTIONS_BUSY      TaskErrorCode = 2  // 所有连接都在使用中 - 稍后重试
+	TaskErrorCode_TASK_ERROR_CODE_ALL_CONNECTIONS_UNHEALTHY TaskErrorCode = 3  // 所有连接都不健康（正在异步激活）- 稍后重试
+	TaskErrorCode_TASK_ERROR_CODE_NO_AVAILABLE_CONNECTION   TaskErrorCode = 4  // 主机没有可用连接（IP 配置问题）
+	TaskErrorCode_TASK_ERROR_CODE_UPSTREAM_FORBIDDEN        TaskErrorCode = 5  // 上游返回 403 - 出口 IP 被拒绝
+	TaskErrorCode_TASK_ERROR_CODE_UPSTREAM_SERVER_ERROR     TaskErrorCode = 6  // 上游返回 5xx - 可重试
+	TaskErrorCode_TASK_ERROR_CODE_TRANSPORT_RESET           TaskErrorCode = 7  // 传输连接被重置或关闭 - 可重试
+	TaskErrorCode_TASK_ERROR_CODE_TIMEOUT                   TaskErrorCode = 8  // 请求超时 - 可重试
+	TaskErrorCode_TASK_ERROR_CODE_INTERNAL                  TaskErrorCode = 9  // 其他内部错误 - 不建议重试
+	TaskErrorCode_TASK_ERROR_CODE_DECODE_FAILED             TaskErrorCode = 10 // 响应体解码失败（数据损坏或解密密钥不匹配）
+	TaskErrorCode_TASK_ERROR_CODE_RATE_LIMITED              TaskErrorCode = 11 // 超过主机/远程 IP/本地源 IP 的速率限制 - 稍后重试
+	TaskErrorCode_TASK_ERROR_CODE_QUEUE_FULL                TaskErrorCode = 12 // 服务器等待队列已满 - 稍后重试
+	TaskErrorCode_TASK_ERROR_CODE_QUEUE_TIMEOUT             TaskErrorCode = 13 // 在等待队列中超过最长等待时间
+	TaskErrorCode_TASK_ERROR_CODE_SERVER_DRAINING           TaskErrorCode = 14 // 服务器正在停止，不再接受新任务 - 改为提交到其他节点
+	TaskErrorCode_TASK_ERROR_CODE_CANCELED                  TaskErrorCode = 15 // 客户端取消了任务（gRPC 上下文已取消）
+	TaskErrorCode_TASK_ERROR_CODE_BODY_TOO_LARGE            TaskErrorCode = 16 // 上游响应体超过大小上限，已中止下载
+)
+
+// Enum value maps for TaskErrorCode.
+var (
+	TaskErrorCode_name = map[int32]string{
+		0:  "TASK_ERROR_CODE_NONE",
+		1:  "TASK_ERROR_CODE_POOL_WARMING",
+		2:  "TASK_ERROR_CODE_ALL_CONNECTIONS_BUSY",
+		3:  "TASK_ERROR_CODE_ALL_CONNECTIONS_UNHEALTHY",
+		4:  "TASK_ERROR_CODE_NO_AVAILABLE_CONNECTION",
+		5:  "TASK_ERROR_CODE_UPSTREAM_FORBIDDEN",
+		6:  "TASK_ERROR_CODE_UPSTREAM_SERVER_ERROR",
+		7:  "TASK_ERROR_CODE_TRANSPORT_RESET",
+		8:  "TASK_ERROR_CODE_TIMEOUT",
+		9:  "TASK_ERROR_CODE_INTERNAL",
+		10: "TASK_ERROR_CODE_DECODE_FAILED",
+		11: "TASK_ERROR_CODE_RATE_LIMITED",
+		12: "TASK_ERROR_CODE_QUEUE_FULL",
+		13: "TASK_ERROR_CODE_QUEUE_TIMEOUT",
+		14: "TASK_ERROR_CODE_SERVER_DRAINING",
+		15: "TASK_ERROR_CODE_CANCELED",
+		16: "TASK_ERROR_CODE_BODY_TOO_LARGE",
+	}
+	TaskErrorCode_value = map[string]int32{
+		"TASK_ERROR_CODE_NONE":                      0,
+		"TASK_ERROR_CODE_POOL_WARMING":              1,
+		"TASK_ERROR_CODE_ALL_CONNECTIONS_BUSY":      2,
+		"TASK_ERROR_CODE_ALL_CONNECTIONS_UNHEALTHY": 3,
+		"TASK_ERROR_CODE_NO_AVAILABLE_CONNECTION":   4,
+		"TASK_ERROR_CODE_UPSTREAM_FORBIDDEN":        5,
+		"TASK_ERROR_CODE_UPSTREAM_SERVER_ERROR":     6,
+		"TASK_ERROR_CODE_TRANSPORT_RESET":           7,
+		"TASK_ERROR_CODE_TIMEOUT":                   8,
+		"TASK_ERROR_CODE_INTERNAL":                  9,
+		"TASK_ERROR_CODE_DECODE_FAILED":             10,
+		"TASK_ERROR_CODE_RATE_LIMITED":              11,
+		"TASK_ERROR_CODE_QUEUE_FULL":                12,
+		"TASK_ERROR_CODE_QUEUE_TIMEOUT":             13,
+		"TASK_ERROR_CODE_SERVER_DRAINING":           14,
+		"TASK_ERROR_CODE_CANCELED":                  15,
+		"TASK_ERROR_CODE_BODY_TOO_LARGE":            16,
+	}
+)
+
+func (x TaskErrorCode) Enum() *TaskErrorCode {
+	p := new(TaskErrorCode)
+	*p = x
+	return p
+}
+
+func (x TaskErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TaskErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_TasksManager_proto_enumTypes[8].Descriptor()
+}
+
+func (TaskErrorCode) Type() protoreflect.EnumType {
+	return &file_TasksManager_proto_enumTypes[8]
+}
+
+func (x TaskErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TaskErrorCode.Descriptor instead.
+func (TaskErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{8}
+}
+
 // TaskClientInfo 任务客户端信息
 // 客户端与 gRPC 服务器连接时需要提供的信息，用于标识和跟踪客户端状态
 // 包含客户端的静态配置信息和实时资源使用情况
@@ -651,13 +906,14 @@ func (x *RegisterClientResponse) GetServerNodes() []*GrpcServerNodeInfo {
 }
 
 // ClientHeartbeatResponse 客户端心跳响应
-// 客户端心跳请求的响应，可能包含新上线的服务器节点信息
+// 客户端心跳请求的响应，可能包含新上线与已下线的服务器节点信息
 type ClientHeartbeatResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`                                      // 心跳是否成功
-	NewServerNodes []*GrpcServerNodeInfo  `protobuf:"bytes,2,rep,name=new_server_nodes,json=newServerNodes,proto3" json:"new_server_nodes,omitempty"` // 新上线的服务器节点列表（如果有）
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Success            bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`                                                  // 心跳是否成功
+	NewServerNodes     []*GrpcServerNodeInfo  `protobuf:"bytes,2,rep,name=new_server_nodes,json=newServerNodes,proto3" json:"new_server_nodes,omitempty"`             // 新上线的服务器节点列表（如果有）
+	RemovedServerNodes []string               `protobuf:"bytes,3,rep,name=removed_server_nodes,json=removedServerNodes,proto3" json:"removed_server_nodes,omitempty"` // 已下线或正在停止的服务器节点地址（IP:Port），客户端应停止向这些节点提交任务
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *ClientHeartbeatResponse) Reset() {
@@ -704,6 +960,13 @@ func (x *ClientHeartbeatResponse) GetNewServerNodes() []*GrpcServerNodeInfo {
 	return nil
 }
 
+func (x *ClientHeartbeatResponse) GetRemovedServerNodes() []string {
+	if x != nil {
+		return x.RemovedServerNodes
+	}
+	return nil
+}
+
 // GrpcServerNodeInfo gRPC 服务器节点信息
 // 用于标识和跟踪 gRPC 服务器节点的状态和配置信息
 // 包含节点的静态配置信息和实时资源使用情况
@@ -1212,7 +1475,7 @@ type NodeMessage struct {
 	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`            // 消息唯一标识符
 	FromNodeUuid  string                 `protobuf:"bytes,2,opt,name=from_node_uuid,json=fromNodeUuid,proto3" json:"from_node_uuid,omitempty"` // 发送方节点 UUID
 	ToNodeUuid    string                 `protobuf:"bytes,3,opt,name=to_node_uuid,json=toNodeUuid,proto3" json:"to_node_uuid,omitempty"`       // 接收方节点 UUID（空字符串表示广播）
-	MessageType   string                 `protobuf:"bytes,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`      // 消息类型（如 "TASK_REQUEST", "TASK_RESULT", "SYNC_REQUEST" 等）
+	MessageType   string                 `protobuf:"bytes,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`      // 消息类型（如 "SYNC_REQUEST" 等；任务转发直接调用对端的 SubmitTask，见 TaskRequest.forward_path）
 	Payload       []byte                 `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`                                 // 消息负载（JSON 或其他格式的数据）
 	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                            // 消息时间戳（Unix 时间戳，毫秒）
 	Ttl           *int32                 `protobuf:"varint,7,opt,name=ttl,proto3,oneof" json:"ttl,omitempty"`                                  // 消息生存时间（跳数，用于限制广播范围）
@@ -1535,9 +1798,31 @@ type TaskRequest struct {
 	Epoch        int32  `protobuf:"varint,4,opt,name=epoch,proto3" json:"epoch,omitempty"`                     // 主版本号（Epoch）
 	ImageryEpoch *int32 `protobuf:"varint,5,opt,name=imageryEpoch,proto3,oneof" json:"imageryEpoch,omitempty"` // 影像版本号（可选）
 	// 通用 HTTP 相关字段
-	TaskBody      []byte      `protobuf:"bytes,6,opt,name=task_body,json=taskBody,proto3,oneof" json:"task_body,omitempty"`                                     // 任务请求体，HTTP 请求的 body 内容（如 POST 请求的数据）
-	TaskMethod    *TaskMethod `protobuf:"varint,7,opt,name=task_method,json=taskMethod,proto3,enum=tasksmanager.TaskMethod,oneof" json:"task_method,omitempty"` // HTTP 请求方法（GET、POST、PUT、DELETE 等）
-	TaskStatus    *TaskStatus `protobuf:"varint,8,opt,name=task_status,json=taskStatus,proto3,enum=tasksmanager.TaskStatus,oneof" json:"task_status,omitempty"` // 任务初始状态（通常为 PENDING）
+	TaskBody   []byte      `protobuf:"bytes,6,opt,name=task_body,json=taskBody,proto3,oneof" json:"task_body,omitempty"`                                     // 任务请求体，HTTP 请求的 body 内容（如 POST 请求的数据）
+	TaskMethod *TaskMethod `protobuf:"varint,7,opt,name=task_method,json=taskMethod,proto3,enum=tasksmanager.TaskMethod,oneof" json:"task_method,omitempty"` // HTTP 请求方法（GET、POST、PUT、DELETE 等）
+	TaskStatus *TaskStatus `protobuf:"varint,8,opt,name=task_status,json=taskStatus,proto3,enum=tasksmanager.TaskStatus,oneof" json:"task_status,omitempty"` // 任务初始状态（通常为 PENDING）
+	// RockTree 相关字段（TileKey 此时为八叉树路径，如 "2060"）
+	TextureFormat *int32 `protobuf:"varint,9,opt,name=texture_format,json=textureFormat,proto3,oneof" json:"texture_format,omitempty"` // 纹理格式（NodeData 任务使用，对应 RockTree Texture.Format，未设置时为 1=JPG）
+	// 多数据库 / 历史影像相关字段
+	DbName  *string `protobuf:"bytes,10,opt,name=db_name,json=dbName,proto3,oneof" json:"db_name,omitempty"`    // 数据库名称（tm/mars/moon/sky，QP 任务使用，未设置时为 tm）
+	DateHex *string `protobuf:"bytes,11,opt,name=date_hex,json=dateHex,proto3,oneof" json:"date_hex,omitempty"` // 历史影像日期（十六进制字符串，IMAGERY_HISTORY 任务使用）
+	// 存储相关字段
+	ProviderId *int32 `protobuf:"varint,12,opt,name=provider_id,json=providerId,proto3,oneof" json:"provider_id,omitempty"` // 数据提供商 ID（来自 Q2 引用，写入存储元数据）
+	// 解码相关字段（仅 Google Earth Desktop 数据任务支持，存储中始终保存上游原始数据）
+	Decode TaskDecodeMode `protobuf:"varint,13,opt,name=decode,proto3,enum=tasksmanager.TaskDecodeMode" json:"decode,omitempty"` // 响应体解码方式（未设置时返回原始数据）
+	// 节点间转发相关字段（由服务器填写，客户端无需设置）
+	ForwardPath []string `protobuf:"bytes,14,rep,name=forward_path,json=forwardPath,proto3" json:"forward_path,omitempty"` // 已转发经过的服务器节点 UUID（用于限制跳数并避免转发回路）
+	// 调度相关字段
+	Priority TaskPriority `protobuf:"varint,15,opt,name=priority,proto3,enum=tasksmanager.TaskPriority" json:"priority,omitempty"` // 任务优先级（上游并发已满时决定等待队列中的执行顺序）
+	// 通用 HTTP 站点相关字段（HTTP_SITE 任务使用）
+	Site         *string           `protobuf:"bytes,16,opt,name=site,proto3,oneof" json:"site,omitempty"`                                                                                                   // 站点名称（对应配置文件中 [[Sites]] 的 name）
+	PathTemplate *string           `protobuf:"bytes,17,opt,name=path_template,json=pathTemplate,proto3,oneof" json:"path_template,omitempty"`                                                               // 路径模板名称（对应站点 path_templates 中的键）
+	PathParams   map[string]string `protobuf:"bytes,18,rep,name=path_params,json=pathParams,proto3" json:"path_params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 路径模板参数（替换模板中的 {参数名}，值会被转义）
+	Path         *string           `protobuf:"bytes,19,opt,name=path,proto3,oneof" json:"path,omitempty"`                                                                                                   // 完整请求路径（包含查询参数，站点允许时代替路径模板）
+	// 请求头相关字段
+	RequestHeaders map[string]string `protobuf:"bytes,20,rep,name=request_headers,json=requestHeaders,proto3" json:"request_headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 附加的请求头（如 If-None-Match、Range、Cookie，只允许服务器 allowed_request_headers 中的请求头）
+	// 响应体大小相关字段
+	MaxBodyBytes  *int64 `protobuf:"varint,21,opt,name=max_body_bytes,json=maxBodyBytes,proto3,oneof" json:"max_body_bytes,omitempty"` // 响应体大小上限（字节，只能低于服务器的 max_body_bytes，超过时中止下载）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1628,6 +1913,97 @@ func (x *TaskRequest) GetTaskStatus() TaskStatus {
 	return TaskStatus_TASK_STATUS_PENDING
 }
 
+func (x *TaskRequest) GetTextureFormat() int32 {
+	if x != nil && x.TextureFormat != nil {
+		return *x.TextureFormat
+	}
+	return 0
+}
+
+func (x *TaskRequest) GetDbName() string {
+	if x != nil && x.DbName != nil {
+		return *x.DbName
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetDateHex() string {
+	if x != nil && x.DateHex != nil {
+		return *x.DateHex
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetProviderId() int32 {
+	if x != nil && x.ProviderId != nil {
+		return *x.ProviderId
+	}
+	return 0
+}
+
+func (x *TaskRequest) GetDecode() TaskDecodeMode {
+	if x != nil {
+		return x.Decode
+	}
+	return TaskDecodeMode_TASK_DECODE_MODE_NONE
+}
+
+func (x *TaskRequest) GetForwardPath() []string {
+	if x != nil {
+		return x.ForwardPath
+	}
+	return nil
+}
+
+func (x *TaskRequest) GetPriority() TaskPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return TaskPriority_TASK_PRIORITY_UNSPECIFIED
+}
+
+func (x *TaskRequest) GetSite() string {
+	if x != nil && x.Site != nil {
+		return *x.Site
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetPathTemplate() string {
+	if x != nil && x.PathTemplate != nil {
+		return *x.PathTemplate
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetPathParams() map[string]string {
+	if x != nil {
+		return x.PathParams
+	}
+	return nil
+}
+
+func (x *TaskRequest) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetRequestHeaders() map[string]string {
+	if x != nil {
+		return x.RequestHeaders
+	}
+	return nil
+}
+
+func (x *TaskRequest) GetMaxBodyBytes() int64 {
+	if x != nil && x.MaxBodyBytes != nil {
+		return *x.MaxBodyBytes
+	}
+	return 0
+}
+
 // TaskResponse 任务响应消息
 // 任务执行完成后返回的响应结果
 // 保持与 TaskRequest 对应的瓦片键和版本信息，便于结果归属
@@ -1640,8 +2016,11 @@ type TaskResponse struct {
 	Epoch        int32  `protobuf:"varint,4,opt,name=Epoch,proto3" json:"Epoch,omitempty"`                     // 主版本号（Epoch）
 	ImageryEpoch *int32 `protobuf:"varint,5,opt,name=imageryEpoch,proto3,oneof" json:"imageryEpoch,omitempty"` // 影像版本号（可选）
 	// HTTP 结果
-	TaskResponseBody       []byte `protobuf:"bytes,6,opt,name=task_response_body,json=taskResponseBody,proto3,oneof" json:"task_response_body,omitempty"`                      // HTTP 响应体内容（可选，任务失败时可能为空）
-	TaskResponseStatusCode *int32 `protobuf:"varint,7,opt,name=task_response_status_code,json=taskResponseStatusCode,proto3,oneof" json:"task_response_status_code,omitempty"` // HTTP 响应状态码（可选，如 200、404、500 等）
+	TaskResponseBody       []byte             `protobuf:"bytes,6,opt,name=task_response_body,json=taskResponseBody,proto3,oneof" json:"task_response_body,omitempty"`                         // HTTP 响应体内容（可选，任务失败时可能为空）
+	TaskResponseStatusCode *int32             `protobuf:"varint,7,opt,name=task_response_status_code,json=taskResponseStatusCode,proto3,oneof" json:"task_response_status_code,omitempty"`    // HTTP 响应状态码（可选，如 200、404、500 等）
+	ResponseSource         TaskResponseSource `protobuf:"varint,8,opt,name=response_source,json=responseSource,proto3,enum=tasksmanager.TaskResponseSource" json:"response_source,omitempty"` // 响应数据来源（上游 / 服务器端存储）
+	ErrorCode              TaskErrorCode      `protobuf:"varint,9,opt,name=error_code,json=errorCode,proto3,enum=tasksmanager.TaskErrorCode" json:"error_code,omitempty"`                     // 任务执行错误码（成功时为 NONE）
+	ResponseHeaders        []*TaskHeader      `protobuf:"bytes,10,rep,name=response_headers,json=responseHeaders,proto3" json:"response_headers,omitempty"`                                   // 上游响应头（只有从上游获取响应的任务返回，同名响应头按出现顺序各占一项）
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
@@ -1725,27 +2104,50 @@ func (x *TaskResponse) GetTaskResponseStatusCode() int32 {
 	return 0
 }
 
-// TUICConfigRequest TUIC 配置请求（空请求）
-type TUICConfigRequest struct {
+func (x *TaskResponse) GetResponseSource() TaskResponseSource {
+	if x != nil {
+		return x.ResponseSource
+	}
+	return TaskResponseSource_TASK_RESPONSE_SOURCE_UPSTREAM
+}
+
+func (x *TaskResponse) GetErrorCode() TaskErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return TaskErrorCode_TASK_ERROR_CODE_NONE
+}
+
+func (x *TaskResponse) GetResponseHeaders() []*TaskHeader {
+	if x != nil {
+		return x.ResponseHeaders
+	}
+	return nil
+}
+
+// TaskHeader HTTP 头（名称为规范化格式，如 Content-Type）
+type TaskHeader struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TUICConfigRequest) Reset() {
-	*x = TUICConfigRequest{}
+func (x *TaskHeader) Reset() {
+	*x = TaskHeader{}
 	mi := &file_TasksManager_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TUICConfigRequest) String() string {
+func (x *TaskHeader) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TUICConfigRequest) ProtoMessage() {}
+func (*TaskHeader) ProtoMessage() {}
 
-func (x *TUICConfigRequest) ProtoReflect() protoreflect.Message {
+func (x *TaskHeader) ProtoReflect() protoreflect.Message {
 	mi := &file_TasksManager_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1757,40 +2159,54 @@ func (x *TUICConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TUICConfigRequest.ProtoReflect.Descriptor instead.
-func (*TUICConfigRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TaskHeader.ProtoReflect.Descriptor instead.
+func (*TaskHeader) Descriptor() ([]byte, []int) {
 	return file_TasksManager_proto_rawDescGZIP(), []int{19}
 }
 
-// TUICConfigResponse TUIC 配置响应
-type TUICConfigResponse struct {
+func (x *TaskHeader) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TaskHeader) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// TaskChunk 流式下载的响应分块
+// 第一条消息只包含 response（状态码、响应头等，不含响应体），之后每条消息包含一段响应体，
+// 最后一条消息的 last 为 true，并携带完整响应体的大小与 SHA-256 校验和
+type TaskChunk struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`      // 是否成功
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`       // 响应消息
-	Enabled       bool                   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`      // TUIC 服务器是否启用
-	Address       string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`       // TUIC 服务器地址
-	Port          string                 `protobuf:"bytes,5,opt,name=port,proto3" json:"port,omitempty"`             // TUIC 服务器端口
-	Uuid          string                 `protobuf:"bytes,6,opt,name=uuid,proto3" json:"uuid,omitempty"`             // TUIC UUID（用于客户端连接）
-	Password      string                 `protobuf:"bytes,7,opt,name=password,proto3" json:"password,omitempty"`     // TUIC 密码（用于客户端连接）
-	Congestion    string                 `protobuf:"bytes,8,opt,name=congestion,proto3" json:"congestion,omitempty"` // 拥塞控制算法
+	Response      *TaskResponse          `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`                     // 任务响应（仅第一条消息，task_response_body 为空）
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`                             // 响应体分块
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`                        // 分块在响应体中的偏移量
+	Last          bool                   `protobuf:"varint,4,opt,name=last,proto3" json:"last,omitempty"`                            // 是否为最后一条消息
+	TotalSize     int64                  `protobuf:"varint,5,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"` // 响应体总大小（仅最后一条消息）
+	Sha256        string                 `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"`                         // 响应体的 SHA-256 校验和（十六进制，仅最后一条消息）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TUICConfigResponse) Reset() {
-	*x = TUICConfigResponse{}
+func (x *TaskChunk) Reset() {
+	*x = TaskChunk{}
 	mi := &file_TasksManager_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TUICConfigResponse) String() string {
+func (x *TaskChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TUICConfigResponse) ProtoMessage() {}
+func (*TaskChunk) ProtoMessage() {}
 
-func (x *TUICConfigResponse) ProtoReflect() protoreflect.Message {
+func (x *TaskChunk) ProtoReflect() protoreflect.Message {
 	mi := &file_TasksManager_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1802,69 +2218,1297 @@ func (x *TUICConfigResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TUICConfigResponse.ProtoReflect.Descriptor instead.
-func (*TUICConfigResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use TaskChunk.ProtoReflect.Descriptor instead.
+func (*TaskChunk) Descriptor() ([]byte, []int) {
 	return file_TasksManager_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *TUICConfigResponse) GetSuccess() bool {
+func (x *TaskChunk) GetResponse() *TaskResponse {
 	if x != nil {
-		return x.Success
+		return x.Response
 	}
-	return false
+	return nil
 }
 
-func (x *TUICConfigResponse) GetMessage() string {
+func (x *TaskChunk) GetData() []byte {
 	if x != nil {
-		return x.Message
+		return x.Data
 	}
-	return ""
+	return nil
 }
 
-func (x *TUICConfigResponse) GetEnabled() bool {
+func (x *TaskChunk) GetOffset() int64 {
 	if x != nil {
-		return x.Enabled
+		return x.Offset
 	}
-	return false
+	return 0
 }
 
-func (x *TUICConfigResponse) GetAddress() string {
+func (x *TaskChunk) GetLast() bool {
 	if x != nil {
-		return x.Address
+		return x.Last
 	}
-	return ""
+	return false
 }
 
-func (x *TUICConfigResponse) GetPort() string {
+func (x *TaskChunk) GetTotalSize() int64 {
 	if x != nil {
-		return x.Port
+		return x.TotalSize
 	}
-	return ""
+	return 0
 }
 
-func (x *TUICConfigResponse) GetUuid() string {
+func (x *TaskChunk) GetSha256() string {
 	if x != nil {
-		return x.Uuid
+		return x.Sha256
 	}
 	return ""
 }
 
-func (x *TUICConfigResponse) GetPassword() string {
-	if x != nil {
-		return x.Password
-	}
-	return ""
+// TaskStreamRequest 流式任务请求消息
+// SubmitTaskStream 中客户端推送的单个任务，通过关联 ID 与乱序返回的响应对应
+type TaskStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"` // 关联 ID（由客户端生成，服务器在响应中原样返回）
+	Task          *TaskRequest           `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`                                        // 任务请求
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TUICConfigResponse) GetCongestion() string {
-	if x != nil {
-		return x.Congestion
-	}
-	return ""
+func (x *TaskStreamRequest) Reset() {
+	*x = TaskStreamRequest{}
+	mi := &file_TasksManager_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-var File_TasksManager_proto protoreflect.FileDescriptor
-
+func (x *TaskStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStreamRequest) ProtoMessage() {}
+
+func (x *TaskStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStreamRequest.ProtoReflect.Descriptor instead.
+func (*TaskStreamRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TaskStreamRequest) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *TaskStreamRequest) GetTask() *TaskRequest {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// TaskStreamResponse 流式任务响应消息
+// SubmitTaskStream 中服务器推送的任务结果或流控额度
+// 流建立后服务器首先推送一条只包含 credits 的消息（初始额度），之后每个任务完成时归还 1 个额度
+// 客户端未完成（已发送但未收到响应）的任务数不得超过累计获得的额度，否则服务器以 RESOURCE_EXHAUSTED 结束流
+type TaskStreamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"` // 关联 ID（与请求中的对应，仅包含额度的消息为空）
+	Task          *TaskResponse          `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`                                        // 任务响应（任务无法执行时为空；执行失败时包含 error_code，见 error）
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`                                      // 任务无法执行或执行失败时的错误信息（如参数错误、配置未启用、连接池预热中）
+	Credits       int32                  `protobuf:"varint,4,opt,name=credits,proto3" json:"credits,omitempty"`                                 // 本条消息授予的流控额度（客户端可额外发送的任务数）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskStreamResponse) Reset() {
+	*x = TaskStreamResponse{}
+	mi := &file_TasksManager_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStreamResponse) ProtoMessage() {}
+
+func (x *TaskStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStreamResponse.ProtoReflect.Descriptor instead.
+func (*TaskStreamResponse) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TaskStreamResponse) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *TaskStreamResponse) GetTask() *TaskResponse {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TaskStreamResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TaskStreamResponse) GetCredits() int32 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+// CreateJobRequest 创建作业请求
+// 作业是一组在服务器后台执行的瓦片任务，执行进度持久化保存，不依赖客户端进程在线
+type CreateJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`   // 作业名称（便于识别，可重复）
+	Tasks         []*TaskRequest         `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"` // 作业包含的任务列表
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateJobRequest) Reset() {
+	*x = CreateJobRequest{}
+	mi := &file_TasksManager_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateJobRequest) ProtoMessage() {}
+
+func (x *CreateJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateJobRequest.ProtoReflect.Descriptor instead.
+func (*CreateJobRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *CreateJobRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateJobRequest) GetTasks() []*TaskRequest {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+// JobRequest 作业操作请求
+// 用于 GetJob、PauseJob、ResumeJob、CancelJob
+type JobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"` // 作业 ID（CreateJob 返回）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobRequest) Reset() {
+	*x = JobRequest{}
+	mi := &file_TasksManager_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobRequest) ProtoMessage() {}
+
+func (x *JobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobRequest.ProtoReflect.Descriptor instead.
+func (*JobRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *JobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// JobInfo 作业信息
+// 包含作业整体状态和各状态任务计数
+type JobInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	JobId          string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`                               // 作业 ID
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                              // 作业名称
+	Status         TasksStatus            `protobuf:"varint,3,opt,name=status,proto3,enum=tasksmanager.TasksStatus" json:"status,omitempty"`           // 作业整体状态（运行中/已暂停/已停止/已完成）
+	TotalCount     int64                  `protobuf:"varint,4,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`               // 任务总数
+	PendingCount   int64                  `protobuf:"varint,5,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"`         // 等待中的任务数
+	RunningCount   int64                  `protobuf:"varint,6,opt,name=running_count,json=runningCount,proto3" json:"running_count,omitempty"`         // 运行中的任务数
+	SuccessCount   int64                  `protobuf:"varint,7,opt,name=success_count,json=successCount,proto3" json:"success_count,omitempty"`         // 成功的任务数
+	FailedCount    int64                  `protobuf:"varint,8,opt,name=failed_count,json=failedCount,proto3" json:"failed_count,omitempty"`            // 失败的任务数
+	CreateTime     string                 `protobuf:"bytes,9,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`                // 作业创建时间（ISO 8601 格式字符串）
+	UpdateTime     string                 `protobuf:"bytes,10,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`               // 作业最后更新时间（ISO 8601 格式字符串）
+	CurrentTileKey string                 `protobuf:"bytes,11,opt,name=current_tile_key,json=currentTileKey,proto3" json:"current_tile_key,omitempty"` // 最近领取执行的任务瓦片键（用于观察作业进度）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *JobInfo) Reset() {
+	*x = JobInfo{}
+	mi := &file_TasksManager_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobInfo) ProtoMessage() {}
+
+func (x *JobInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobInfo.ProtoReflect.Descriptor instead.
+func (*JobInfo) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *JobInfo) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobInfo) GetStatus() TasksStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TasksStatus_TASKS_STATUS_UNKNOWN
+}
+
+func (x *JobInfo) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *JobInfo) GetPendingCount() int64 {
+	if x != nil {
+		return x.PendingCount
+	}
+	return 0
+}
+
+func (x *JobInfo) GetRunningCount() int64 {
+	if x != nil {
+		return x.RunningCount
+	}
+	return 0
+}
+
+func (x *JobInfo) GetSuccessCount() int64 {
+	if x != nil {
+		return x.SuccessCount
+	}
+	return 0
+}
+
+func (x *JobInfo) GetFailedCount() int64 {
+	if x != nil {
+		return x.FailedCount
+	}
+	return 0
+}
+
+func (x *JobInfo) GetCreateTime() string {
+	if x != nil {
+		return x.CreateTime
+	}
+	return ""
+}
+
+func (x *JobInfo) GetUpdateTime() string {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return ""
+}
+
+func (x *JobInfo) GetCurrentTileKey() string {
+	if x != nil {
+		return x.CurrentTileKey
+	}
+	return ""
+}
+
+// BoundingBox 经纬度边界框（单位：度）
+type BoundingBox struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinLat        float64                `protobuf:"fixed64,1,opt,name=min_lat,json=minLat,proto3" json:"min_lat,omitempty"` // 最小纬度（南边界）
+	MinLon        float64                `protobuf:"fixed64,2,opt,name=min_lon,json=minLon,proto3" json:"min_lon,omitempty"` // 最小经度（西边界）
+	MaxLat        float64                `protobuf:"fixed64,3,opt,name=max_lat,json=maxLat,proto3" json:"max_lat,omitempty"` // 最大纬度（北边界）
+	MaxLon        float64                `protobuf:"fixed64,4,opt,name=max_lon,json=maxLon,proto3" json:"max_lon,omitempty"` // 最大经度（东边界）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoundingBox) Reset() {
+	*x = BoundingBox{}
+	mi := &file_TasksManager_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoundingBox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoundingBox) ProtoMessage() {}
+
+func (x *BoundingBox) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoundingBox.ProtoReflect.Descriptor instead.
+func (*BoundingBox) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BoundingBox) GetMinLat() float64 {
+	if x != nil {
+		return x.MinLat
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMinLon() float64 {
+	if x != nil {
+		return x.MinLon
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMaxLat() float64 {
+	if x != nil {
+		return x.MaxLat
+	}
+	return 0
+}
+
+func (x *BoundingBox) GetMaxLon() float64 {
+	if x != nil {
+		return x.MaxLon
+	}
+	return 0
+}
+
+// CreateRegionJobRequest 创建区域爬取作业请求
+// 按边界框和层级范围惰性枚举瓦片键（不预先生成任务列表），对每个瓦片依次执行指定类型的任务
+// 多个边界框重叠时，重叠部分的瓦片会被重复执行
+type CreateRegionJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                                               // 作业名称（便于识别，可重复）
+	Bboxes        []*BoundingBox         `protobuf:"bytes,2,rep,name=bboxes,proto3" json:"bboxes,omitempty"`                                                           // 边界框列表（至少一个）
+	MinLevel      int32                  `protobuf:"varint,3,opt,name=min_level,json=minLevel,proto3" json:"min_level,omitempty"`                                      // 最小层级（含，0 为根节点）
+	MaxLevel      int32                  `protobuf:"varint,4,opt,name=max_level,json=maxLevel,proto3" json:"max_level,omitempty"`                                      // 最大层级（含）
+	TaskTypes     []TaskType             `protobuf:"varint,5,rep,packed,name=task_types,json=taskTypes,proto3,enum=tasksmanager.TaskType" json:"task_types,omitempty"` // 每个瓦片执行的任务类型（支持 Q2、QP、IMAGERY、TERRAIN）
+	Epoch         int32                  `protobuf:"varint,6,opt,name=epoch,proto3" json:"epoch,omitempty"`                                                            // 主版本号（Q2、QP、TERRAIN 任务使用）
+	ImageryEpoch  *int32                 `protobuf:"varint,7,opt,name=imageryEpoch,proto3,oneof" json:"imageryEpoch,omitempty"`                                        // 影像版本号（IMAGERY 任务必需）
+	DbName        *string                `protobuf:"bytes,8,opt,name=db_name,json=dbName,proto3,oneof" json:"db_name,omitempty"`                                       // 数据库名称（QP 任务使用，未设置时为 tm）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRegionJobRequest) Reset() {
+	*x = CreateRegionJobRequest{}
+	mi := &file_TasksManager_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRegionJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRegionJobRequest) ProtoMessage() {}
+
+func (x *CreateRegionJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRegionJobRequest.ProtoReflect.Descriptor instead.
+func (*CreateRegionJobRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateRegionJobRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateRegionJobRequest) GetBboxes() []*BoundingBox {
+	if x != nil {
+		return x.Bboxes
+	}
+	return nil
+}
+
+func (x *CreateRegionJobRequest) GetMinLevel() int32 {
+	if x != nil {
+		return x.MinLevel
+	}
+	return 0
+}
+
+func (x *CreateRegionJobRequest) GetMaxLevel() int32 {
+	if x != nil {
+		return x.MaxLevel
+	}
+	return 0
+}
+
+func (x *CreateRegionJobRequest) GetTaskTypes() []TaskType {
+	if x != nil {
+		return x.TaskTypes
+	}
+	return nil
+}
+
+func (x *CreateRegionJobRequest) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *CreateRegionJobRequest) GetImageryEpoch() int32 {
+	if x != nil && x.ImageryEpoch != nil {
+		return *x.ImageryEpoch
+	}
+	return 0
+}
+
+func (x *CreateRegionJobRequest) GetDbName() string {
+	if x != nil && x.DbName != nil {
+		return *x.DbName
+	}
+	return ""
+}
+
+// CreateDiscoveryJobRequest 创建 Q2 发现作业请求
+// 从起始 Q2 数据包开始，解析其中的子 Q2、影像和地形引用，递归跟踪子 Q2 直到最大层级
+// 只执行 Q2 中实际存在的影像/地形瓦片，待执行任务（前沿）随作业状态持久化，重启后继续执行
+type CreateDiscoveryJobRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                            // 作业名称（便于识别，可重复）
+	Epoch          int32                  `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`                                         // 起始 Q2 的版本号（子 Q2、影像、地形使用引用中的版本号）
+	MaxLevel       int32                  `protobuf:"varint,3,opt,name=max_level,json=maxLevel,proto3" json:"max_level,omitempty"`                   // 最大层级（瓦片键长度 - 1，超过该层级的引用不再跟踪）
+	IncludeImagery bool                   `protobuf:"varint,4,opt,name=include_imagery,json=includeImagery,proto3" json:"include_imagery,omitempty"` // 是否执行影像任务
+	IncludeTerrain bool                   `protobuf:"varint,5,opt,name=include_terrain,json=includeTerrain,proto3" json:"include_terrain,omitempty"` // 是否执行地形任务
+	RootTileKey    string                 `protobuf:"bytes,6,opt,name=root_tile_key,json=rootTileKey,proto3" json:"root_tile_key,omitempty"`         // 起始 Q2 瓦片键（未设置时为根节点 "0"）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateDiscoveryJobRequest) Reset() {
+	*x = CreateDiscoveryJobRequest{}
+	mi := &file_TasksManager_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDiscoveryJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDiscoveryJobRequest) ProtoMessage() {}
+
+func (x *CreateDiscoveryJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDiscoveryJobRequest.ProtoReflect.Descriptor instead.
+func (*CreateDiscoveryJobRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CreateDiscoveryJobRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateDiscoveryJobRequest) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *CreateDiscoveryJobRequest) GetMaxLevel() int32 {
+	if x != nil {
+		return x.MaxLevel
+	}
+	return 0
+}
+
+func (x *CreateDiscoveryJobRequest) GetIncludeImagery() bool {
+	if x != nil {
+		return x.IncludeImagery
+	}
+	return false
+}
+
+func (x *CreateDiscoveryJobRequest) GetIncludeTerrain() bool {
+	if x != nil {
+		return x.IncludeTerrain
+	}
+	return false
+}
+
+func (x *CreateDiscoveryJobRequest) GetRootTileKey() string {
+	if x != nil {
+		return x.RootTileKey
+	}
+	return ""
+}
+
+// ListJobsRequest 作业列表请求
+type ListJobsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_TasksManager_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{29}
+}
+
+// ListJobsResponse 作业列表响应
+type ListJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*JobInfo             `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"` // 服务器上的所有作业（按创建时间排序）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_TasksManager_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListJobsResponse) GetItems() []*JobInfo {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// CacheStatsRequest 热点瓦片缓存统计请求（空请求）
+type CacheStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheStatsRequest) Reset() {
+	*x = CacheStatsRequest{}
+	mi := &file_TasksManager_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStatsRequest) ProtoMessage() {}
+
+func (x *CacheStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStatsRequest.ProtoReflect.Descriptor instead.
+func (*CacheStatsRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{31}
+}
+
+// CacheStats 热点瓦片缓存与相同任务合并的统计信息（自服务器启动起累计）
+type CacheStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          int64                  `protobuf:"varint,1,opt,name=hits,proto3" json:"hits,omitempty"`                         // 缓存命中次数
+	Misses        int64                  `protobuf:"varint,2,opt,name=misses,proto3" json:"misses,omitempty"`                     // 缓存未命中次数
+	Coalesced     int64                  `protobuf:"varint,3,opt,name=coalesced,proto3" json:"coalesced,omitempty"`               // 与正在执行的相同任务合并（未单独请求上游）的次数
+	Evictions     int64                  `protobuf:"varint,4,opt,name=evictions,proto3" json:"evictions,omitempty"`               // 因容量不足被淘汰的条目数
+	Entries       int64                  `protobuf:"varint,5,opt,name=entries,proto3" json:"entries,omitempty"`                   // 当前缓存条目数
+	Bytes         int64                  `protobuf:"varint,6,opt,name=bytes,proto3" json:"bytes,omitempty"`                       // 当前缓存占用字节数
+	MaxBytes      int64                  `protobuf:"varint,7,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"` // 缓存容量上限（字节，0 表示未启用缓存）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheStats) Reset() {
+	*x = CacheStats{}
+	mi := &file_TasksManager_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheStats) ProtoMessage() {}
+
+func (x *CacheStats) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheStats.ProtoReflect.Descriptor instead.
+func (*CacheStats) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CacheStats) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *CacheStats) GetMisses() int64 {
+	if x != nil {
+		return x.Misses
+	}
+	return 0
+}
+
+func (x *CacheStats) GetCoalesced() int64 {
+	if x != nil {
+		return x.Coalesced
+	}
+	return 0
+}
+
+func (x *CacheStats) GetEvictions() int64 {
+	if x != nil {
+		return x.Evictions
+	}
+	return 0
+}
+
+func (x *CacheStats) GetEntries() int64 {
+	if x != nil {
+		return x.Entries
+	}
+	return 0
+}
+
+func (x *CacheStats) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *CacheStats) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+// SchedulerStatsRequest 任务调度统计请求（空请求）
+type SchedulerStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchedulerStatsRequest) Reset() {
+	*x = SchedulerStatsRequest{}
+	mi := &file_TasksManager_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulerStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerStatsRequest) ProtoMessage() {}
+
+func (x *SchedulerStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerStatsRequest.ProtoReflect.Descriptor instead.
+func (*SchedulerStatsRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{33}
+}
+
+// ReloadConfigRequest 重新加载配置请求（空请求）
+type ReloadConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadConfigRequest) Reset() {
+	*x = ReloadConfigRequest{}
+	mi := &file_TasksManager_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadConfigRequest) ProtoMessage() {}
+
+func (x *ReloadConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadConfigRequest.ProtoReflect.Descriptor instead.
+func (*ReloadConfigRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{34}
+}
+
+// ReloadConfigResponse 重新加载配置响应（配置项格式为 "表名.键名"）
+type ReloadConfigResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Applied         []string               `protobuf:"bytes,1,rep,name=applied,proto3" json:"applied,omitempty"`                                        // 已在运行时生效的配置项
+	RestartRequired []string               `protobuf:"bytes,2,rep,name=restart_required,json=restartRequired,proto3" json:"restart_required,omitempty"` // 已修改但需要重启服务器才能生效的配置项
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReloadConfigResponse) Reset() {
+	*x = ReloadConfigResponse{}
+	mi := &file_TasksManager_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadConfigResponse) ProtoMessage() {}
+
+func (x *ReloadConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadConfigResponse.ProtoReflect.Descriptor instead.
+func (*ReloadConfigResponse) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ReloadConfigResponse) GetApplied() []string {
+	if x != nil {
+		return x.Applied
+	}
+	return nil
+}
+
+func (x *ReloadConfigResponse) GetRestartRequired() []string {
+	if x != nil {
+		return x.RestartRequired
+	}
+	return nil
+}
+
+// SchedulerPriorityStats 单个优先级的调度统计（计数自服务器启动起累计）
+type SchedulerPriorityStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Priority      TaskPriority           `protobuf:"varint,1,opt,name=priority,proto3,enum=tasksmanager.TaskPriority" json:"priority,omitempty"` // 优先级
+	Queued        int64                  `protobuf:"varint,2,opt,name=queued,proto3" json:"queued,omitempty"`                                    // 当前在等待队列中的任务数
+	Admitted      int64                  `protobuf:"varint,3,opt,name=admitted,proto3" json:"admitted,omitempty"`                                // 获得执行名额的任务数
+	Rejected      int64                  `protobuf:"varint,4,opt,name=rejected,proto3" json:"rejected,omitempty"`                                // 因等待队列已满被拒绝的任务数
+	TimedOut      int64                  `protobuf:"varint,5,opt,name=timed_out,json=timedOut,proto3" json:"timed_out,omitempty"`                // 等待超时或调用方取消的任务数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchedulerPriorityStats) Reset() {
+	*x = SchedulerPriorityStats{}
+	mi := &file_TasksManager_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulerPriorityStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerPriorityStats) ProtoMessage() {}
+
+func (x *SchedulerPriorityStats) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerPriorityStats.ProtoReflect.Descriptor instead.
+func (*SchedulerPriorityStats) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SchedulerPriorityStats) GetPriority() TaskPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return TaskPriority_TASK_PRIORITY_UNSPECIFIED
+}
+
+func (x *SchedulerPriorityStats) GetQueued() int64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *SchedulerPriorityStats) GetAdmitted() int64 {
+	if x != nil {
+		return x.Admitted
+	}
+	return 0
+}
+
+func (x *SchedulerPriorityStats) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *SchedulerPriorityStats) GetTimedOut() int64 {
+	if x != nil {
+		return x.TimedOut
+	}
+	return 0
+}
+
+// SchedulerStats 任务调度统计
+type SchedulerStats struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Running       int64                     `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`                         // 当前正在请求上游的任务数
+	MaxRunning    int64                     `protobuf:"varint,2,opt,name=max_running,json=maxRunning,proto3" json:"max_running,omitempty"` // 同时请求上游的任务数上限
+	Queued        int64                     `protobuf:"varint,3,opt,name=queued,proto3" json:"queued,omitempty"`                           // 当前在等待队列中的任务数
+	MaxQueued     int64                     `protobuf:"varint,4,opt,name=max_queued,json=maxQueued,proto3" json:"max_queued,omitempty"`    // 等待队列容量
+	Priorities    []*SchedulerPriorityStats `protobuf:"bytes,5,rep,name=priorities,proto3" json:"priorities,omitempty"`                    // 各优先级的统计
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchedulerStats) Reset() {
+	*x = SchedulerStats{}
+	mi := &file_TasksManager_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchedulerStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchedulerStats) ProtoMessage() {}
+
+func (x *SchedulerStats) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchedulerStats.ProtoReflect.Descriptor instead.
+func (*SchedulerStats) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SchedulerStats) GetRunning() int64 {
+	if x != nil {
+		return x.Running
+	}
+	return 0
+}
+
+func (x *SchedulerStats) GetMaxRunning() int64 {
+	if x != nil {
+		return x.MaxRunning
+	}
+	return 0
+}
+
+func (x *SchedulerStats) GetQueued() int64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *SchedulerStats) GetMaxQueued() int64 {
+	if x != nil {
+		return x.MaxQueued
+	}
+	return 0
+}
+
+func (x *SchedulerStats) GetPriorities() []*SchedulerPriorityStats {
+	if x != nil {
+		return x.Priorities
+	}
+	return nil
+}
+
+// TUICConfigRequest TUIC 配置请求（空请求）
+type TUICConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TUICConfigRequest) Reset() {
+	*x = TUICConfigRequest{}
+	mi := &file_TasksManager_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TUICConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TUICConfigRequest) ProtoMessage() {}
+
+func (x *TUICConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TUICConfigRequest.ProtoReflect.Descriptor instead.
+func (*TUICConfigRequest) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{38}
+}
+
+// TUICConfigResponse TUIC 配置响应
+type TUICConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`      // 是否成功
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`       // 响应消息
+	Enabled       bool                   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`      // TUIC 服务器是否启用
+	Address       string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`       // TUIC 服务器地址
+	Port          string                 `protobuf:"bytes,5,opt,name=port,proto3" json:"port,omitempty"`             // TUIC 服务器端口
+	Uuid          string                 `protobuf:"bytes,6,opt,name=uuid,proto3" json:"uuid,omitempty"`             // TUIC UUID（用于客户端连接）
+	Password      string                 `protobuf:"bytes,7,opt,name=password,proto3" json:"password,omitempty"`     // TUIC 密码（用于客户端连接）
+	Congestion    string                 `protobuf:"bytes,8,opt,name=congestion,proto3" json:"congestion,omitempty"` // 拥塞控制算法
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TUICConfigResponse) Reset() {
+	*x = TUICConfigResponse{}
+	mi := &file_TasksManager_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TUICConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TUICConfigResponse) ProtoMessage() {}
+
+func (x *TUICConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_TasksManager_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TUICConfigResponse.ProtoReflect.Descriptor instead.
+func (*TUICConfigResponse) Descriptor() ([]byte, []int) {
+	return file_TasksManager_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *TUICConfigResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TUICConfigResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TUICConfigResponse) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *TUICConfigResponse) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *TUICConfigResponse) GetPort() string {
+	if x != nil {
+		return x.Port
+	}
+	return ""
+}
+
+func (x *TUICConfigResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *TUICConfigResponse) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *TUICConfigResponse) GetCongestion() string {
+	if x != nil {
+		return x.Congestion
+	}
+	return ""
+}
+
+var File_TasksManager_proto protoreflect.FileDescriptor
+
 const file_TasksManager_proto_rawDesc = "" +
 	"\n" +
 	"\x12TasksManager.proto\x12\ftasksmanager\"\x93\b\n" +
@@ -1905,10 +3549,11 @@ const file_TasksManager_proto_rawDesc = "" +
 	"\x16RegisterClientResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12C\n" +
-	"\fserver_nodes\x18\x03 \x03(\v2 .tasksmanager.GrpcServerNodeInfoR\vserverNodes\"\x7f\n" +
+	"\fserver_nodes\x18\x03 \x03(\v2 .tasksmanager.GrpcServerNodeInfoR\vserverNodes\"\xb1\x01\n" +
 	"\x17ClientHeartbeatResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12J\n" +
-	"\x10new_server_nodes\x18\x02 \x03(\v2 .tasksmanager.GrpcServerNodeInfoR\x0enewServerNodes\"\xc2\a\n" +
+	"\x10new_server_nodes\x18\x02 \x03(\v2 .tasksmanager.GrpcServerNodeInfoR\x0enewServerNodes\x120\n" +
+	"\x14removed_server_nodes\x18\x03 \x03(\tR\x12removedServerNodes\"\xc2\a\n" +
 	"\x12GrpcServerNodeInfo\x12\x1b\n" +
 	"\tnode_uuid\x18\x01 \x01(\tR\bnodeUuid\x12\x1b\n" +
 	"\tnode_name\x18\x02 \x01(\tR\bnodeName\x12\x17\n" +
@@ -1982,7 +3627,7 @@ const file_TasksManager_proto_rawDesc = "" +
 	"\fnodes_to_add\x18\x01 \x03(\v2 .tasksmanager.GrpcServerNodeInfoR\n" +
 	"nodesToAdd\x12&\n" +
 	"\x0fnodes_to_remove\x18\x02 \x03(\tR\rnodesToRemove\x12H\n" +
-	"\x0fnodes_to_update\x18\x03 \x03(\v2 .tasksmanager.GrpcServerNodeInfoR\rnodesToUpdate\"\xa2\x03\n" +
+	"\x0fnodes_to_update\x18\x03 \x03(\v2 .tasksmanager.GrpcServerNodeInfoR\rnodesToUpdate\"\xe3\t\n" +
 	"\vTaskRequest\x12$\n" +
 	"\x0etask_client_id\x18\x01 \x01(\tR\ftaskClientId\x123\n" +
 	"\ttask_type\x18\x02 \x01(\x0e2\x16.tasksmanager.TaskTypeR\btaskType\x12\x18\n" +
@@ -1993,12 +3638,44 @@ const file_TasksManager_proto_rawDesc = "" +
 	"\vtask_method\x18\a \x01(\x0e2\x18.tasksmanager.TaskMethodH\x02R\n" +
 	"taskMethod\x88\x01\x01\x12>\n" +
 	"\vtask_status\x18\b \x01(\x0e2\x18.tasksmanager.TaskStatusH\x03R\n" +
-	"taskStatus\x88\x01\x01B\x0f\n" +
+	"taskStatus\x88\x01\x01\x12*\n" +
+	"\x0etexture_format\x18\t \x01(\x05H\x04R\rtextureFormat\x88\x01\x01\x12\x1c\n" +
+	"\adb_name\x18\n" +
+	" \x01(\tH\x05R\x06dbName\x88\x01\x01\x12\x1e\n" +
+	"\bdate_hex\x18\v \x01(\tH\x06R\adateHex\x88\x01\x01\x12$\n" +
+	"\vprovider_id\x18\f \x01(\x05H\aR\n" +
+	"providerId\x88\x01\x01\x124\n" +
+	"\x06decode\x18\r \x01(\x0e2\x1c.tasksmanager.TaskDecodeModeR\x06decode\x12!\n" +
+	"\fforward_path\x18\x0e \x03(\tR\vforwardPath\x126\n" +
+	"\bpriority\x18\x0f \x01(\x0e2\x1a.tasksmanager.TaskPriorityR\bpriority\x12\x17\n" +
+	"\x04site\x18\x10 \x01(\tH\bR\x04site\x88\x01\x01\x12(\n" +
+	"\rpath_template\x18\x11 \x01(\tH\tR\fpathTemplate\x88\x01\x01\x12J\n" +
+	"\vpath_params\x18\x12 \x03(\v2).tasksmanager.TaskRequest.PathParamsEntryR\n" +
+	"pathParams\x12\x17\n" +
+	"\x04path\x18\x13 \x01(\tH\n" +
+	"R\x04path\x88\x01\x01\x12V\n" +
+	"\x0frequest_headers\x18\x14 \x03(\v2-.tasksmanager.TaskRequest.RequestHeadersEntryR\x0erequestHeaders\x12)\n" +
+	"\x0emax_body_bytes\x18\x15 \x01(\x03H\vR\fmaxBodyBytes\x88\x01\x01\x1a=\n" +
+	"\x0fPathParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aA\n" +
+	"\x13RequestHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x0f\n" +
 	"\r_imageryEpochB\f\n" +
 	"\n" +
 	"_task_bodyB\x0e\n" +
 	"\f_task_methodB\x0e\n" +
-	"\f_task_status\"\xfb\x02\n" +
+	"\f_task_statusB\x11\n" +
+	"\x0f_texture_formatB\n" +
+	"\n" +
+	"\b_db_nameB\v\n" +
+	"\t_date_hexB\x0e\n" +
+	"\f_provider_idB\a\n" +
+	"\x05_siteB\x10\n" +
+	"\x0e_path_templateB\a\n" +
+	"\x05_pathB\x11\n" +
+	"\x0f_max_body_bytes\"\xc7\x04\n" +
 	"\fTaskResponse\x12$\n" +
 	"\x0etask_client_id\x18\x01 \x01(\tR\ftaskClientId\x123\n" +
 	"\ttask_type\x18\x02 \x01(\x0e2\x16.tasksmanager.TaskTypeR\btaskType\x12\x18\n" +
@@ -2006,10 +3683,116 @@ const file_TasksManager_proto_rawDesc = "" +
 	"\x05Epoch\x18\x04 \x01(\x05R\x05Epoch\x12'\n" +
 	"\fimageryEpoch\x18\x05 \x01(\x05H\x00R\fimageryEpoch\x88\x01\x01\x121\n" +
 	"\x12task_response_body\x18\x06 \x01(\fH\x01R\x10taskResponseBody\x88\x01\x01\x12>\n" +
-	"\x19task_response_status_code\x18\a \x01(\x05H\x02R\x16taskResponseStatusCode\x88\x01\x01B\x0f\n" +
+	"\x19task_response_status_code\x18\a \x01(\x05H\x02R\x16taskResponseStatusCode\x88\x01\x01\x12I\n" +
+	"\x0fresponse_source\x18\b \x01(\x0e2 .tasksmanager.TaskResponseSourceR\x0eresponseSource\x12:\n" +
+	"\n" +
+	"error_code\x18\t \x01(\x0e2\x1b.tasksmanager.TaskErrorCodeR\terrorCode\x12C\n" +
+	"\x10response_headers\x18\n" +
+	" \x03(\v2\x18.tasksmanager.TaskHeaderR\x0fresponseHeadersB\x0f\n" +
 	"\r_imageryEpochB\x15\n" +
 	"\x13_task_response_bodyB\x1c\n" +
-	"\x1a_task_response_status_code\"\x13\n" +
+	"\x1a_task_response_status_code\"6\n" +
+	"\n" +
+	"TaskHeader\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\xba\x01\n" +
+	"\tTaskChunk\x126\n" +
+	"\bresponse\x18\x01 \x01(\v2\x1a.tasksmanager.TaskResponseR\bresponse\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04last\x18\x04 \x01(\bR\x04last\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x05 \x01(\x03R\ttotalSize\x12\x16\n" +
+	"\x06sha256\x18\x06 \x01(\tR\x06sha256\"i\n" +
+	"\x11TaskStreamRequest\x12%\n" +
+	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12-\n" +
+	"\x04task\x18\x02 \x01(\v2\x19.tasksmanager.TaskRequestR\x04task\"\x9b\x01\n" +
+	"\x12TaskStreamResponse\x12%\n" +
+	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12.\n" +
+	"\x04task\x18\x02 \x01(\v2\x1a.tasksmanager.TaskResponseR\x04task\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x18\n" +
+	"\acredits\x18\x04 \x01(\x05R\acredits\"W\n" +
+	"\x10CreateJobRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12/\n" +
+	"\x05tasks\x18\x02 \x03(\v2\x19.tasksmanager.TaskRequestR\x05tasks\"#\n" +
+	"\n" +
+	"JobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\x86\x03\n" +
+	"\aJobInfo\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x121\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x19.tasksmanager.TasksStatusR\x06status\x12\x1f\n" +
+	"\vtotal_count\x18\x04 \x01(\x03R\n" +
+	"totalCount\x12#\n" +
+	"\rpending_count\x18\x05 \x01(\x03R\fpendingCount\x12#\n" +
+	"\rrunning_count\x18\x06 \x01(\x03R\frunningCount\x12#\n" +
+	"\rsuccess_count\x18\a \x01(\x03R\fsuccessCount\x12!\n" +
+	"\ffailed_count\x18\b \x01(\x03R\vfailedCount\x12\x1f\n" +
+	"\vcreate_time\x18\t \x01(\tR\n" +
+	"createTime\x12\x1f\n" +
+	"\vupdate_time\x18\n" +
+	" \x01(\tR\n" +
+	"updateTime\x12(\n" +
+	"\x10current_tile_key\x18\v \x01(\tR\x0ecurrentTileKey\"q\n" +
+	"\vBoundingBox\x12\x17\n" +
+	"\amin_lat\x18\x01 \x01(\x01R\x06minLat\x12\x17\n" +
+	"\amin_lon\x18\x02 \x01(\x01R\x06minLon\x12\x17\n" +
+	"\amax_lat\x18\x03 \x01(\x01R\x06maxLat\x12\x17\n" +
+	"\amax_lon\x18\x04 \x01(\x01R\x06maxLon\"\xca\x02\n" +
+	"\x16CreateRegionJobRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x121\n" +
+	"\x06bboxes\x18\x02 \x03(\v2\x19.tasksmanager.BoundingBoxR\x06bboxes\x12\x1b\n" +
+	"\tmin_level\x18\x03 \x01(\x05R\bminLevel\x12\x1b\n" +
+	"\tmax_level\x18\x04 \x01(\x05R\bmaxLevel\x125\n" +
+	"\n" +
+	"task_types\x18\x05 \x03(\x0e2\x16.tasksmanager.TaskTypeR\ttaskTypes\x12\x14\n" +
+	"\x05epoch\x18\x06 \x01(\x05R\x05epoch\x12'\n" +
+	"\fimageryEpoch\x18\a \x01(\x05H\x00R\fimageryEpoch\x88\x01\x01\x12\x1c\n" +
+	"\adb_name\x18\b \x01(\tH\x01R\x06dbName\x88\x01\x01B\x0f\n" +
+	"\r_imageryEpochB\n" +
+	"\n" +
+	"\b_db_name\"\xd8\x01\n" +
+	"\x19CreateDiscoveryJobRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05epoch\x18\x02 \x01(\x05R\x05epoch\x12\x1b\n" +
+	"\tmax_level\x18\x03 \x01(\x05R\bmaxLevel\x12'\n" +
+	"\x0finclude_imagery\x18\x04 \x01(\bR\x0eincludeImagery\x12'\n" +
+	"\x0finclude_terrain\x18\x05 \x01(\bR\x0eincludeTerrain\x12\"\n" +
+	"\rroot_tile_key\x18\x06 \x01(\tR\vrootTileKey\"\x11\n" +
+	"\x0fListJobsRequest\"?\n" +
+	"\x10ListJobsResponse\x12+\n" +
+	"\x05items\x18\x01 \x03(\v2\x15.tasksmanager.JobInfoR\x05items\"\x13\n" +
+	"\x11CacheStatsRequest\"\xc1\x01\n" +
+	"\n" +
+	"CacheStats\x12\x12\n" +
+	"\x04hits\x18\x01 \x01(\x03R\x04hits\x12\x16\n" +
+	"\x06misses\x18\x02 \x01(\x03R\x06misses\x12\x1c\n" +
+	"\tcoalesced\x18\x03 \x01(\x03R\tcoalesced\x12\x1c\n" +
+	"\tevictions\x18\x04 \x01(\x03R\tevictions\x12\x18\n" +
+	"\aentries\x18\x05 \x01(\x03R\aentries\x12\x14\n" +
+	"\x05bytes\x18\x06 \x01(\x03R\x05bytes\x12\x1b\n" +
+	"\tmax_bytes\x18\a \x01(\x03R\bmaxBytes\"\x17\n" +
+	"\x15SchedulerStatsRequest\"\x15\n" +
+	"\x13ReloadConfigRequest\"[\n" +
+	"\x14ReloadConfigResponse\x12\x18\n" +
+	"\aapplied\x18\x01 \x03(\tR\aapplied\x12)\n" +
+	"\x10restart_required\x18\x02 \x03(\tR\x0frestartRequired\"\xbd\x01\n" +
+	"\x16SchedulerPriorityStats\x126\n" +
+	"\bpriority\x18\x01 \x01(\x0e2\x1a.tasksmanager.TaskPriorityR\bpriority\x12\x16\n" +
+	"\x06queued\x18\x02 \x01(\x03R\x06queued\x12\x1a\n" +
+	"\badmitted\x18\x03 \x01(\x03R\badmitted\x12\x1a\n" +
+	"\brejected\x18\x04 \x01(\x03R\brejected\x12\x1b\n" +
+	"\ttimed_out\x18\x05 \x01(\x03R\btimedOut\"\xc8\x01\n" +
+	"\x0eSchedulerStats\x12\x18\n" +
+	"\arunning\x18\x01 \x01(\x03R\arunning\x12\x1f\n" +
+	"\vmax_running\x18\x02 \x01(\x03R\n" +
+	"maxRunning\x12\x16\n" +
+	"\x06queued\x18\x03 \x01(\x03R\x06queued\x12\x1d\n" +
+	"\n" +
+	"max_queued\x18\x04 \x01(\x03R\tmaxQueued\x12D\n" +
+	"\n" +
+	"priorities\x18\x05 \x03(\v2$.tasksmanager.SchedulerPriorityStatsR\n" +
+	"priorities\"\x13\n" +
 	"\x11TUICConfigRequest\"\xe0\x01\n" +
 	"\x12TUICConfigResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
@@ -2021,7 +3804,7 @@ const file_TasksManager_proto_rawDesc = "" +
 	"\bpassword\x18\a \x01(\tR\bpassword\x12\x1e\n" +
 	"\n" +
 	"congestion\x18\b \x01(\tR\n" +
-	"congestion*\xb5\x02\n" +
+	"congestion*\xce\x02\n" +
 	"\bTaskType\x12\x15\n" +
 	"\x11TASK_TYPE_UNKNOWN\x10\x00\x121\n" +
 	"-TASK_TYPE_GOOGLE_EARTH_ROCKTREE_BULK_METADATA\x10\x01\x12-\n" +
@@ -2030,12 +3813,14 @@ const file_TasksManager_proto_rawDesc = "" +
 	"\x1eTASK_TYPE_GOOGLE_EARTH_IMAGERY\x10\x04\x12*\n" +
 	"&TASK_TYPE_GOOGLE_EARTH_IMAGERY_HISTORY\x10\x05\x12\x1d\n" +
 	"\x19TASK_TYPE_GOOGLE_EARTH_QP\x10\x06\x12\"\n" +
-	"\x1eTASK_TYPE_GOOGLE_EARTH_TERRAIN\x10\a*t\n" +
+	"\x1eTASK_TYPE_GOOGLE_EARTH_TERRAIN\x10\a\x12\x17\n" +
+	"\x13TASK_TYPE_HTTP_SITE\x10\b*\x90\x01\n" +
 	"\vTasksStatus\x12\x18\n" +
 	"\x14TASKS_STATUS_UNKNOWN\x10\x00\x12\x18\n" +
 	"\x14TASKS_STATUS_RUNNING\x10\x01\x12\x17\n" +
 	"\x13TASKS_STATUS_PAUSED\x10\x02\x12\x18\n" +
-	"\x14TASKS_STATUS_STOPPED\x10\x03*o\n" +
+	"\x14TASKS_STATUS_STOPPED\x10\x03\x12\x1a\n" +
+	"\x16TASKS_STATUS_COMPLETED\x10\x04*o\n" +
 	"\n" +
 	"TaskStatus\x12\x17\n" +
 	"\x13TASK_STATUS_PENDING\x10\x00\x12\x17\n" +
@@ -2056,13 +3841,58 @@ const file_TasksManager_proto_rawDesc = "" +
 	"\x12TASK_METHOD_DELETE\x10\x03\x12\x15\n" +
 	"\x11TASK_METHOD_PATCH\x10\x04\x12\x14\n" +
 	"\x10TASK_METHOD_HEAD\x10\x05\x12\x17\n" +
-	"\x13TASK_METHOD_OPTIONS\x10\x062\xa1\a\n" +
+	"\x13TASK_METHOD_OPTIONS\x10\x06*y\n" +
+	"\x12TaskResponseSource\x12!\n" +
+	"\x1dTASK_RESPONSE_SOURCE_UPSTREAM\x10\x00\x12 \n" +
+	"\x1cTASK_RESPONSE_SOURCE_STORAGE\x10\x01\x12\x1e\n" +
+	"\x1aTASK_RESPONSE_SOURCE_CACHE\x10\x02*`\n" +
+	"\x0eTaskDecodeMode\x12\x19\n" +
+	"\x15TASK_DECODE_MODE_NONE\x10\x00\x12\x18\n" +
+	"\x14TASK_DECODE_MODE_RAW\x10\x01\x12\x19\n" +
+	"\x15TASK_DECODE_MODE_JSON\x10\x02*~\n" +
+	"\fTaskPriority\x12\x1d\n" +
+	"\x19TASK_PRIORITY_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19TASK_PRIORITY_INTERACTIVE\x10\x01\x12\x18\n" +
+	"\x14TASK_PRIORITY_NORMAL\x10\x02\x12\x16\n" +
+	"\x12TASK_PRIORITY_BULK\x10\x03*\xf3\x04\n" +
+	"\rTaskErrorCode\x12\x18\n" +
+	"\x14TASK_ERROR_CODE_NONE\x10\x00\x12 \n" +
+	"\x1cTASK_ERROR_CODE_POOL_WARMING\x10\x01\x12(\n" +
+	"$TASK_ERROR_CODE_ALL_CONNECTIONS_BUSY\x10\x02\x12-\n" +
+	")TASK_ERROR_CODE_ALL_CONNECTIONS_UNHEALTHY\x10\x03\x12+\n" +
+	"'TASK_ERROR_CODE_NO_AVAILABLE_CONNECTION\x10\x04\x12&\n" +
+	"\"TASK_ERROR_CODE_UPSTREAM_FORBIDDEN\x10\x05\x12)\n" +
+	"%TASK_ERROR_CODE_UPSTREAM_SERVER_ERROR\x10\x06\x12#\n" +
+	"\x1fTASK_ERROR_CODE_TRANSPORT_RESET\x10\a\x12\x1b\n" +
+	"\x17TASK_ERROR_CODE_TIMEOUT\x10\b\x12\x1c\n" +
+	"\x18TASK_ERROR_CODE_INTERNAL\x10\t\x12!\n" +
+	"\x1dTASK_ERROR_CODE_DECODE_FAILED\x10\n" +
+	"\x12 \n" +
+	"\x1cTASK_ERROR_CODE_RATE_LIMITED\x10\v\x12\x1e\n" +
+	"\x1aTASK_ERROR_CODE_QUEUE_FULL\x10\f\x12!\n" +
+	"\x1dTASK_ERROR_CODE_QUEUE_TIMEOUT\x10\r\x12#\n" +
+	"\x1fTASK_ERROR_CODE_SERVER_DRAINING\x10\x0e\x12\x1c\n" +
+	"\x18TASK_ERROR_CODE_CANCELED\x10\x0f\x12\"\n" +
+	"\x1eTASK_ERROR_CODE_BODY_TOO_LARGE\x10\x102\xe6\x0e\n" +
 	"\fTasksManager\x12j\n" +
 	"\x15GetTaskClientInfoList\x12'.tasksmanager.TaskClientInfoListRequest\x1a(.tasksmanager.TaskClientInfoListResponse\x12v\n" +
 	"\x19GetGrpcServerNodeInfoList\x12+.tasksmanager.GrpcServerNodeInfoListRequest\x1a,.tasksmanager.GrpcServerNodeInfoListResponse\x12R\n" +
 	"\rGetTUICConfig\x12\x1f.tasksmanager.TUICConfigRequest\x1a .tasksmanager.TUICConfigResponse\x12C\n" +
 	"\n" +
-	"SubmitTask\x12\x19.tasksmanager.TaskRequest\x1a\x1a.tasksmanager.TaskResponse\x12T\n" +
+	"SubmitTask\x12\x19.tasksmanager.TaskRequest\x1a\x1a.tasksmanager.TaskResponse\x12Y\n" +
+	"\x10SubmitTaskStream\x12\x1f.tasksmanager.TaskStreamRequest\x1a .tasksmanager.TaskStreamResponse(\x010\x01\x12D\n" +
+	"\fDownloadTask\x12\x19.tasksmanager.TaskRequest\x1a\x17.tasksmanager.TaskChunk0\x01\x12J\n" +
+	"\rGetCacheStats\x12\x1f.tasksmanager.CacheStatsRequest\x1a\x18.tasksmanager.CacheStats\x12V\n" +
+	"\x11GetSchedulerStats\x12#.tasksmanager.SchedulerStatsRequest\x1a\x1c.tasksmanager.SchedulerStats\x12U\n" +
+	"\fReloadConfig\x12!.tasksmanager.ReloadConfigRequest\x1a\".tasksmanager.ReloadConfigResponse\x12B\n" +
+	"\tCreateJob\x12\x1e.tasksmanager.CreateJobRequest\x1a\x15.tasksmanager.JobInfo\x12N\n" +
+	"\x0fCreateRegionJob\x12$.tasksmanager.CreateRegionJobRequest\x1a\x15.tasksmanager.JobInfo\x12T\n" +
+	"\x12CreateDiscoveryJob\x12'.tasksmanager.CreateDiscoveryJobRequest\x1a\x15.tasksmanager.JobInfo\x129\n" +
+	"\x06GetJob\x12\x18.tasksmanager.JobRequest\x1a\x15.tasksmanager.JobInfo\x12I\n" +
+	"\bListJobs\x12\x1d.tasksmanager.ListJobsRequest\x1a\x1e.tasksmanager.ListJobsResponse\x12;\n" +
+	"\bPauseJob\x12\x18.tasksmanager.JobRequest\x1a\x15.tasksmanager.JobInfo\x12<\n" +
+	"\tResumeJob\x12\x18.tasksmanager.JobRequest\x1a\x15.tasksmanager.JobInfo\x12<\n" +
+	"\tCancelJob\x12\x18.tasksmanager.JobRequest\x1a\x15.tasksmanager.JobInfo\x12T\n" +
 	"\x0eRegisterClient\x12\x1c.tasksmanager.TaskClientInfo\x1a$.tasksmanager.RegisterClientResponse\x12V\n" +
 	"\x0fClientHeartbeat\x12\x1c.tasksmanager.TaskClientInfo\x1a%.tasksmanager.ClientHeartbeatResponse\x12]\n" +
 	"\fRegisterNode\x12%.tasksmanager.NodeRegistrationRequest\x1a&.tasksmanager.NodeRegistrationResponse\x12X\n" +
@@ -2082,78 +3912,146 @@ func file_TasksManager_proto_rawDescGZIP() []byte {
 	return file_TasksManager_proto_rawDescData
 }
 
-var file_TasksManager_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_TasksManager_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_TasksManager_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_TasksManager_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
 var file_TasksManager_proto_goTypes = []any{
 	(TaskType)(0),                          // 0: tasksmanager.TaskType
 	(TasksStatus)(0),                       // 1: tasksmanager.TasksStatus
 	(TaskStatus)(0),                        // 2: tasksmanager.TaskStatus
 	(ClientTaskStatus)(0),                  // 3: tasksmanager.ClientTaskStatus
 	(TaskMethod)(0),                        // 4: tasksmanager.TaskMethod
-	(*TaskClientInfo)(nil),                 // 5: tasksmanager.TaskClientInfo
-	(*TaskClientInfoListRequest)(nil),      // 6: tasksmanager.TaskClientInfoListRequest
-	(*TaskClientInfoListResponse)(nil),     // 7: tasksmanager.TaskClientInfoListResponse
-	(*RegisterClientResponse)(nil),         // 8: tasksmanager.RegisterClientResponse
-	(*ClientHeartbeatResponse)(nil),        // 9: tasksmanager.ClientHeartbeatResponse
-	(*GrpcServerNodeInfo)(nil),             // 10: tasksmanager.GrpcServerNodeInfo
-	(*GrpcServerNodeInfoListRequest)(nil),  // 11: tasksmanager.GrpcServerNodeInfoListRequest
-	(*GrpcServerNodeInfoListResponse)(nil), // 12: tasksmanager.GrpcServerNodeInfoListResponse
-	(*NodeRegistrationRequest)(nil),        // 13: tasksmanager.NodeRegistrationRequest
-	(*NodeRegistrationResponse)(nil),       // 14: tasksmanager.NodeRegistrationResponse
-	(*NodeHeartbeatRequest)(nil),           // 15: tasksmanager.NodeHeartbeatRequest
-	(*NodeHeartbeatResponse)(nil),          // 16: tasksmanager.NodeHeartbeatResponse
-	(*NodeMessage)(nil),                    // 17: tasksmanager.NodeMessage
-	(*NodeMessageRequest)(nil),             // 18: tasksmanager.NodeMessageRequest
-	(*NodeMessageResponse)(nil),            // 19: tasksmanager.NodeMessageResponse
-	(*SyncNodeListRequest)(nil),            // 20: tasksmanager.SyncNodeListRequest
-	(*SyncNodeListResponse)(nil),           // 21: tasksmanager.SyncNodeListResponse
-	(*TaskRequest)(nil),                    // 22: tasksmanager.TaskRequest
-	(*TaskResponse)(nil),                   // 23: tasksmanager.TaskResponse
-	(*TUICConfigRequest)(nil),              // 24: tasksmanager.TUICConfigRequest
-	(*TUICConfigResponse)(nil),             // 25: tasksmanager.TUICConfigResponse
+	(TaskResponseSource)(0),                // 5: tasksmanager.TaskResponseSource
+	(TaskDecodeMode)(0),                    // 6: tasksmanager.TaskDecodeMode
+	(TaskPriority)(0),                      // 7: tasksmanager.TaskPriority
+	(TaskErrorCode)(0),                     // 8: tasksmanager.TaskErrorCode
+	(*TaskClientInfo)(nil),                 // 9: tasksmanager.TaskClientInfo
+	(*TaskClientInfoListRequest)(nil),      // 10: tasksmanager.TaskClientInfoListRequest
+	(*TaskClientInfoListResponse)(nil),     // 11: tasksmanager.TaskClientInfoListResponse
+	(*RegisterClientResponse)(nil),         // 12: tasksmanager.RegisterClientResponse
+	(*ClientHeartbeatResponse)(nil),        // 13: tasksmanager.ClientHeartbeatResponse
+	(*GrpcServerNodeInfo)(nil),             // 14: tasksmanager.GrpcServerNodeInfo
+	(*GrpcServerNodeInfoListRequest)(nil),  // 15: tasksmanager.GrpcServerNodeInfoListRequest
+	(*GrpcServerNodeInfoListResponse)(nil), // 16: tasksmanager.GrpcServerNodeInfoListResponse
+	(*NodeRegistrationRequest)(nil),        // 17: tasksmanager.NodeRegistrationRequest
+	(*NodeRegistrationResponse)(nil),       // 18: tasksmanager.NodeRegistrationResponse
+	(*NodeHeartbeatRequest)(nil),           // 19: tasksmanager.NodeHeartbeatRequest
+	(*NodeHeartbeatResponse)(nil),          // 20: tasksmanager.NodeHeartbeatResponse
+	(*NodeMessage)(nil),                    // 21: tasksmanager.NodeMessage
+	(*NodeMessageRequest)(nil),             // 22: tasksmanager.NodeMessageRequest
+	(*NodeMessageResponse)(nil),            // 23: tasksmanager.NodeMessageResponse
+	(*SyncNodeListRequest)(nil),            // 24: tasksmanager.SyncNodeListRequest
+	(*SyncNodeListResponse)(nil),           // 25: tasksmanager.SyncNodeListResponse
+	(*TaskRequest)(nil),                    // 26: tasksmanager.TaskRequest
+	(*TaskResponse)(nil),                   // 27: tasksmanager.TaskResponse
+	(*TaskHeader)(nil),                     // 28: tasksmanager.TaskHeader
+	(*TaskChunk)(nil),                      // 29: tasksmanager.TaskChunk
+	(*TaskStreamRequest)(nil),              // 30: tasksmanager.TaskStreamRequest
+	(*TaskStreamResponse)(nil),             // 31: tasksmanager.TaskStreamResponse
+	(*CreateJobRequest)(nil),               // 32: tasksmanager.CreateJobRequest
+	(*JobRequest)(nil),                     // 33: tasksmanager.JobRequest
+	(*JobInfo)(nil),                        // 34: tasksmanager.JobInfo
+	(*BoundingBox)(nil),                    // 35: tasksmanager.BoundingBox
+	(*CreateRegionJobRequest)(nil),         // 36: tasksmanager.CreateRegionJobRequest
+	(*CreateDiscoveryJobRequest)(nil),      // 37: tasksmanager.CreateDiscoveryJobRequest
+	(*ListJobsRequest)(nil),                // 38: tasksmanager.ListJobsRequest
+	(*ListJobsResponse)(nil),               // 39: tasksmanager.ListJobsResponse
+	(*CacheStatsRequest)(nil),              // 40: tasksmanager.CacheStatsRequest
+	(*CacheStats)(nil),                     // 41: tasksmanager.CacheStats
+	(*SchedulerStatsRequest)(nil),          // 42: tasksmanager.SchedulerStatsRequest
+	(*ReloadConfigRequest)(nil),            // 43: tasksmanager.ReloadConfigRequest
+	(*ReloadConfigResponse)(nil),           // 44: tasksmanager.ReloadConfigResponse
+	(*SchedulerPriorityStats)(nil),         // 45: tasksmanager.SchedulerPriorityStats
+	(*SchedulerStats)(nil),                 // 46: tasksmanager.SchedulerStats
+	(*TUICConfigRequest)(nil),              // 47: tasksmanager.TUICConfigRequest
+	(*TUICConfigResponse)(nil),             // 48: tasksmanager.TUICConfigResponse
+	nil,                                    // 49: tasksmanager.TaskRequest.PathParamsEntry
+	nil,                                    // 50: tasksmanager.TaskRequest.RequestHeadersEntry
 }
 var file_TasksManager_proto_depIdxs = []int32{
 	3,  // 0: tasksmanager.TaskClientInfo.client_task_status:type_name -> tasksmanager.ClientTaskStatus
-	5,  // 1: tasksmanager.TaskClientInfoListResponse.items:type_name -> tasksmanager.TaskClientInfo
-	10, // 2: tasksmanager.RegisterClientResponse.server_nodes:type_name -> tasksmanager.GrpcServerNodeInfo
-	10, // 3: tasksmanager.ClientHeartbeatResponse.new_server_nodes:type_name -> tasksmanager.GrpcServerNodeInfo
-	10, // 4: tasksmanager.GrpcServerNodeInfoListResponse.items:type_name -> tasksmanager.GrpcServerNodeInfo
-	10, // 5: tasksmanager.NodeRegistrationRequest.node_info:type_name -> tasksmanager.GrpcServerNodeInfo
-	10, // 6: tasksmanager.NodeRegistrationResponse.known_nodes:type_name -> tasksmanager.GrpcServerNodeInfo
-	10, // 7: tasksmanager.NodeHeartbeatRequest.node_info:type_name -> tasksmanager.GrpcServerNodeInfo
-	10, // 8: tasksmanager.NodeHeartbeatResponse.updated_nodes:type_name -> tasksmanager.GrpcServerNodeInfo
-	17, // 9: tasksmanager.NodeMessageRequest.message:type_name -> tasksmanager.NodeMessage
-	10, // 10: tasksmanager.SyncNodeListResponse.nodes_to_add:type_name -> tasksmanager.GrpcServerNodeInfo
-	10, // 11: tasksmanager.SyncNodeListResponse.nodes_to_update:type_name -> tasksmanager.GrpcServerNodeInfo
+	9,  // 1: tasksmanager.TaskClientInfoListResponse.items:type_name -> tasksmanager.TaskClientInfo
+	14, // 2: tasksmanager.RegisterClientResponse.server_nodes:type_name -> tasksmanager.GrpcServerNodeInfo
+	14, // 3: tasksmanager.ClientHeartbeatResponse.new_server_nodes:type_name -> tasksmanager.GrpcServerNodeInfo
+	14, // 4: tasksmanager.GrpcServerNodeInfoListResponse.items:type_name -> tasksmanager.GrpcServerNodeInfo
+	14, // 5: tasksmanager.NodeRegistrationRequest.node_info:type_name -> tasksmanager.GrpcServerNodeInfo
+	14, // 6: tasksmanager.NodeRegistrationResponse.known_nodes:type_name -> tasksmanager.GrpcServerNodeInfo
+	14, // 7: tasksmanager.NodeHeartbeatRequest.node_info:type_name -> tasksmanager.GrpcServerNodeInfo
+	14, // 8: tasksmanager.NodeHeartbeatResponse.updated_nodes:type_name -> tasksmanager.GrpcServerNodeInfo
+	21, // 9: tasksmanager.NodeMessageRequest.message:type_name -> tasksmanager.NodeMessage
+	14, // 10: tasksmanager.SyncNodeListResponse.nodes_to_add:type_name -> tasksmanager.GrpcServerNodeInfo
+	14, // 11: tasksmanager.SyncNodeListResponse.nodes_to_update:type_name -> tasksmanager.GrpcServerNodeInfo
 	0,  // 12: tasksmanager.TaskRequest.task_type:type_name -> tasksmanager.TaskType
 	4,  // 13: tasksmanager.TaskRequest.task_method:type_name -> tasksmanager.TaskMethod
 	2,  // 14: tasksmanager.TaskRequest.task_status:type_name -> tasksmanager.TaskStatus
-	0,  // 15: tasksmanager.TaskResponse.task_type:type_name -> tasksmanager.TaskType
-	6,  // 16: tasksmanager.TasksManager.GetTaskClientInfoList:input_type -> tasksmanager.TaskClientInfoListRequest
-	11, // 17: tasksmanager.TasksManager.GetGrpcServerNodeInfoList:input_type -> tasksmanager.GrpcServerNodeInfoListRequest
-	24, // 18: tasksmanager.TasksManager.GetTUICConfig:input_type -> tasksmanager.TUICConfigRequest
-	22, // 19: tasksmanager.TasksManager.SubmitTask:input_type -> tasksmanager.TaskRequest
-	5,  // 20: tasksmanager.TasksManager.RegisterClient:input_type -> tasksmanager.TaskClientInfo
-	5,  // 21: tasksmanager.TasksManager.ClientHeartbeat:input_type -> tasksmanager.TaskClientInfo
-	13, // 22: tasksmanager.TasksManager.RegisterNode:input_type -> tasksmanager.NodeRegistrationRequest
-	15, // 23: tasksmanager.TasksManager.NodeHeartbeat:input_type -> tasksmanager.NodeHeartbeatRequest
-	18, // 24: tasksmanager.TasksManager.SendNodeMessage:input_type -> tasksmanager.NodeMessageRequest
-	20, // 25: tasksmanager.TasksManager.SyncNodeList:input_type -> tasksmanager.SyncNodeListRequest
-	7,  // 26: tasksmanager.TasksManager.GetTaskClientInfoList:output_type -> tasksmanager.TaskClientInfoListResponse
-	12, // 27: tasksmanager.TasksManager.GetGrpcServerNodeInfoList:output_type -> tasksmanager.GrpcServerNodeInfoListResponse
-	25, // 28: tasksmanager.TasksManager.GetTUICConfig:output_type -> tasksmanager.TUICConfigResponse
-	23, // 29: tasksmanager.TasksManager.SubmitTask:output_type -> tasksmanager.TaskResponse
-	8,  // 30: tasksmanager.TasksManager.RegisterClient:output_type -> tasksmanager.RegisterClientResponse
-	9,  // 31: tasksmanager.TasksManager.ClientHeartbeat:output_type -> tasksmanager.ClientHeartbeatResponse
-	14, // 32: tasksmanager.TasksManager.RegisterNode:output_type -> tasksmanager.NodeRegistrationResponse
-	16, // 33: tasksmanager.TasksManager.NodeHeartbeat:output_type -> tasksmanager.NodeHeartbeatResponse
-	19, // 34: tasksmanager.TasksManager.SendNodeMessage:output_type -> tasksmanager.NodeMessageResponse
-	21, // 35: tasksmanager.TasksManager.SyncNodeList:output_type -> tasksmanager.SyncNodeListResponse
-	26, // [26:36] is the sub-list for method output_type
-	16, // [16:26] is the sub-list for method input_type
-	16, // [16:16] is the sub-list for extension type_name
-	16, // [16:16] is the sub-list for extension extendee
-	0,  // [0:16] is the sub-list for field type_name
+	6,  // 15: tasksmanager.TaskRequest.decode:type_name -> tasksmanager.TaskDecodeMode
+	7,  // 16: tasksmanager.TaskRequest.priority:type_name -> tasksmanager.TaskPriority
+	49, // 17: tasksmanager.TaskRequest.path_params:type_name -> tasksmanager.TaskRequest.PathParamsEntry
+	50, // 18: tasksmanager.TaskRequest.request_headers:type_name -> tasksmanager.TaskRequest.RequestHeadersEntry
+	0,  // 19: tasksmanager.TaskResponse.task_type:type_name -> tasksmanager.TaskType
+	5,  // 20: tasksmanager.TaskResponse.response_source:type_name -> tasksmanager.TaskResponseSource
+	8,  // 21: tasksmanager.TaskResponse.error_code:type_name -> tasksmanager.TaskErrorCode
+	28, // 22: tasksmanager.TaskResponse.response_headers:type_name -> tasksmanager.TaskHeader
+	27, // 23: tasksmanager.TaskChunk.response:type_name -> tasksmanager.TaskResponse
+	26, // 24: tasksmanager.TaskStreamRequest.task:type_name -> tasksmanager.TaskRequest
+	27, // 25: tasksmanager.TaskStreamResponse.task:type_name -> tasksmanager.TaskResponse
+	26, // 26: tasksmanager.CreateJobRequest.tasks:type_name -> tasksmanager.TaskRequest
+	1,  // 27: tasksmanager.JobInfo.status:type_name -> tasksmanager.TasksStatus
+	35, // 28: tasksmanager.CreateRegionJobRequest.bboxes:type_name -> tasksmanager.BoundingBox
+	0,  // 29: tasksmanager.CreateRegionJobRequest.task_types:type_name -> tasksmanager.TaskType
+	34, // 30: tasksmanager.ListJobsResponse.items:type_name -> tasksmanager.JobInfo
+	7,  // 31: tasksmanager.SchedulerPriorityStats.priority:type_name -> tasksmanager.TaskPriority
+	45, // 32: tasksmanager.SchedulerStats.priorities:type_name -> tasksmanager.SchedulerPriorityStats
+	10, // 33: tasksmanager.TasksManager.GetTaskClientInfoList:input_type -> tasksmanager.TaskClientInfoListRequest
+	15, // 34: tasksmanager.TasksManager.GetGrpcServerNodeInfoList:input_type -> tasksmanager.GrpcServerNodeInfoListRequest
+	47, // 35: tasksmanager.TasksManager.GetTUICConfig:input_type -> tasksmanager.TUICConfigRequest
+	26, // 36: tasksmanager.TasksManager.SubmitTask:input_type -> tasksmanager.TaskRequest
+	30, // 37: tasksmanager.TasksManager.SubmitTaskStream:input_type -> tasksmanager.TaskStreamRequest
+	26, // 38: tasksmanager.TasksManager.DownloadTask:input_type -> tasksmanager.TaskRequest
+	40, // 39: tasksmanager.TasksManager.GetCacheStats:input_type -> tasksmanager.CacheStatsRequest
+	42, // 40: tasksmanager.TasksManager.GetSchedulerStats:input_type -> tasksmanager.SchedulerStatsRequest
+	43, // 41: tasksmanager.TasksManager.ReloadConfig:input_type -> tasksmanager.ReloadConfigRequest
+	32, // 42: tasksmanager.TasksManager.CreateJob:input_type -> tasksmanager.CreateJobRequest
+	36, // 43: tasksmanager.TasksManager.CreateRegionJob:input_type -> tasksmanager.CreateRegionJobRequest
+	37, // 44: tasksmanager.TasksManager.CreateDiscoveryJob:input_type -> tasksmanager.CreateDiscoveryJobRequest
+	33, // 45: tasksmanager.TasksManager.GetJob:input_type -> tasksmanager.JobRequest
+	38, // 46: tasksmanager.TasksManager.ListJobs:input_type -> tasksmanager.ListJobsRequest
+	33, // 47: tasksmanager.TasksManager.PauseJob:input_type -> tasksmanager.JobRequest
+	33, // 48: tasksmanager.TasksManager.ResumeJob:input_type -> tasksmanager.JobRequest
+	33, // 49: tasksmanager.TasksManager.CancelJob:input_type -> tasksmanager.JobRequest
+	9,  // 50: tasksmanager.TasksManager.RegisterClient:input_type -> tasksmanager.TaskClientInfo
+	9,  // 51: tasksmanager.TasksManager.ClientHeartbeat:input_type -> tasksmanager.TaskClientInfo
+	17, // 52: tasksmanager.TasksManager.RegisterNode:input_type -> tasksmanager.NodeRegistrationRequest
+	19, // 53: tasksmanager.TasksManager.NodeHeartbeat:input_type -> tasksmanager.NodeHeartbeatRequest
+	22, // 54: tasksmanager.TasksManager.SendNodeMessage:input_type -> tasksmanager.NodeMessageRequest
+	24, // 55: tasksmanager.TasksManager.SyncNodeList:input_type -> tasksmanager.SyncNodeListRequest
+	11, // 56: tasksmanager.TasksManager.GetTaskClientInfoList:output_type -> tasksmanager.TaskClientInfoListResponse
+	16, // 57: tasksmanager.TasksManager.GetGrpcServerNodeInfoList:output_type -> tasksmanager.GrpcServerNodeInfoListResponse
+	48, // 58: tasksmanager.TasksManager.GetTUICConfig:output_type -> tasksmanager.TUICConfigResponse
+	27, // 59: tasksmanager.TasksManager.SubmitTask:output_type -> tasksmanager.TaskResponse
+	31, // 60: tasksmanager.TasksManager.SubmitTaskStream:output_type -> tasksmanager.TaskStreamResponse
+	29, // 61: tasksmanager.TasksManager.DownloadTask:output_type -> tasksmanager.TaskChunk
+	41, // 62: tasksmanager.TasksManager.GetCacheStats:output_type -> tasksmanager.CacheStats
+	46, // 63: tasksmanager.TasksManager.GetSchedulerStats:output_type -> tasksmanager.SchedulerStats
+	44, // 64: tasksmanager.TasksManager.ReloadConfig:output_type -> tasksmanager.ReloadConfigResponse
+	34, // 65: tasksmanager.TasksManager.CreateJob:output_type -> tasksmanager.JobInfo
+	34, // 66: tasksmanager.TasksManager.CreateRegionJob:output_type -> tasksmanager.JobInfo
+	34, // 67: tasksmanager.TasksManager.CreateDiscoveryJob:output_type -> tasksmanager.JobInfo
+	34, // 68: tasksmanager.TasksManager.GetJob:output_type -> tasksmanager.JobInfo
+	39, // 69: tasksmanager.TasksManager.ListJobs:output_type -> tasksmanager.ListJobsResponse
+	34, // 70: tasksmanager.TasksManager.PauseJob:output_type -> tasksmanager.JobInfo
+	34, // 71: tasksmanager.TasksManager.ResumeJob:output_type -> tasksmanager.JobInfo
+	34, // 72: tasksmanager.TasksManager.CancelJob:output_type -> tasksmanager.JobInfo
+	12, // 73: tasksmanager.TasksManager.RegisterClient:output_type -> tasksmanager.RegisterClientResponse
+	13, // 74: tasksmanager.TasksManager.ClientHeartbeat:output_type -> tasksmanager.ClientHeartbeatResponse
+	18, // 75: tasksmanager.TasksManager.RegisterNode:output_type -> tasksmanager.NodeRegistrationResponse
+	20, // 76: tasksmanager.TasksManager.NodeHeartbeat:output_type -> tasksmanager.NodeHeartbeatResponse
+	23, // 77: tasksmanager.TasksManager.SendNodeMessage:output_type -> tasksmanager.NodeMessageResponse
+	25, // 78: tasksmanager.TasksManager.SyncNodeList:output_type -> tasksmanager.SyncNodeListResponse
+	56, // [56:79] is the sub-list for method output_type
+	33, // [33:56] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_TasksManager_proto_init() }
@@ -2166,13 +4064,14 @@ func file_TasksManager_proto_init() {
 	file_TasksManager_proto_msgTypes[12].OneofWrappers = []any{}
 	file_TasksManager_proto_msgTypes[17].OneofWrappers = []any{}
 	file_TasksManager_proto_msgTypes[18].OneofWrappers = []any{}
+	file_TasksManager_proto_msgTypes[27].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_TasksManager_proto_rawDesc), len(file_TasksManager_proto_rawDesc)),
-			NumEnums:      5,
-			NumMessages:   21,
+			NumEnums:      9,
+			NumMessages:   42,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,6 +26,19 @@ const (
 	TasksManager_GetGrpcServerNodeInfoList_FullMethodName = "/tasksmanager.TasksManager/GetGrpcServerNodeInfoList"
 	TasksManager_GetTUICConfig_FullMethodName             = "/tasksmanager.TasksManager/GetTUICConfig"
 	TasksManager_SubmitTask_FullMethodName                = "/tasksmanager.TasksManager/SubmitTask"
+	TasksManager_SubmitTaskStream_FullMethodName          = "/tasksmanager.TasksManager/SubmitTaskStream"
+	TasksManager_DownloadTask_FullMethodName              = "/tasksmanager.TasksManager/DownloadTask"
+	TasksManager_GetCacheStats_FullMethodName             = "/tasksmanager.TasksManager/GetCacheStats"
+	TasksManager_GetSchedulerStats_FullMethodName         = "/tasksmanager.TasksManager/GetSchedulerStats"
+	TasksManager_ReloadConfig_FullMethodName              = "/tasksmanager.TasksManager/ReloadConfig"
+	TasksManager_CreateJob_FullMethodName                 = "/tasksmanager.TasksManager/CreateJob"
+	TasksManager_CreateRegionJob_FullMethodName           = "/tasksmanager.TasksManager/CreateRegionJob"
+	TasksManager_CreateDiscoveryJob_FullMethodName        = "/tasksmanager.TasksManager/CreateDiscoveryJob"
+	TasksManager_GetJob_FullMethodName                    = "/tasksmanager.TasksManager/GetJob"
+	TasksManager_ListJobs_FullMethodName                  = "/tasksmanager.TasksManager/ListJobs"
+	TasksManager_PauseJob_FullMethodName                  = "/tasksmanager.TasksManager/PauseJob"
+	TasksManager_ResumeJob_FullMethodName                 = "/tasksmanager.TasksManager/ResumeJob"
+	TasksManager_CancelJob_FullMethodName                 = "/tasksmanager.TasksManager/CancelJob"
 	TasksManager_RegisterClient_FullMethodName            = "/tasksmanager.TasksManager/RegisterClient"
 	TasksManager_ClientHeartbeat_FullMethodName           = "/tasksmanager.TasksManager/ClientHeartbeat"
 	TasksManager_RegisterNode_FullMethodName              = "/tasksmanager.TasksManager/RegisterNode"
@@ -49,15 +62,59 @@ type TasksManagerClient interface {
 	// GetGrpcServerNodeInfoList 获取 gRPC 服务器节点列表
 	// 返回所有 gRPC 服务器节点的信息列表
 	// 包括节点的基本信息、系统信息、运行状态等
+	// 启用认证时只允许 node 与 admin 身份调用
 	GetGrpcServerNodeInfoList(ctx context.Context, in *GrpcServerNodeInfoListRequest, opts ...grpc.CallOption) (*GrpcServerNodeInfoListResponse, error)
 	// GetTUICConfig 获取 TUIC 服务器配置信息
 	// 返回 TUIC 服务器的 UUID 和密码，用于客户端自动连接
 	// 客户端可以通过此接口获取服务器自动生成的 UUID 和密码，实现无缝连接
+	// 启用认证时只允许 admin 身份调用
 	GetTUICConfig(ctx context.Context, in *TUICConfigRequest, opts ...grpc.CallOption) (*TUICConfigResponse, error)
 	// SubmitTask 提交任务请求
 	// 向指定的任务客户端提交一个新的 HTTP 任务请求
 	// 任务将在客户端执行，并返回执行结果（包括响应状态码和响应体）
+	// 执行失败时返回与 error_code 对应的 gRPC 状态码，状态详情中携带包含 error_code 的 TaskResponse
+	// 上游并发已满时按 priority 排队，等待队列已满时返回 RESOURCE_EXHAUSTED（QUEUE_FULL）
 	SubmitTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*TaskResponse, error)
+	// SubmitTaskStream 流式提交任务请求（双向流）
+	// 客户端持续推送带关联 ID 的任务，服务器在任务完成时乱序推送响应
+	// 服务器通过 credits 进行流控，限制单个流同时执行的任务数，避免压垮热连接池
+	SubmitTaskStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TaskStreamRequest, TaskStreamResponse], error)
+	// DownloadTask 执行任务并以分块流式返回响应体（服务端流）
+	// 适用于大型 RockTree 纹理、批量数据等响应，避免超过 gRPC 消息大小上限与一次性读入内存
+	// 不支持响应体解码；分块之后发送的错误（如响应体超过 max_body_bytes）表示下载已中止，已收到的分块应丢弃
+	DownloadTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TaskChunk], error)
+	// GetCacheStats 获取热点瓦片缓存统计
+	// 返回缓存命中/未命中次数、相同任务合并次数以及当前缓存占用
+	GetCacheStats(ctx context.Context, in *CacheStatsRequest, opts ...grpc.CallOption) (*CacheStats, error)
+	// GetSchedulerStats 获取任务调度统计
+	// 返回当前执行中与排队中的任务数，以及各优先级的执行、拒绝、超时次数
+	GetSchedulerStats(ctx context.Context, in *SchedulerStatsRequest, opts ...grpc.CallOption) (*SchedulerStats, error)
+	// ReloadConfig 重新加载配置文件（与 SIGHUP 信号相同）
+	// 热连接池限制、超时、路径模板、日志级别与监控域名立即生效，其余修改返回在 restart_required 中
+	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
+	// CreateJob 创建作业
+	// 作业创建后立即在服务器后台开始执行，状态持久化到磁盘，服务器重启后继续执行
+	CreateJob(ctx context.Context, in *CreateJobRequest, opts ...grpc.CallOption) (*JobInfo, error)
+	// CreateRegionJob 创建区域爬取作业
+	// 按边界框、层级范围和数据类型在服务器后台惰性枚举并执行瓦片任务，进度通过 GetJob 查询
+	CreateRegionJob(ctx context.Context, in *CreateRegionJobRequest, opts ...grpc.CallOption) (*JobInfo, error)
+	// CreateDiscoveryJob 创建 Q2 发现作业
+	// 沿 Q2 数据包中的引用递归发现实际存在的影像/地形瓦片，任务总数随发现过程增长
+	CreateDiscoveryJob(ctx context.Context, in *CreateDiscoveryJobRequest, opts ...grpc.CallOption) (*JobInfo, error)
+	// GetJob 获取作业信息
+	// 返回作业整体状态和各状态任务计数
+	GetJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobInfo, error)
+	// ListJobs 获取作业列表
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	// PauseJob 暂停作业
+	// 不再启动新任务，正在执行的任务完成后作业进入已暂停状态
+	PauseJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobInfo, error)
+	// ResumeJob 恢复作业
+	// 恢复已暂停的作业
+	ResumeJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobInfo, error)
+	// CancelJob 取消作业
+	// 停止作业，未执行的任务不再执行，已停止的作业不能恢复
+	CancelJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobInfo, error)
 	// RegisterClient 客户端注册
 	// 客户端连接到服务器时调用，注册自己的信息
 	// 服务器会返回所有已知的服务器节点列表，帮助客户端连接到所有服务器
@@ -136,6 +193,148 @@ func (c *tasksManagerClient) SubmitTask(ctx context.Context, in *TaskRequest, op
 	return out, nil
 }
 
+func (c *tasksManagerClient) SubmitTaskStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TaskStreamRequest, TaskStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TasksManager_ServiceDesc.Streams[0], TasksManager_SubmitTaskStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TaskStreamRequest, TaskStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TasksManager_SubmitTaskStreamClient = grpc.BidiStreamingClient[TaskStreamRequest, TaskStreamResponse]
+
+func (c *tasksManagerClient) DownloadTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TaskChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TasksManager_ServiceDesc.Streams[1], TasksManager_DownloadTask_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TaskRequest, TaskChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TasksManager_DownloadTaskClient = grpc.ServerStreamingClient[TaskChunk]
+
+func (c *tasksManagerClient) GetCacheStats(ctx context.Context, in *CacheStatsRequest, opts ...grpc.CallOption) (*CacheStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CacheStats)
+	err := c.cc.Invoke(ctx, TasksManager_GetCacheStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) GetSchedulerStats(ctx context.Context, in *SchedulerStatsRequest, opts ...grpc.CallOption) (*SchedulerStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SchedulerStats)
+	err := c.cc.Invoke(ctx, TasksManager_GetSchedulerStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReloadConfigResponse)
+	err := c.cc.Invoke(ctx, TasksManager_ReloadConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) CreateJob(ctx context.Context, in *CreateJobRequest, opts ...grpc.CallOption) (*JobInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobInfo)
+	err := c.cc.Invoke(ctx, TasksManager_CreateJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) CreateRegionJob(ctx context.Context, in *CreateRegionJobRequest, opts ...grpc.CallOption) (*JobInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobInfo)
+	err := c.cc.Invoke(ctx, TasksManager_CreateRegionJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) CreateDiscoveryJob(ctx context.Context, in *CreateDiscoveryJobRequest, opts ...grpc.CallOption) (*JobInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobInfo)
+	err := c.cc.Invoke(ctx, TasksManager_CreateDiscoveryJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) GetJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobInfo)
+	err := c.cc.Invoke(ctx, TasksManager_GetJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, TasksManager_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) PauseJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobInfo)
+	err := c.cc.Invoke(ctx, TasksManager_PauseJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) ResumeJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobInfo)
+	err := c.cc.Invoke(ctx, TasksManager_ResumeJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tasksManagerClient) CancelJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobInfo)
+	err := c.cc.Invoke(ctx, TasksManager_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *tasksManagerClient) RegisterClient(ctx context.Context, in *TaskClientInfo, opts ...grpc.CallOption) (*RegisterClientResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RegisterClientResponse)
@@ -211,15 +410,59 @@ type TasksManagerServer interface {
 	// GetGrpcServerNodeInfoList 获取 gRPC 服务器节点列表
 	// 返回所有 gRPC 服务器节点的信息列表
 	// 包括节点的基本信息、系统信息、运行状态等
+	// 启用认证时只允许 node 与 admin 身份调用
 	GetGrpcServerNodeInfoList(context.Context, *GrpcServerNodeInfoListRequest) (*GrpcServerNodeInfoListResponse, error)
 	// GetTUICConfig 获取 TUIC 服务器配置信息
 	// 返回 TUIC 服务器的 UUID 和密码，用于客户端自动连接
 	// 客户端可以通过此接口获取服务器自动生成的 UUID 和密码，实现无缝连接
+	// 启用认证时只允许 admin 身份调用
 	GetTUICConfig(context.Context, *TUICConfigRequest) (*TUICConfigResponse, error)
 	// SubmitTask 提交任务请求
 	// 向指定的任务客户端提交一个新的 HTTP 任务请求
 	// 任务将在客户端执行，并返回执行结果（包括响应状态码和响应体）
+	// 执行失败时返回与 error_code 对应的 gRPC 状态码，状态详情中携带包含 error_code 的 TaskResponse
+	// 上游并发已满时按 priority 排队，等待队列已满时返回 RESOURCE_EXHAUSTED（QUEUE_FULL）
 	SubmitTask(context.Context, *TaskRequest) (*TaskResponse, error)
+	// SubmitTaskStream 流式提交任务请求（双向流）
+	// 客户端持续推送带关联 ID 的任务，服务器在任务完成时乱序推送响应
+	// 服务器通过 credits 进行流控，限制单个流同时执行的任务数，避免压垮热连接池
+	SubmitTaskStream(grpc.BidiStreamingServer[TaskStreamRequest, TaskStreamResponse]) error
+	// DownloadTask 执行任务并以分块流式返回响应体（服务端流）
+	// 适用于大型 RockTree 纹理、批量数据等响应，避免超过 gRPC 消息大小上限与一次性读入内存
+	// 不支持响应体解码；分块之后发送的错误（如响应体超过 max_body_bytes）表示下载已中止，已收到的分块应丢弃
+	DownloadTask(*TaskRequest, grpc.ServerStreamingServer[TaskChunk]) error
+	// GetCacheStats 获取热点瓦片缓存统计
+	// 返回缓存命中/未命中次数、相同任务合并次数以及当前缓存占用
+	GetCacheStats(context.Context, *CacheStatsRequest) (*CacheStats, error)
+	// GetSchedulerStats 获取任务调度统计
+	// 返回当前执行中与排队中的任务数，以及各优先级的执行、拒绝、超时次数
+	GetSchedulerStats(context.Context, *SchedulerStatsRequest) (*SchedulerStats, error)
+	// ReloadConfig 重新加载配置文件（与 SIGHUP 信号相同）
+	// 热连接池限制、超时、路径模板、日志级别与监控域名立即生效，其余修改返回在 restart_required 中
+	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
+	// CreateJob 创建作业
+	// 作业创建后立即在服务器后台开始执行，状态持久化到磁盘，服务器重启后继续执行
+	CreateJob(context.Context, *CreateJobRequest) (*JobInfo, error)
+	// CreateRegionJob 创建区域爬取作业
+	// 按边界框、层级范围和数据类型在服务器后台惰性枚举并执行瓦片任务，进度通过 GetJob 查询
+	CreateRegionJob(context.Context, *CreateRegionJobRequest) (*JobInfo, error)
+	// CreateDiscoveryJob 创建 Q2 发现作业
+	// 沿 Q2 数据包中的引用递归发现实际存在的影像/地形瓦片，任务总数随发现过程增长
+	CreateDiscoveryJob(context.Context, *CreateDiscoveryJobRequest) (*JobInfo, error)
+	// GetJob 获取作业信息
+	// 返回作业整体状态和各状态任务计数
+	GetJob(context.Context, *JobRequest) (*JobInfo, error)
+	// ListJobs 获取作业列表
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	// PauseJob 暂停作业
+	// 不再启动新任务，正在执行的任务完成后作业进入已暂停状态
+	PauseJob(context.Context, *JobRequest) (*JobInfo, error)
+	// ResumeJob 恢复作业
+	// 恢复已暂停的作业
+	ResumeJob(context.Context, *JobRequest) (*JobInfo, error)
+	// CancelJob 取消作业
+	// 停止作业，未执行的任务不再执行，已停止的作业不能恢复
+	CancelJob(context.Context, *JobRequest) (*JobInfo, error)
 	// RegisterClient 客户端注册
 	// 客户端连接到服务器时调用，注册自己的信息
 	// 服务器会返回所有已知的服务器节点列表，帮助客户端连接到所有服务器
@@ -270,6 +513,45 @@ func (UnimplementedTasksManagerServer) GetTUICConfig(context.Context, *TUICConfi
 func (UnimplementedTasksManagerServer) SubmitTask(context.Context, *TaskRequest) (*TaskResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SubmitTask not implemented")
 }
+func (UnimplementedTasksManagerServer) SubmitTaskStream(grpc.BidiStreamingServer[TaskStreamRequest, TaskStreamResponse]) error {
+	return status.Error(codes.Unimplemented, "method SubmitTaskStream not implemented")
+}
+func (UnimplementedTasksManagerServer) DownloadTask(*TaskRequest, grpc.ServerStreamingServer[TaskChunk]) error {
+	return status.Error(codes.Unimplemented, "method DownloadTask not implemented")
+}
+func (UnimplementedTasksManagerServer) GetCacheStats(context.Context, *CacheStatsRequest) (*CacheStats, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCacheStats not implemented")
+}
+func (UnimplementedTasksManagerServer) GetSchedulerStats(context.Context, *SchedulerStatsRequest) (*SchedulerStats, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSchedulerStats not implemented")
+}
+func (UnimplementedTasksManagerServer) ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedTasksManagerServer) CreateJob(context.Context, *CreateJobRequest) (*JobInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateJob not implemented")
+}
+func (UnimplementedTasksManagerServer) CreateRegionJob(context.Context, *CreateRegionJobRequest) (*JobInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateRegionJob not implemented")
+}
+func (UnimplementedTasksManagerServer) CreateDiscoveryJob(context.Context, *CreateDiscoveryJobRequest) (*JobInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateDiscoveryJob not implemented")
+}
+func (UnimplementedTasksManagerServer) GetJob(context.Context, *JobRequest) (*JobInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedTasksManagerServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedTasksManagerServer) PauseJob(context.Context, *JobRequest) (*JobInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method PauseJob not implemented")
+}
+func (UnimplementedTasksManagerServer) ResumeJob(context.Context, *JobRequest) (*JobInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResumeJob not implemented")
+}
+func (UnimplementedTasksManagerServer) CancelJob(context.Context, *JobRequest) (*JobInfo, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelJob not implemented")
+}
 func (UnimplementedTasksManagerServer) RegisterClient(context.Context, *TaskClientInfo) (*RegisterClientResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RegisterClient not implemented")
 }
@@ -381,6 +663,222 @@ func _TasksManager_SubmitTask_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TasksManager_SubmitTaskStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TasksManagerServer).SubmitTaskStream(&grpc.GenericServerStream[TaskStreamRequest, TaskStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TasksManager_SubmitTaskStreamServer = grpc.BidiStreamingServer[TaskStreamRequest, TaskStreamResponse]
+
+func _TasksManager_DownloadTask_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TaskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TasksManagerServer).DownloadTask(m, &grpc.GenericServerStream[TaskRequest, TaskChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TasksManager_DownloadTaskServer = grpc.ServerStreamingServer[TaskChunk]
+
+func _TasksManager_GetCacheStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CacheStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).GetCacheStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_GetCacheStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).GetCacheStats(ctx, req.(*CacheStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_GetSchedulerStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchedulerStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).GetSchedulerStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_GetSchedulerStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).GetSchedulerStats(ctx, req.(*SchedulerStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_ReloadConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).ReloadConfig(ctx, req.(*ReloadConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_CreateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).CreateJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_CreateJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).CreateJob(ctx, req.(*CreateJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_CreateRegionJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRegionJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).CreateRegionJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_CreateRegionJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).CreateRegionJob(ctx, req.(*CreateRegionJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_CreateDiscoveryJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDiscoveryJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).CreateDiscoveryJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_CreateDiscoveryJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).CreateDiscoveryJob(ctx, req.(*CreateDiscoveryJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).GetJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_PauseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).PauseJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_PauseJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).PauseJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_ResumeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).ResumeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_ResumeJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).ResumeJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TasksManager_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TasksManagerServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TasksManager_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TasksManagerServer).CancelJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TasksManager_RegisterClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TaskClientInfo)
 	if err := dec(in); err != nil {
@@ -512,6 +1010,50 @@ var TasksManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SubmitTask",
 			Handler:    _TasksManager_SubmitTask_Handler,
 		},
+		{
+			MethodName: "GetCacheStats",
+			Handler:    _TasksManager_GetCacheStats_Handler,
+		},
+		{
+			MethodName: "GetSchedulerStats",
+			Handler:    _TasksManager_GetSchedulerStats_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _TasksManager_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "CreateJob",
+			Handler:    _TasksManager_CreateJob_Handler,
+		},
+		{
+			MethodName: "CreateRegionJob",
+			Handler:    _TasksManager_CreateRegionJob_Handler,
+		},
+		{
+			MethodName: "CreateDiscoveryJob",
+			Handler:    _TasksManager_CreateDiscoveryJob_Handler,
+		},
+		{
+			MethodName: "GetJob",
+			Handler:    _TasksManager_GetJob_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _TasksManager_ListJobs_Handler,
+		},
+		{
+			MethodName: "PauseJob",
+			Handler:    _TasksManager_PauseJob_Handler,
+		},
+		{
+			MethodName: "ResumeJob",
+			Handler:    _TasksManager_ResumeJob_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _TasksManager_CancelJob_Handler,
+		},
 		{
 			MethodName: "RegisterClient",
 			Handler:    _TasksManager_RegisterClient_Handler,
@@ -537,6 +1079,18 @@ var TasksManager_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _TasksManager_SyncNodeList_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubmitTaskStream",
+			Handler:       _TasksManager_SubmitTaskStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadTask",
+			Handler:       _TasksManager_DownloadTask_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "TasksManager.proto",
 }
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"crawler-platform/Store"
+	server "crawler-platform/cmd/grpcserver/internal"
+)
+
+// maskedSecret 打印有效配置时替换敏感字段的值
+const maskedSecret = "******"
+
+// configErrors 收集配置校验错误（"[表名] 键名: 原因"）
+type configErrors []error
+
+func (e *configErrors) add(section, key, format string, args ...any) {
+	*e = append(*e, fmt.Errorf("[%s] %s: %s", section, key, fmt.Sprintf(format, args...)))
+}
+
+// Validate 校验配置中的所有表，返回全部错误（没有错误时返回 nil）
+// 会访问文件系统（证书、DNS 服务器列表、仪表盘目录），但不会启动任何组件
+func (c *Config) Validate() error {
+	var errs configErrors
+
+	// [tls]
+	if c.TLS.Enable {
+		if _, err := server.LoadTLSConfigFromCertsDir(c.GetCertsDir()); err != nil {
+			errs.add("tls", "certs_dir", "%v", err)
+		}
+	}
+
+	// [protocol]
+	switch c.Protocol.Type {
+	case ProtocolTypeGRPC, ProtocolTypeTUIC, ProtocolTypeBoth:
+	default:
+		errs.add("protocol", "type", "不支持的协议类型 %q（必须为 grpc/tuic/both）", c.Protocol.Type)
+	}
+
+	// [server]
+	if err := validatePort(c.Server.Port); err != nil {
+		errs.add("server", "port", "%v", err)
+	}
+	for _, addr := range c.Server.Bootstrap {
+		if _, port, err := net.SplitHostPort(addr); err != nil || validatePort(port) != nil {
+			errs.add("server", "bootstrap", "无效的节点地址 %q（格式为 IP:端口）", addr)
+		}
+	}
+	if c.Server.StreamMaxInFlight < 0 {
+		errs.add("server", "stream_max_in_flight", "不能为负数")
+	}
+	if c.Server.HotCacheMaxBytes < 0 {
+		errs.add("server", "hot_cache_max_bytes", "不能为负数")
+	}
+	if c.Server.ForwardMaxHops < 0 {
+		errs.add("server", "forward_max_hops", "不能为负数")
+	}
+	validateDuration(&errs, "server", "drain_timeout", c.Server.DrainTimeout)
+	if err := server.ValidateAllowedRequestHeaders(c.Server.AllowedRequestHeaders); err != nil {
+		errs.add("server", "allowed_request_headers", "%v", err)
+	}
+	if c.Server.MaxBodyBytes < 0 {
+		errs.add("server", "max_body_bytes", "不能为负数")
+	}
+
+	// [tuic]
+	if c.TUIC.Enable && (c.Protocol.Type == ProtocolTypeTUIC || c.Protocol.Type == ProtocolTypeBoth) {
+		if err := validatePort(c.TUIC.Port); err != nil {
+			errs.add("tuic", "port", "%v", err)
+		}
+		certPath, keyPath := c.TUICCertPaths()
+		if certPath == "" || keyPath == "" {
+			errs.add("tuic", "tls_cert_path", "未配置证书，且证书目录 %s 下没有 cert.pem/key.pem 或 server.crt/server.key", c.GetCertsDir())
+		} else {
+			if _, err := os.Stat(certPath); err != nil {
+				errs.add("tuic", "tls_cert_path", "证书文件不可用: %v", err)
+			}
+			if _, err := os.Stat(keyPath); err != nil {
+				errs.add("tuic", "tls_key_path", "密钥文件不可用: %v", err)
+			}
+		}
+	}
+
+	// [LocalIPPool]
+	for _, ip := range c.LocalIPPool.StaticIPv4s {
+		if parsed := net.ParseIP(ip); parsed == nil || parsed.To4() == nil {
+			errs.add("LocalIPPool", "static_ipv4s", "无效的 IPv4 地址 %q", ip)
+		}
+	}
+	if c.LocalIPPool.IPv6SubnetCIDR != "" {
+		if ip, _, err := net.ParseCIDR(c.LocalIPPool.IPv6SubnetCIDR); err != nil {
+			errs.add("LocalIPPool", "ipv6_subnet_cidr", "%v", err)
+		} else if ip.To4() != nil {
+			errs.add("LocalIPPool", "ipv6_subnet_cidr", "%q 不是 IPv6 子网", c.LocalIPPool.IPv6SubnetCIDR)
+		}
+	}
+	if c.LocalIPPool.TargetIPCount < 0 {
+		errs.add("LocalIPPool", "target_ip_count", "不能为负数")
+	}
+
+	// [DomainMonitor] 与 [DNSDomain]
+	if c.DomainMonitor.Enable {
+		if len(c.DNSDomain.HostName) == 0 {
+			errs.add("DNSDomain", "HostName", "已启用域名监控，但没有配置域名")
+		}
+		if _, err := LoadDNSServersFromJSON(c.DomainMonitor.DNSServersFile); err != nil {
+			errs.add("DomainMonitor", "dns_servers_file", "%v", err)
+		}
+		if c.DomainMonitor.UpdateIntervalMinutes <= 0 {
+			errs.add("DomainMonitor", "update_interval_minutes", "必须大于 0")
+		}
+		switch c.DomainMonitor.StorageFormat {
+		case "json", "yaml", "toml":
+		default:
+			errs.add("DomainMonitor", "storage_format", "不支持的存储格式 %q（必须为 json/yaml/toml）", c.DomainMonitor.StorageFormat)
+		}
+	}
+
+	// [RockTreeDataConfig] 路径模板参数与 buildPathForTask 一致
+	if c.RockTreeData.Enable {
+		const section = "RockTreeDataConfig"
+		if c.RockTreeData.HostName == "" {
+			errs.add(section, "HostName", "已启用但未配置主机名")
+		}
+		validatePathTemplate(&errs, section, "BulkMetadataPath", c.RockTreeData.BulkMetadataPath, "0123", int32(1))
+		validatePathTemplate(&errs, section, "NodeDataPath", c.RockTreeData.NodeDataPath, "0123", int32(1), int32(1))
+		validatePathTemplate(&errs, section, "ImageryDataPath", c.RockTreeData.ImageryDataPath, "0123", int32(1), int32(1), int32(1))
+	}
+
+	// [GoogleEarthDesktopDataConfig]
+	if c.GoogleEarthDesktopData.Enable {
+		const section = "GoogleEarthDesktopDataConfig"
+		if c.GoogleEarthDesktopData.HostName == "" {
+			errs.add(section, "HostName", "已启用但未配置主机名")
+		}
+		if c.GoogleEarthDesktopData.TMHostName == "" {
+			errs.add(section, "tmHostName", "已启用但未配置多数据库主机名")
+		}
+		validatePathTemplate(&errs, section, "q2Path", c.GoogleEarthDesktopData.Q2Path, "0123", int32(1))
+		validatePathTemplate(&errs, section, "imageryPath", c.GoogleEarthDesktopData.ImageryPath, "0123", int32(1))
+		validatePathTemplate(&errs, section, "terrainPath", c.GoogleEarthDesktopData.TerrainPath, "0123", int32(1))
+		validatePathTemplate(&errs, section, "qpPath", c.GoogleEarthDesktopData.QPPath, "tm", "0123", int32(1))
+		validatePathTemplate(&errs, section, "imageryHistoryPath", c.GoogleEarthDesktopData.ImageryHistoryPath, "0123", int32(1), "fd9ab")
+	}
+
+	// [[Sites]]
+	c.validateSites(&errs)
+
+	// [UtlsClient]
+	const utls = "UtlsClient"
+	if c.UtlsClient.MaxConnsPerHost < 0 {
+		errs.add(utls, "max_conns_per_host", "不能为负数")
+	}
+	if c.UtlsClient.MaxConcurrentPreWarms < 0 {
+		errs.add(utls, "max_concurrent_pre_warms", "不能为负数")
+	}
+	validateDuration(&errs, utls, "pre_warm_interval", c.UtlsClient.PreWarmInterval)
+	validateDuration(&errs, utls, "conn_timeout", c.UtlsClient.ConnTimeout)
+	validateDuration(&errs, utls, "idle_timeout", c.UtlsClient.IdleTimeout)
+	validateDuration(&errs, utls, "max_conn_lifetime", c.UtlsClient.MaxConnLifetime)
+	validateDuration(&errs, utls, "health_check_interval", c.UtlsClient.HealthCheckInterval)
+	validateDuration(&errs, utls, "ip_blacklist_timeout", c.UtlsClient.IPBlacklistTimeout)
+	if c.UtlsClient.PerHostBurst < 0 || c.UtlsClient.PerRemoteIPBurst < 0 || c.UtlsClient.PerLocalIPBurst < 0 {
+		errs.add(utls, "per_*_burst", "突发请求数不能为负数")
+	}
+
+	// [Storage]
+	if c.Storage.Enable {
+		switch Store.StorageBackend(c.Storage.Backend) {
+		case Store.BackendBBolt, Store.BackendSQLite:
+		default:
+			errs.add("Storage", "backend", "不支持的持久化后端 %q（必须为 bbolt/sqlite）", c.Storage.Backend)
+		}
+		if c.Storage.DBDir == "" {
+			errs.add("Storage", "db_dir", "已启用但未配置数据库目录")
+		}
+		validateDuration(&errs, "Storage", "cache_expiration", c.Storage.CacheExpiration)
+		validateDuration(&errs, "Storage", "persist_interval", c.Storage.PersistInterval)
+		if c.Storage.EnableAsyncPersist && c.Storage.PersistBatchSize <= 0 {
+			errs.add("Storage", "persist_batch_size", "启用异步持久化时必须大于 0")
+		}
+	}
+
+	// [Jobs] 与 [Scheduler]
+	if c.Jobs.Concurrency < 0 {
+		errs.add("Jobs", "concurrency", "不能为负数")
+	}
+	if c.Scheduler.MaxRunning < 0 {
+		errs.add("Scheduler", "max_running", "不能为负数")
+	}
+	if c.Scheduler.MaxQueued < 0 {
+		errs.add("Scheduler", "max_queued", "不能为负数")
+	}
+	validateDuration(&errs, "Scheduler", "max_wait", c.Scheduler.MaxWait)
+
+	// [TaskTimeouts]
+	const timeouts = "TaskTimeouts"
+	validateDuration(&errs, timeouts, "default", c.TaskTimeouts.Default)
+	validateDuration(&errs, timeouts, "rocktree_bulk_metadata", c.TaskTimeouts.RockTreeBulkMetadata)
+	validateDuration(&errs, timeouts, "rocktree_node_data", c.TaskTimeouts.RockTreeNodeData)
+	validateDuration(&errs, timeouts, "q2", c.TaskTimeouts.Q2)
+	validateDuration(&errs, timeouts, "imagery", c.TaskTimeouts.Imagery)
+	validateDuration(&errs, timeouts, "imagery_history", c.TaskTimeouts.ImageryHistory)
+	validateDuration(&errs, timeouts, "qp", c.TaskTimeouts.QP)
+	validateDuration(&errs, timeouts, "terrain", c.TaskTimeouts.Terrain)
+	validateDuration(&errs, timeouts, "http_site", c.TaskTimeouts.HTTPSite)
+
+	// [Auth]
+	if _, _, err := c.Auth.ToIdentities(); err != nil {
+		errs.add("Auth", "tokens", "%v", err)
+	}
+	if c.Auth.NodeToken != "" && !c.TLS.Enable {
+		errs.add("Auth", "node_token", "未启用 [tls] 时节点令牌会以明文发送，需先启用 TLS")
+	}
+
+	// [Metrics] 与 [Admin]
+	if c.Metrics.Enable {
+		if _, _, err := net.SplitHostPort(c.Metrics.Address); err != nil {
+			errs.add("Metrics", "address", "%v", err)
+		}
+	}
+	if c.Admin.Enable {
+		if _, _, err := net.SplitHostPort(c.Admin.Address); err != nil {
+			errs.add("Admin", "address", "%v", err)
+		}
+		if c.Admin.WebDir != "" {
+			if info, err := os.Stat(c.Admin.WebDir); err != nil || !info.IsDir() {
+				errs.add("Admin", "web_dir", "仪表盘目录 %s 不存在", c.Admin.WebDir)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateSites 校验通用 HTTP 站点（名称唯一，路径模板与方法有效，启用域名监控时主机名需在 [DNSDomain] 中）
+func (c *Config) validateSites(errs *configErrors) {
+	names := make(map[string]bool, len(c.Sites))
+	for i, site := range c.Sites {
+		section := fmt.Sprintf("Sites.%d", i)
+		if site.Name != "" {
+			section = "Sites." + site.Name
+		}
+		if names[site.Name] {
+			errs.add(section, "name", "站点名称重复")
+		}
+		names[site.Name] = true
+		if err := server.ValidateSite(server.Site{
+			Name:           site.Name,
+			HostName:       site.HostName,
+			PathTemplates:  site.PathTemplates,
+			AllowedMethods: site.AllowedMethods,
+		}); err != nil {
+			errs.add(section, "site", "%v", err)
+		}
+		if site.ValidatorPath != "" && !strings.HasPrefix(site.ValidatorPath, "/") {
+			errs.add(section, "validator_path", "必须以 / 开头")
+		}
+		if c.DomainMonitor.Enable && site.HostName != "" && !slices.Contains(c.DNSDomain.HostName, site.HostName) {
+			errs.add(section, "HostName", "主机名 %s 不在 [DNSDomain] HostName 中，热连接池无法获取其 IP", site.HostName)
+		}
+	}
+}
+
+// validatePort 校验端口号（1-65535）
+func validatePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n <= 0 || n > 65535 {
+		return fmt.Errorf("无效的端口 %q", port)
+	}
+	return nil
+}
+
+// validateDuration 校验时间间隔字符串（为空时使用默认值，不报错）
+func validateDuration(errs *configErrors, section, key, value string) {
+	if value == "" {
+		return
+	}
+	if d, err := time.ParseDuration(value); err != nil {
+		errs.add(section, key, "无效的时间间隔 %q", value)
+	} else if d < 0 {
+		errs.add(section, key, "时间间隔 %q 不能为负数", value)
+	}
+}
+
+// validatePathTemplate 用示例参数格式化路径模板，检查占位符的数量与类型是否与任务类型匹配
+func validatePathTemplate(errs *configErrors, section, key, template string, args ...any) {
+	if template == "" {
+		errs.add(section, key, "未配置路径模板")
+		return
+	}
+	if !strings.HasPrefix(template, "/") {
+		errs.add(section, key, "路径模板 %q 必须以 / 开头", template)
+	}
+	if path := fmt.Sprintf(template, args...); strings.Contains(path, "%!") {
+		errs.add(section, key, "路径模板 %q 与参数不匹配（需要 %d 个参数，格式化结果: %s）", template, len(args), path)
+	}
+}
+
+// runValidate 执行 validate 子命令：加载配置文件，校验所有表并打印有效配置（合并默认值后）
+// 用法: grpcserver validate [配置文件路径]，未指定路径时使用 GRPCSERVER_CONFIG 或默认路径
+// 返回进程退出码：配置有错误时为 1
+func runValidate(args []string) int {
+	path := defaultConfigPath()
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintf(os.Stderr, "配置文件不可用: %v\n", err)
+		return 1
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	// 未知的配置项（通常是拼写错误）会被 TOML 解析忽略，这里单独报告
+	errs := []error{config.Validate()}
+	if meta, err := toml.DecodeFile(path, &Config{}); err == nil {
+		for _, key := range meta.Undecoded() {
+			errs = append(errs, fmt.Errorf("未知的配置项: %s", key))
+		}
+	}
+
+	fmt.Printf("# 有效配置（%s 与默认值合并后）\n", path)
+	if err := toml.NewEncoder(os.Stdout).Encode(config.masked()); err != nil {
+		fmt.Fprintf(os.Stderr, "输出有效配置失败: %v\n", err)
+		return 1
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		fmt.Fprintf(os.Stderr, "\n配置校验失败:\n%v\n", err)
+		return 1
+	}
+	fmt.Fprintln(os.Stderr, "\n配置校验通过")
+	return 0
+}
+
+// masked 返回隐藏了令牌与密码的配置副本（用于打印）
+func (c *Config) masked() *Config {
+	mask := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return maskedSecret
+	}
+	m := *c
+	m.TUIC.Token = mask(m.TUIC.Token)
+	m.TUIC.Password = mask(m.TUIC.Password)
+	m.IPInfo.Token = mask(m.IPInfo.Token)
+	m.Auth.NodeToken = mask(m.Auth.NodeToken)
+	m.Auth.Tokens = make([]AuthTokenConfig, len(c.Auth.Tokens))
+	for i, t := range c.Auth.Tokens {
+		t.Token = mask(t.Token)
+		m.Auth.Tokens[i] = t
+	}
+	return &m
+}
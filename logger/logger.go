@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"sync"
+	"sync/atomic"
 )
 
 var (
@@ -42,33 +43,43 @@ func Warn(format string, args ...interface{})  { GetGlobalLogger().Warn(format,
 func Error(format string, args ...interface{}) { GetGlobalLogger().Error(format, args...) }
 
 type ConsoleLogger struct {
-	debug bool
-	info  bool
-	warn  bool
-	error bool
+	debug atomic.Bool
+	info  atomic.Bool
+	warn  atomic.Bool
+	error atomic.Bool
 }
 
 func NewConsoleLogger(debug, info, warn, error bool) *ConsoleLogger {
-	return &ConsoleLogger{debug: debug, info: info, warn: warn, error: error}
+	l := &ConsoleLogger{}
+	l.SetLevels(debug, info, warn, error)
+	return l
+}
+
+// SetLevels 运行时修改启用的日志级别
+func (l *ConsoleLogger) SetLevels(debug, info, warn, error bool) {
+	l.debug.Store(debug)
+	l.info.Store(info)
+	l.warn.Store(warn)
+	l.error.Store(error)
 }
 
 func (l *ConsoleLogger) Debug(format string, args ...interface{}) {
-	if l.debug {
+	if l.debug.Load() {
 		log.Printf("[DEBUG] "+format, args...)
 	}
 }
 func (l *ConsoleLogger) Info(format string, args ...interface{}) {
-	if l.info {
+	if l.info.Load() {
 		log.Printf("[INFO] "+format, args...)
 	}
 }
 func (l *ConsoleLogger) Warn(format string, args ...interface{}) {
-	if l.warn {
+	if l.warn.Load() {
 		log.Printf("[WARN] "+format, args...)
 	}
 }
 func (l *ConsoleLogger) Error(format string, args ...interface{}) {
-	if l.error {
+	if l.error.Load() {
 		log.Printf("[ERROR] "+format, args...)
 	}
 }
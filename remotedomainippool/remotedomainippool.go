@@ -31,6 +31,9 @@ type DomainMonitor interface { // 定义DomainMonitor接口
 	// 返回的数据是深拷贝，可以安全地被调用方修改。
 	// 如果找不到该域名的数据，返回的 bool 值为 false。
 	GetDomainPool(domain string) (map[string][]IPRecord, bool) // 获取域名IP池方法
+	// SetDomains 运行时替换监控的域名列表，新增的域名立即开始解析。
+	// 已移除域名的最新数据保留在内存中，但不再更新。
+	SetDomains(domains []string) // 设置域名列表方法
 }
 
 // --- 2. 数据结构定义 ---
@@ -184,6 +187,31 @@ func (m *remoteIPMonitor) GetDomainPool(domain string) (map[string][]IPRecord, b
 	return copiedPool, true // 返回拷贝池和true
 }
 
+// SetDomains 实现了 DomainMonitor 接口。
+// 参数：domains - 新的域名列表（为空时忽略）
+func (m *remoteIPMonitor) SetDomains(domains []string) { // 实现SetDomains方法
+	if len(domains) == 0 { // 如果域名列表为空
+		return // 保持原列表
+	}
+	m.mu.Lock() // 加写锁
+	current := make(map[string]bool, len(m.config.Domains))
+	for _, domain := range m.config.Domains { // 当前监控的域名
+		current[domain] = true
+	}
+	added := make([]string, 0, len(domains))
+	for _, domain := range domains { // 找出新增的域名
+		if !current[domain] {
+			added = append(added, domain)
+		}
+	}
+	m.config.Domains = append([]string(nil), domains...) // 替换域名列表
+	m.mu.Unlock()                                        // 解写锁
+
+	for _, domain := range added { // 立即解析新增的域名，不等待下一个更新周期
+		go m.processSingleDomain(domain)
+	}
+}
+
 // run 是在后台goroutine中运行的主循环。
 func (m *remoteIPMonitor) run() { // 运行方法
 	m.updateAllDomains() // 更新所有域名
@@ -201,8 +229,12 @@ func (m *remoteIPMonitor) run() { // 运行方法
 func (m *remoteIPMonitor) updateAllDomains() { // 更新所有域名方法
 	fmt.Printf("[%s] 开始按域名隔离的累加式增量更新...\n", time.Now().Format(time.Kitchen)) // 输出更新开始日志
 
-	var wg sync.WaitGroup                     // 声明等待组
-	for _, domain := range m.config.Domains { // 遍历域名列表
+	m.mu.RLock()                                          // 加读锁
+	domains := append([]string(nil), m.config.Domains...) // 拷贝域名列表（可能被 SetDomains 替换）
+	m.mu.RUnlock()                                        // 解读锁
+
+	var wg sync.WaitGroup            // 声明等待组
+	for _, domain := range domains { // 遍历域名列表
 		wg.Add(1)           // 增加等待计数
 		go func(d string) { // 启动goroutine处理单个域名
 			defer wg.Done()          // 延迟减少等待计数
@@ -27,6 +27,16 @@ func NewBlacklist(timeout time.Duration) *Blacklist {
 	}
 }
 
+// SetTimeout 修改黑名单超时时间（对已拉黑的IP同样生效），<=0 时忽略。
+func (b *Blacklist) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.timeout = timeout
+}
+
 // Add 将一个IP添加到黑名单中，并记录当前时间。
 func (b *Blacklist) Add(ip string) {
 	b.mu.Lock()
@@ -37,9 +37,9 @@ func (cm *ConnectionManager) AddConnection(conn *UTLSConnection) {
 	}
 
 	// 检查该主机的连接数是否超过限制
-	if cm.config.MaxConnsPerHost > 0 {
+	if maxConns := cm.config.limits().MaxConnsPerHost; maxConns > 0 {
 		hostIPs := cm.hostMapping[conn.targetHost]
-		if len(hostIPs) >= cm.config.MaxConnsPerHost {
+		if len(hostIPs) >= maxConns {
 			// 已达到该主机的最大连接数限制
 			// 注意：这里不阻止添加，因为 max_conns_per_host 应该理解为"每个主机最多预热多少个不同的 IP"
 			// 如果用户希望每个 IP 都参与，应该设置 max_conns_per_host 为一个很大的值（如 1000）
@@ -203,6 +203,7 @@ func (cm *ConnectionManager) Close() {
 func (cm *ConnectionManager) CleanupIdleConnections() int {
 	var toRemove []string // 改为存储IP地址而不是连接对象
 	now := time.Now()
+	idleTimeout := cm.config.limits().IdleTimeout
 
 	cm.mu.RLock()
 	// 遍历时只收集信息，不做修改
@@ -210,7 +211,7 @@ func (cm *ConnectionManager) CleanupIdleConnections() int {
 		conn.mu.Lock()
 		// 检查最后使用时间，而不是创建时间
 		// 只有空闲（不在使用中）且最后使用时间超过 IdleTimeout 的连接才被清理
-		isIdle := !conn.inUse && now.Sub(conn.lastUsed) > cm.config.IdleTimeout
+		isIdle := !conn.inUse && now.Sub(conn.lastUsed) > idleTimeout
 		conn.mu.Unlock()
 		if isIdle {
 			toRemove = append(toRemove, ip)
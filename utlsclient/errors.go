@@ -1,6 +1,15 @@
 package utlsclient
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+)
 
 // 错误定义
 // 统一管理所有包级别的错误类型和变量，便于错误处理和使用
@@ -18,6 +27,55 @@ var (
 	// ErrConnectionInUse 表示连接正在使用中
 	ErrConnectionInUse = errors.New("connection is in use")
 
+	// ErrPoolWarming 表示 PoolManager 正在预热中，暂时没有可用连接（同时匹配 ErrNoAvailableConnection）
+	ErrPoolWarming = fmt.Errorf("%w: pool manager is warming up", ErrNoAvailableConnection)
+
+	// ErrAllConnectionsUnhealthy 表示主机的所有连接都不健康，正在异步激活（同时匹配 ErrNoAvailableConnection）
+	ErrAllConnectionsUnhealthy = fmt.Errorf("%w: all connections are unhealthy", ErrNoAvailableConnection)
+
+	// ErrAllConnectionsBusy 表示主机的所有健康连接当前都在使用中（同时匹配 ErrConnectionInUse）
+	ErrAllConnectionsBusy = fmt.Errorf("%w: all connections are busy", ErrConnectionInUse)
+
+	// ErrRateLimited 表示请求超过主机、远程 IP 或本地源 IP 的速率限制
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrTransportReset 表示请求过程中底层连接被重置或关闭
+	ErrTransportReset = errors.New("transport connection reset")
+
+	// ErrTimeout 表示请求过程中网络读写超时
+	ErrTimeout = errors.New("transport timeout")
+
 	// ErrInvalidConfig 表示配置无效
 	ErrInvalidConfig = errors.New("invalid configuration")
 )
+
+// WrapTransportError 将请求过程中的网络错误归类为 ErrTransportReset 或 ErrTimeout（保留原始错误）
+// 无法归类的错误原样返回
+func WrapTransportError(err error) error {
+	if err == nil || errors.Is(err, ErrTransportReset) || errors.Is(err, ErrTimeout) {
+		return err
+	}
+	var netErr net.Error
+	if errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	if isConnectionClosed(err) {
+		return fmt.Errorf("%w: %w", ErrTransportReset, err)
+	}
+	return err
+}
+
+// isConnectionClosed 判断错误是否表示底层连接已被重置或关闭
+// HTTP/2 客户端的连接关闭错误未导出，只能通过错误信息判断
+func isConnectionClosed(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNABORTED) {
+		return true
+	}
+	errStr := err.Error()
+	return strings.Contains(errStr, "use of closed network connection") ||
+		strings.Contains(errStr, "connection closed") ||
+		strings.Contains(errStr, "broken pipe") ||
+		strings.Contains(errStr, "EOF")
+}
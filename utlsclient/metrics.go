@@ -19,6 +19,7 @@ type ConnectionMetrics struct {
 	totalRequests   int64 // 总请求数
 	failedRequests  int64 // 失败请求数
 	forbiddenErrors int64 // 403错误数
+	rateLimited     int64 // 因限速被拒绝的请求数
 
 	// 性能指标
 	requestDurationMs int64 // 请求耗时（毫秒，用于计算平均耗时）
@@ -99,6 +100,12 @@ func (m *ConnectionMetrics) RecordForbiddenError() {
 	m.updateTimestamp()
 }
 
+// RecordRateLimited 记录请求因限速被拒绝
+func (m *ConnectionMetrics) RecordRateLimited() {
+	atomic.AddInt64(&m.rateLimited, 1)
+	m.updateTimestamp()
+}
+
 // RecordIPUsed 记录IP使用
 func (m *ConnectionMetrics) RecordIPUsed(isIPv6 bool) {
 	if isIPv6 {
@@ -132,6 +139,7 @@ func (m *ConnectionMetrics) GetSnapshot() MetricsSnapshot {
 		TotalRequests:        atomic.LoadInt64(&m.totalRequests),
 		FailedRequests:       atomic.LoadInt64(&m.failedRequests),
 		ForbiddenErrors:      atomic.LoadInt64(&m.forbiddenErrors),
+		RateLimitedRequests:  atomic.LoadInt64(&m.rateLimited),
 		ConnectionsCreated:   atomic.LoadInt64(&m.connectionsCreated),
 		ConnectionsClosed:    atomic.LoadInt64(&m.connectionsClosed),
 		ConnectionsRecovered: atomic.LoadInt64(&m.connectionsRecovered),
@@ -173,9 +181,11 @@ type MetricsSnapshot struct {
 	ActiveConnections    int64     `json:"active_connections"`
 	HealthyConnections   int64     `json:"healthy_connections"`
 	UnhealthyConnections int64     `json:"unhealthy_connections"`
+	InUseConnections     int64     `json:"in_use_connections"`
 	TotalRequests        int64     `json:"total_requests"`
 	FailedRequests       int64     `json:"failed_requests"`
 	ForbiddenErrors      int64     `json:"forbidden_errors"`
+	RateLimitedRequests  int64     `json:"rate_limited_requests"`
 	ConnectionsCreated   int64     `json:"connections_created"`
 	ConnectionsClosed    int64     `json:"connections_closed"`
 	ConnectionsRecovered int64     `json:"connections_recovered"`
@@ -185,6 +195,8 @@ type MetricsSnapshot struct {
 	BlacklistCleaned     int64     `json:"blacklist_cleaned"`
 	AvgRequestDurationMs int64     `json:"avg_request_duration_ms"`
 	LastUpdated          time.Time `json:"last_updated"`
+
+	RateLimits RateLimits `json:"rate_limits"` // 当前限速配置
 }
 
 // SuccessRate 计算请求成功率
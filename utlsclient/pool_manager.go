@@ -26,11 +26,12 @@ type PoolManager struct {
 	config      *PoolConfig
 	remotePool  RemoteIPPool
 
-	stopChan    chan struct{}
-	wg          sync.WaitGroup
-	stopOnce    sync.Once // 确保Stop只执行一次
-	initialized int32     // 标记是否已完成初始化（使用atomic保证线程安全）
-	initOnce    sync.Once // 确保初始化只执行一次
+	limitsChanged chan struct{} // 预热间隔修改通知（SetPoolLimits）
+	stopChan      chan struct{}
+	wg            sync.WaitGroup
+	stopOnce      sync.Once // 确保Stop只执行一次
+	initialized   int32     // 标记是否已完成初始化（使用atomic保证线程安全）
+	initOnce      sync.Once // 确保初始化只执行一次
 }
 
 // NewPoolManager 创建一个新的池管理器。
@@ -42,12 +43,21 @@ func NewPoolManager(
 	config *PoolConfig,
 ) *PoolManager {
 	return &PoolManager{
-		remotePool:  remotePool,
-		connManager: connManager,
-		blacklist:   blacklist,
-		validator:   validator,
-		config:      config,
-		stopChan:    make(chan struct{}),
+		remotePool:    remotePool,
+		connManager:   connManager,
+		blacklist:     blacklist,
+		validator:     validator,
+		config:        config,
+		limitsChanged: make(chan struct{}, 1),
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// notifyLimitsChanged 通知维护循环按新的预热间隔重置定时器
+func (pm *PoolManager) notifyLimitsChanged() {
+	select {
+	case pm.limitsChanged <- struct{}{}:
+	default:
 	}
 }
 
@@ -82,7 +92,7 @@ func (pm *PoolManager) maintenanceLoop() {
 	})
 
 	// 预热定时器
-	preWarmTicker := time.NewTicker(pm.config.PreWarmInterval)
+	preWarmTicker := time.NewTicker(pm.config.limits().PreWarmInterval)
 	defer preWarmTicker.Stop()
 
 	// 黑名单恢复检查定时器（如果配置了检查间隔）
@@ -103,6 +113,8 @@ func (pm *PoolManager) maintenanceLoop() {
 			pm.maintainPoolFromWhitelist()
 		case <-blacklistTickerChan:
 			pm.checkBlacklistRecovery()
+		case <-pm.limitsChanged:
+			preWarmTicker.Reset(pm.config.limits().PreWarmInterval)
 		case <-pm.stopChan:
 			return
 		}
@@ -126,7 +138,7 @@ func (pm *PoolManager) maintainPoolFromRemoteIPs(isInitial bool) {
 
 	var wg sync.WaitGroup
 	var successCount int64 // 成功加入热连接池的连接数
-	concurrencyLimit := make(chan struct{}, pm.config.MaxConcurrentPreWarms)
+	concurrencyLimit := make(chan struct{}, pm.config.limits().MaxConcurrentPreWarms)
 
 	for domain, ips := range allDomainIPs {
 		// 检查该主机当前已有的连接数
@@ -150,9 +162,9 @@ func (pm *PoolManager) maintainPoolFromRemoteIPs(isInitial bool) {
 			// 检查是否超过每个主机的最大连接数限制
 			// 注意：max_conns_per_host 限制的是每个主机（域名）的连接数
 			// 如果设置为 0 或负数，表示不限制
-			if pm.config.MaxConnsPerHost > 0 && currentConnCount >= pm.config.MaxConnsPerHost {
+			if maxConns := pm.config.limits().MaxConnsPerHost; maxConns > 0 && currentConnCount >= maxConns {
 				// 已达到该主机的最大连接数限制，跳过此 IP
-				projlogger.Debug("主机 %s 已达到最大连接数限制 (%d)，跳过 IP %s", domain, pm.config.MaxConnsPerHost, ip)
+				projlogger.Debug("主机 %s 已达到最大连接数限制 (%d)，跳过 IP %s", domain, maxConns, ip)
 				continue
 			}
 
@@ -216,7 +228,7 @@ func (pm *PoolManager) maintainPoolFromWhitelist() {
 
 	var wg sync.WaitGroup
 	var successCount int64
-	concurrencyLimit := make(chan struct{}, pm.config.MaxConcurrentPreWarms)
+	concurrencyLimit := make(chan struct{}, pm.config.limits().MaxConcurrentPreWarms)
 
 	for domain, ips := range domainToIPs {
 		wg.Add(1)
@@ -266,7 +278,7 @@ type batchProcessor struct {
 
 // newBatchProcessor 创建新的批次处理器
 func newBatchProcessor(pm *PoolManager, concurrencyLimit chan struct{}) *batchProcessor {
-	batchSize := pm.config.MaxConcurrentPreWarms
+	batchSize := pm.config.limits().MaxConcurrentPreWarms
 	if batchSize <= 0 {
 		batchSize = 10
 	}
@@ -413,7 +425,7 @@ func (pm *PoolManager) preWarmConnectionsBatch(domain string, ips []string, conc
 	// 检查文件描述符错误
 	if count := atomic.LoadInt32(&processor.tooManyFilesCount); count > 0 {
 		projlogger.Warn("检测到 %d 个\"too many open files\"错误，建议：1) 降低 max_concurrent_pre_warms 配置值（当前: %d），2) 增加系统文件描述符限制（ulimit -n）",
-			count, pm.config.MaxConcurrentPreWarms)
+			count, pm.config.limits().MaxConcurrentPreWarms)
 	}
 
 	// 收集成功建立的连接
@@ -542,7 +554,7 @@ func (pm *PoolManager) checkBlacklistRecovery() {
 	}
 
 	// 并发检查黑名单中的IP
-	concurrencyLimit := make(chan struct{}, pm.config.MaxConcurrentPreWarms)
+	concurrencyLimit := make(chan struct{}, pm.config.limits().MaxConcurrentPreWarms)
 	var wg sync.WaitGroup
 	var recoveredCount int64
 
@@ -0,0 +1,133 @@
+package utlsclient
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimit 令牌桶限速配置
+type RateLimit struct {
+	Rate  float64 `mapstructure:"Rate" json:"rate"`   // 每秒允许的请求数（<=0 表示不限速）
+	Burst int     `mapstructure:"Burst" json:"burst"` // 允许的突发请求数（<=0 时按 1 处理）
+}
+
+// RateLimits 热连接池的限速配置
+// 每个目标主机、每个远程 IP、每个本地源 IP 各自拥有独立的令牌桶，一次请求需要同时从三个桶中取得令牌
+// 令牌在 GetConnectionForHost 获取连接时扣除，限速按连接获取次数计算：获取后在同一连接上发送的多个请求只计一次，
+// 健康检查等直接调用 UTLSConnection.RoundTrip 的请求不受限速。需要按请求限速的调用方应每个请求获取一次连接
+type RateLimits struct {
+	PerHost     RateLimit `mapstructure:"PerHost" json:"per_host"`          // 每个目标主机
+	PerRemoteIP RateLimit `mapstructure:"PerRemoteIP" json:"per_remote_ip"` // 每个远程 IP
+	PerLocalIP  RateLimit `mapstructure:"PerLocalIP" json:"per_local_ip"`   // 每个本地源 IP
+}
+
+// 令牌桶类别
+const (
+	bucketHost = iota
+	bucketRemoteIP
+	bucketLocalIP
+)
+
+// bucketKey 令牌桶键（类别 + 主机名/IP）
+type bucketKey struct {
+	kind int
+	name string
+}
+
+// limitFor 返回类别对应的限速配置
+func (l RateLimits) limitFor(kind int) RateLimit {
+	switch kind {
+	case bucketHost:
+		return l.PerHost
+	case bucketRemoteIP:
+		return l.PerRemoteIP
+	default:
+		return l.PerLocalIP
+	}
+}
+
+// tokenBucket 令牌桶
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill 按经过的时间补充令牌，返回补充后的令牌数
+func (b *tokenBucket) refill(limit RateLimit, now time.Time) float64 {
+	burst := float64(max(limit.Burst, 1))
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	return b.tokens
+}
+
+// RateLimiter 按主机、远程 IP、本地源 IP 限速的令牌桶集合（限速配置可在运行时修改）
+type RateLimiter struct {
+	mu      sync.Mutex
+	limits  RateLimits
+	buckets map[bucketKey]*tokenBucket
+}
+
+// NewRateLimiter 创建限速器
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	return &RateLimiter{limits: limits, buckets: make(map[bucketKey]*tokenBucket)}
+}
+
+// SetLimits 修改限速配置（已有令牌桶中的令牌数保持不变，超过新的突发数时在下次取令牌时截断）
+func (r *RateLimiter) SetLimits(limits RateLimits) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limits = limits
+}
+
+// Limits 返回当前限速配置
+func (r *RateLimiter) Limits() RateLimits {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.limits
+}
+
+// Allow 尝试为一次请求同时从主机、远程 IP、本地源 IP 三个令牌桶中各取一个令牌
+// 任一令牌桶不足时不取任何令牌并返回 false；为空的主机名或 IP 不参与限速
+func (r *RateLimiter) Allow(host, remoteIP, localIP string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var buckets []*tokenBucket
+	for _, key := range []bucketKey{{bucketHost, host}, {bucketRemoteIP, remoteIP}, {bucketLocalIP, localIP}} {
+		limit := r.limits.limitFor(key.kind)
+		if limit.Rate <= 0 || key.name == "" {
+			continue
+		}
+		bucket, ok := r.buckets[key]
+		if !ok {
+			bucket = &tokenBucket{tokens: float64(max(limit.Burst, 1)), last: now}
+			r.buckets[key] = bucket
+		}
+		if bucket.refill(limit, now) < 1 {
+			return false
+		}
+		buckets = append(buckets, bucket)
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	return true
+}
+
+// Cleanup 清理已补满的令牌桶（长时间未使用的主机/IP 不再占用内存），返回清理数量
+func (r *RateLimiter) Cleanup() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	removed := 0
+	for key, bucket := range r.buckets {
+		limit := r.limits.limitFor(key.kind)
+		if limit.Rate <= 0 || bucket.refill(limit, now) >= float64(max(limit.Burst, 1)) {
+			delete(r.buckets, key)
+			removed++
+		}
+	}
+	return removed
+}
@@ -1,11 +1,15 @@
 package utlsclient
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"net"
 	"net/http"
+	"slices"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	projlogger "crawler-platform/logger"
@@ -18,11 +22,13 @@ type Client struct {
 	blacklist   *Blacklist
 	poolManager *PoolManager
 	metrics     *ConnectionMetrics // 连接池指标收集器
+	rateLimiter *RateLimiter       // 按主机/远程 IP/本地源 IP 限速
 
-	stopChan chan struct{}
-	wg       sync.WaitGroup
-	running  bool
-	mu       sync.Mutex
+	limitsChanged chan struct{} // 健康检查间隔修改通知（SetPoolLimits）
+	stopChan      chan struct{}
+	wg            sync.WaitGroup
+	running       bool
+	mu            sync.Mutex
 }
 
 // NewClient 创建并初始化所有组件。
@@ -30,6 +36,9 @@ func NewClient(config *PoolConfig, remotePool RemoteIPPool) (*Client, error) {
 	if config == nil || remotePool == nil {
 		return nil, fmt.Errorf("%w: 配置和远程IP池提供者不能为空", ErrInvalidConfig)
 	}
+	if config.mu == nil {
+		config.mu = &sync.RWMutex{}
+	}
 
 	// 1. 创建黑名单和连接管理器 (ConnectionManager 即为白名单)
 	blacklist := NewBlacklist(config.IPBlacklistTimeout)
@@ -47,12 +56,14 @@ func NewClient(config *PoolConfig, remotePool RemoteIPPool) (*Client, error) {
 	poolManager := NewPoolManager(remotePool, connManager, blacklist, validator, config)
 
 	return &Client{
-		config:      config,
-		connManager: connManager,
-		blacklist:   blacklist,
-		poolManager: poolManager,
-		metrics:     NewConnectionMetrics(), // 初始化指标收集器
-		stopChan:    make(chan struct{}),
+		config:        config,
+		connManager:   connManager,
+		blacklist:     blacklist,
+		poolManager:   poolManager,
+		metrics:       NewConnectionMetrics(), // 初始化指标收集器
+		rateLimiter:   NewRateLimiter(config.RateLimits),
+		limitsChanged: make(chan struct{}, 1),
+		stopChan:      make(chan struct{}),
 	}, nil
 }
 
@@ -93,6 +104,7 @@ func (c *Client) Stop() {
 }
 
 // GetConnectionForHost 从“白名单”(ConnectionManager)中获取一个健康的连接。
+// 每次成功获取都会扣除一次限速令牌（见 RateLimits），连接使用期间的请求不再单独限速。
 func (c *Client) GetConnectionForHost(host string) (*UTLSConnection, error) {
 	connections := c.connManager.GetConnectionsForHost(host)
 	if len(connections) == 0 {
@@ -102,7 +114,7 @@ func (c *Client) GetConnectionForHost(host string) (*UTLSConnection, error) {
 			// 检查 PoolManager 是否已完成初始化
 			if !c.poolManager.IsInitialized() {
 				// PoolManager 还未初始化完成，立即返回错误，让上层处理重试
-				return nil, fmt.Errorf("%w: 没有到主机 %s 的可用连接，PoolManager正在预热中，请稍后重试", ErrNoAvailableConnection, host)
+				return nil, fmt.Errorf("%w: 没有到主机 %s 的可用连接，PoolManager正在预热中，请稍后重试", ErrPoolWarming, host)
 			}
 			// PoolManager 已初始化完成，但该主机没有可用连接
 			return nil, fmt.Errorf("%w: 没有到主机 %s 的可用连接，请检查该主机的IP配置", ErrNoAvailableConnection, host)
@@ -128,21 +140,38 @@ func (c *Client) GetConnectionForHost(host string) (*UTLSConnection, error) {
 		}
 
 		// 立即返回错误，不等待激活完成（避免阻塞，让上层决定是否重试）
-		return nil, fmt.Errorf("%w: 没有到主机 %s 的可用连接，所有连接都不健康，正在异步激活", ErrNoAvailableConnection, host)
+		return nil, fmt.Errorf("%w: 没有到主机 %s 的可用连接，所有连接都不健康，正在异步激活", ErrAllConnectionsUnhealthy, host)
 	}
 
-	// 有健康连接，从随机位置开始尝试获取
+	// 有健康连接，从随机位置开始尝试获取（获取后还需通过主机/远程 IP/本地源 IP 限速）
 	n := len(connections)
 	start := rand.Intn(n)
+	rateLimited := false
 	for i := 0; i < n; i++ {
 		idx := (start + i) % n
 		conn := connections[idx]
 		if conn.TryAcquire() {
-			return conn, nil
+			if c.rateLimiter.Allow(host, conn.TargetIP(), conn.LocalIP()) {
+				return conn, nil
+			}
+			conn.release()
+			rateLimited = true
 		}
 	}
 
-	return nil, fmt.Errorf("%w: 主机 %s 的所有连接当前都在使用中", ErrConnectionInUse, host)
+	if rateLimited {
+		c.metrics.RecordRateLimited()
+		return nil, fmt.Errorf("%w: 主机 %s 的请求速率超过限制", ErrRateLimited, host)
+	}
+	return nil, fmt.Errorf("%w: 主机 %s 的所有连接当前都在使用中", ErrAllConnectionsBusy, host)
+}
+
+// GetConnectionForHostContext 与 GetConnectionForHost 相同，上下文已取消或超时时不获取连接。
+func (c *Client) GetConnectionForHostContext(ctx context.Context, host string) (*UTLSConnection, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("获取连接已取消: %w", err)
+	}
+	return c.GetConnectionForHost(host)
 }
 
 // ReleaseConnection 将使用完毕的连接交还给客户端处理。
@@ -197,11 +226,7 @@ func (c *Client) ReleaseConnection(conn *UTLSConnection) {
 
 func (c *Client) maintenanceLoop() {
 	defer c.wg.Done()
-	interval := c.config.HealthCheckInterval
-	if interval <= 0 {
-		interval = 5 * time.Minute
-	}
-	ticker := time.NewTicker(interval)
+	ticker := time.NewTicker(c.healthCheckInterval())
 	defer ticker.Stop()
 
 	// 不再清理空闲连接，只有系统关闭时才清理
@@ -220,12 +245,24 @@ func (c *Client) maintenanceLoop() {
 			if cleanedBlacklist > 0 {
 				projlogger.Info("从黑名单中移除了 %d 个过期的IP", cleanedBlacklist)
 			}
+
+			c.rateLimiter.Cleanup()
+		case <-c.limitsChanged:
+			ticker.Reset(c.healthCheckInterval())
 		case <-c.stopChan:
 			return
 		}
 	}
 }
 
+// healthCheckInterval 返回健康检查间隔（未配置时为 5 分钟）
+func (c *Client) healthCheckInterval() time.Duration {
+	if interval := c.config.limits().HealthCheckInterval; interval > 0 {
+		return interval
+	}
+	return 5 * time.Minute
+}
+
 // healthCheck 遍历所有白名单中的连接，检查其健康状况。
 func (c *Client) healthCheck() {
 	allConns := c.connManager.GetAllConnections()
@@ -236,8 +273,8 @@ func (c *Client) healthCheck() {
 	// 使用信号量限制并发数，避免创建过多goroutine
 	// 默认最大并发数为10，可以根据配置调整
 	maxConcurrency := 10
-	if c.config.MaxConcurrentPreWarms > 0 && c.config.MaxConcurrentPreWarms < maxConcurrency {
-		maxConcurrency = c.config.MaxConcurrentPreWarms
+	if limit := c.config.limits().MaxConcurrentPreWarms; limit > 0 && limit < maxConcurrency {
+		maxConcurrency = limit
 	}
 
 	semaphore := make(chan struct{}, maxConcurrency)
@@ -290,10 +327,7 @@ func (c *Client) healthCheck() {
 			}
 
 			// 使用配置的健康检查路径，如果没有配置则使用默认路径
-			healthCheckPath := c.config.HealthCheckPath
-			if healthCheckPath == "" {
-				healthCheckPath = DefaultHealthCheckPath // 使用默认健康检查路径
-			}
+			healthCheckPath := c.config.healthCheckPathFor(targetHost)
 
 			// 使用 GET 方法进行健康检查（因为需要验证返回 200）
 			req, err := http.NewRequest("GET", "https://"+targetHost+healthCheckPath, nil)
@@ -374,10 +408,7 @@ func (c *Client) quickHealthCheck(conn *UTLSConnection) {
 		}()
 
 		// 使用配置的健康检查路径
-		healthCheckPath := c.config.HealthCheckPath
-		if healthCheckPath == "" {
-			healthCheckPath = DefaultHealthCheckPath // 使用默认健康检查路径
-		}
+		healthCheckPath := c.config.healthCheckPathFor(targetHost)
 
 		// 使用 GET 方法进行健康检查
 		req, err := http.NewRequest("GET", "https://"+targetHost+healthCheckPath, nil)
@@ -427,14 +458,214 @@ func (c *Client) GetMetrics() MetricsSnapshot {
 	if c.metrics == nil {
 		return MetricsSnapshot{}
 	}
-	return c.metrics.GetSnapshot()
+	snapshot := c.metrics.GetSnapshot()
+	snapshot.RateLimits = c.rateLimiter.Limits()
+
+	// 连接数与黑名单大小按当前状态统计
+	snapshot.ActiveConnections, snapshot.HealthyConnections, snapshot.UnhealthyConnections = 0, 0, 0
+	for _, stats := range c.HostStats() {
+		snapshot.ActiveConnections += int64(stats.Total)
+		snapshot.HealthyConnections += int64(stats.Healthy)
+		snapshot.InUseConnections += int64(stats.InUse)
+	}
+	snapshot.UnhealthyConnections = snapshot.ActiveConnections - snapshot.HealthyConnections
+	snapshot.BlacklistedIPs = int64(len(c.blacklist.GetBlockedIPs()))
+	return snapshot
+}
+
+// HostStats 连接池中单个主机的连接统计
+type HostStats struct {
+	Total   int `json:"total"`   // 连接总数
+	Healthy int `json:"healthy"` // 健康连接数
+	InUse   int `json:"in_use"`  // 正在使用的连接数
+}
+
+// HostStats 按主机统计连接池中的连接
+func (c *Client) HostStats() map[string]HostStats {
+	result := make(map[string]HostStats)
+	for _, conn := range c.connManager.GetAllConnections() {
+		conn.mu.Lock()
+		host, healthy, inUse := conn.targetHost, conn.healthy, conn.inUse
+		conn.mu.Unlock()
+
+		stats := result[host]
+		stats.Total++
+		if healthy {
+			stats.Healthy++
+		}
+		if inUse {
+			stats.InUse++
+		}
+		result[host] = stats
+	}
+	return result
+}
+
+// SetRateLimits 运行时修改热连接池的限速配置
+func (c *Client) SetRateLimits(limits RateLimits) {
+	c.rateLimiter.SetLimits(limits)
+	projlogger.Info("热连接池限速已更新: 每主机 %.1f/s (突发 %d), 每远程IP %.1f/s (突发 %d), 每本地IP %.1f/s (突发 %d)",
+		limits.PerHost.Rate, limits.PerHost.Burst, limits.PerRemoteIP.Rate, limits.PerRemoteIP.Burst,
+		limits.PerLocalIP.Rate, limits.PerLocalIP.Burst)
+}
+
+// SetPoolLimits 运行时修改热连接池的连接数、并发与超时配置
+// 时间间隔 <=0 的字段保持原值；新配置对之后的预热、建立连接与健康检查生效，已建立的连接不受影响
+func (c *Client) SetPoolLimits(limits PoolLimits) {
+	c.config.mu.Lock()
+	c.config.MaxConnsPerHost = limits.MaxConnsPerHost
+	c.config.MaxConcurrentPreWarms = limits.MaxConcurrentPreWarms
+	if limits.PreWarmInterval > 0 {
+		c.config.PreWarmInterval = limits.PreWarmInterval
+	}
+	if limits.ConnTimeout > 0 {
+		c.config.ConnTimeout = limits.ConnTimeout
+	}
+	if limits.IdleTimeout > 0 {
+		c.config.IdleTimeout = limits.IdleTimeout
+	}
+	if limits.HealthCheckInterval > 0 {
+		c.config.HealthCheckInterval = limits.HealthCheckInterval
+	}
+	if limits.IPBlacklistTimeout > 0 {
+		c.config.IPBlacklistTimeout = limits.IPBlacklistTimeout
+	}
+	c.config.mu.Unlock()
+
+	c.blacklist.SetTimeout(limits.IPBlacklistTimeout)
+	c.poolManager.notifyLimitsChanged()
+	select {
+	case c.limitsChanged <- struct{}{}:
+	default:
+	}
+
+	current := c.config.limits()
+	projlogger.Info("热连接池配置已更新: 每主机最大连接 %d, 最大并发预热 %d, 预热间隔 %v, 连接超时 %v, 空闲超时 %v, 健康检查间隔 %v, 黑名单超时 %v",
+		current.MaxConnsPerHost, current.MaxConcurrentPreWarms, current.PreWarmInterval, current.ConnTimeout,
+		current.IdleTimeout, current.HealthCheckInterval, current.IPBlacklistTimeout)
+}
+
+// ConnectionInfo 热连接池中单个连接的状态
+type ConnectionInfo struct {
+	IP           string    `json:"ip"`            // 远程 IP
+	Host         string    `json:"host"`          // 目标主机名
+	LocalIP      string    `json:"local_ip"`      // 本地源 IP（未使用本地 IP 池时为空）
+	Healthy      bool      `json:"healthy"`       // 是否健康
+	InUse        bool      `json:"in_use"`        // 是否正在使用
+	RequestCount int64     `json:"request_count"` // 累计请求数
+	ErrorCount   int64     `json:"error_count"`   // 累计错误数
+	Created      time.Time `json:"created"`       // 建立时间
+	LastUsed     time.Time `json:"last_used"`     // 最后使用时间
+}
+
+// Connections 返回热连接池中所有连接的状态（按主机名、IP 排序）
+func (c *Client) Connections() []ConnectionInfo {
+	conns := c.connManager.GetAllConnections()
+	result := make([]ConnectionInfo, 0, len(conns))
+	for _, conn := range conns {
+		conn.mu.Lock()
+		info := ConnectionInfo{
+			IP:       conn.targetIP,
+			Host:     conn.targetHost,
+			LocalIP:  conn.localIP,
+			Healthy:  conn.healthy,
+			InUse:    conn.inUse,
+			Created:  conn.created,
+			LastUsed: conn.lastUsed,
+		}
+		conn.mu.Unlock()
+		info.RequestCount = atomic.LoadInt64(&conn.requestCount)
+		info.ErrorCount = atomic.LoadInt64(&conn.errorCount)
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Host != result[j].Host {
+			return result[i].Host < result[j].Host
+		}
+		return result[i].IP < result[j].IP
+	})
+	return result
+}
+
+// WhitelistedIPs 返回白名单（热连接池中已建立连接）的远程 IP 列表（已排序）
+func (c *Client) WhitelistedIPs() []string {
+	conns := c.connManager.GetAllConnections()
+	ips := make([]string, 0, len(conns))
+	for _, conn := range conns {
+		ips = append(ips, conn.TargetIP())
+	}
+	sort.Strings(ips)
+	return ips
+}
+
+// BlacklistedIPs 返回当前黑名单中的远程 IP 列表（已排序）
+func (c *Client) BlacklistedIPs() []string {
+	ips := c.blacklist.GetBlockedIPs()
+	sort.Strings(ips)
+	return ips
+}
+
+// BlockIP 手动将远程 IP 加入黑名单，并关闭到该 IP 的连接（超过黑名单超时时间后自动恢复）
+func (c *Client) BlockIP(ip string) {
+	c.blacklist.Add(ip)
+	c.connManager.RemoveConnection(ip)
+	projlogger.Info("已手动将IP加入黑名单: %s", ip)
+}
+
+// UnblockIP 手动将远程 IP 移出黑名单（不会立即建立连接，可通过 WarmIP 加入白名单）
+func (c *Client) UnblockIP(ip string) {
+	c.blacklist.Remove(ip)
+	projlogger.Info("已手动将IP移出黑名单: %s", ip)
+}
+
+// WarmIP 手动将远程 IP 加入白名单：移出黑名单，并为远程 IP 池中解析到该 IP 的主机建立并验证连接
+// IP 已在白名单中时直接返回
+func (c *Client) WarmIP(ip string) error {
+	if c.connManager.GetConnection(ip) != nil {
+		return nil
+	}
+
+	var hosts []string
+	for host, ips := range c.poolManager.remotePool.GetAllDomainIPs() {
+		if slices.Contains(ips, ip) {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return fmt.Errorf("%w: IP %s 不在任何主机的远程 IP 池中", ErrInvalidConfig, ip)
+	}
+	sort.Strings(hosts)
+
+	c.blacklist.Remove(ip)
+	for _, host := range hosts {
+		c.poolManager.preWarmConnectionsBatch(host, []string{ip}, make(chan struct{}, 1))
+		// 每个 IP 只保留一个连接
+		if c.connManager.GetConnection(ip) != nil {
+			projlogger.Info("已手动将IP加入白名单: %s (%s)", ip, host)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: 到 IP %s 的连接建立或验证失败，或主机连接数已达上限", ErrNoAvailableConnection, ip)
+}
+
+// RemoveConnection 手动将远程 IP 移出白名单并关闭连接（远程 IP 池重新预热时可能再次加入）
+func (c *Client) RemoveConnection(ip string) {
+	c.connManager.RemoveConnection(ip)
+	projlogger.Info("已手动将IP移出白名单: %s", ip)
+}
+
+// Config 返回连接池配置的副本
+func (c *Client) Config() PoolConfig {
+	c.config.mu.RLock()
+	defer c.config.mu.RUnlock()
+	return *c.config
 }
 
 // GetMetricsJSON 获取JSON格式的指标（便于日志或API输出）
 func (c *Client) GetMetricsJSON() string {
 	snapshot := c.GetMetrics()
 	return fmt.Sprintf(
-		"连接池指标 - 活跃: %d (健康: %d/不健康: %d), 总请求: %d, 成功率: %.1f%%, 健康率: %.1f%%, 拉黑IP: %d",
+		"连接池指标 - 活跃: %d (健康: %d/不健康: %d), 总请求: %d, 成功率: %.1f%%, 健康率: %.1f%%, 拉黑IP: %d, 限速拒绝: %d",
 		snapshot.ActiveConnections,
 		snapshot.HealthyConnections,
 		snapshot.UnhealthyConnections,
@@ -442,5 +673,6 @@ func (c *Client) GetMetricsJSON() string {
 		snapshot.SuccessRate(),
 		snapshot.HealthRate(),
 		snapshot.BlacklistedIPs,
+		snapshot.RateLimitedRequests,
 	)
 }
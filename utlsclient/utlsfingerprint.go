@@ -21,6 +21,38 @@ type Profile struct { // 定义配置文件结构体
 	Version     string             // 版本信息
 }
 
+// 各浏览器发送 HTTP/1.1 请求头的顺序（规范化名称）
+var (
+	chromeHeaderOrder = []string{
+		"Host", "Connection", "Content-Length", "Cache-Control", "User-Agent", "Content-Type", "Accept",
+		"Origin", "Sec-Purpose", "Sec-Fetch-Site", "Sec-Fetch-Mode", "Sec-Fetch-Dest", "Referer",
+		"Accept-Encoding", "Accept-Language", "Cookie", "If-None-Match", "If-Modified-Since", "Range", "If-Range", "Priority",
+	}
+	firefoxHeaderOrder = []string{
+		"Host", "User-Agent", "Accept", "Accept-Language", "Accept-Encoding", "Content-Type", "Content-Length",
+		"Origin", "Connection", "Referer", "Cookie", "Sec-Purpose", "Sec-Fetch-Dest", "Sec-Fetch-Mode", "Sec-Fetch-Site",
+		"If-Modified-Since", "If-None-Match", "Range", "If-Range", "Priority",
+	}
+	safariHeaderOrder = []string{
+		"Host", "Content-Type", "Origin", "Accept-Encoding", "Cookie", "Connection", "Accept", "User-Agent",
+		"Content-Length", "Referer", "Accept-Language", "Sec-Purpose", "Sec-Fetch-Dest", "Sec-Fetch-Site", "Sec-Fetch-Mode",
+		"If-None-Match", "If-Modified-Since", "Range", "If-Range", "Priority",
+	}
+)
+
+// HeaderOrder 返回指纹对应浏览器发送请求头的顺序，未列出的请求头按名称排序写在最后
+// 只用于 HTTP/1.1 请求（HTTP/2 请求头由 http2 库编码，顺序无法控制）
+func (p Profile) HeaderOrder() []string {
+	switch p.Browser {
+	case "Firefox":
+		return firefoxHeaderOrder
+	case "Safari":
+		return safariHeaderOrder
+	default:
+		return chromeHeaderOrder
+	}
+}
+
 // Library 定义了指纹库结构体
 type Library struct { // 定义指纹库结构体
 	profiles []Profile  // 配置文件列表
@@ -5,8 +5,11 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -99,6 +102,22 @@ func (c *UTLSConnection) TryAcquire() bool {
 	return true
 }
 
+// release 撤销 TryAcquire 的获取（连接尚未用于请求，不触发 ReleaseConnection 的本地 IP 释放与健康检查）
+func (c *UTLSConnection) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inUse = false
+}
+
+// skipSessionKey 请求上下文键：标记请求不携带 SessionId Cookie
+type skipSessionKey struct{}
+
+// WithoutSessionID 返回不携带 SessionId Cookie 的请求副本。
+// RockTree 等无需会话的接口与 flatfile 共用连接时使用，避免带上 geauth 会话。
+func WithoutSessionID(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), skipSessionKey{}, true))
+}
+
 // SetSessionID 设置连接的 Session ID。
 func (c *UTLSConnection) SetSessionID(sessionID string) {
 	c.mu.Lock()
@@ -161,6 +180,10 @@ func (c *UTLSConnection) Close() error {
 func (c *UTLSConnection) RoundTrip(req *http.Request) (*http.Response, error) {
 	atomic.AddInt64(&c.requestCount, 1)
 
+	if err := req.Context().Err(); err != nil {
+		return nil, fmt.Errorf("请求已取消: %w", err)
+	}
+
 	// 安全地读取共享字段，并检查连接健康状态
 	c.mu.Lock()
 	if !c.healthy {
@@ -189,7 +212,7 @@ func (c *UTLSConnection) RoundTrip(req *http.Request) (*http.Response, error) {
 		// 设置 Accept 头，与 curl 测试一致
 		req.Header.Set("Accept", "*/*")
 	}
-	if sessionID != "" {
+	if sessionID != "" && req.Context().Value(skipSessionKey{}) == nil {
 		// projlogger.Debug("设置Cookie: %s", sessionID)
 		req.Header.Set("Cookie", fmt.Sprintf("SessionId=%s;State=1", sessionID))
 	}
@@ -242,18 +265,37 @@ func (c *UTLSConnection) RoundTrip(req *http.Request) (*http.Response, error) {
 }
 
 func (c *UTLSConnection) roundTripH1(req *http.Request) (*http.Response, error) {
-	err := req.Write(c.tlsConn)
+	c.mu.Lock()
+	headerOrder := c.fingerprint.HeaderOrder()
+	c.mu.Unlock()
+
+	// 请求上下文取消时让阻塞的读写立即返回（直到响应体关闭前都有效）
+	ctx := req.Context()
+	stop := context.AfterFunc(ctx, func() {
+		c.tlsConn.SetDeadline(time.Unix(1, 0))
+	})
+
+	err := writeRequestH1(c.tlsConn, req, headerOrder)
 	if err != nil {
+		stop()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, c.abortH1(ctxErr)
+		}
 		// 网络错误不标记为不健康，允许重试（只有403才标记为不健康）
 		// 连接断开是正常的，下次使用时会自动恢复
-		return nil, err
+		return nil, WrapTransportError(err)
 	}
 	resp, err := http.ReadResponse(bufio.NewReader(c.tlsConn), req)
 	if err != nil {
+		stop()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, c.abortH1(ctxErr)
+		}
 		// 网络错误不标记为不健康，允许重试（只有403才标记为不健康）
 		// 连接断开是正常的，下次使用时会自动恢复
-		return nil, err
+		return nil, WrapTransportError(err)
 	}
+	resp.Body = &h1Body{ReadCloser: resp.Body, conn: c, ctx: ctx, stop: stop}
 
 	// 检测403错误，将IP加入黑名单（只有403才标记为不健康）
 	if resp.StatusCode == http.StatusForbidden {
@@ -264,6 +306,121 @@ func (c *UTLSConnection) roundTripH1(req *http.Request) (*http.Response, error)
 	return resp, nil
 }
 
+// headerValueReplacer 将请求头值中的换行替换为空格（与 net/http 一致，防止请求头注入）
+var headerValueReplacer = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// writeRequestH1 按指纹的请求头顺序写出 HTTP/1.1 请求（net/http 的 Request.Write 按名称排序写出请求头）
+func writeRequestH1(w io.Writer, req *http.Request, order []string) error {
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	// 长度未知的请求体需要分块编码，交给标准库处理
+	if hasBody && req.ContentLength <= 0 {
+		return req.Write(w)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.Header.Get("Host")
+	}
+	if host == "" {
+		host = req.URL.Host
+	}
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	header := req.Header.Clone()
+	header.Del("Transfer-Encoding")
+	header.Set("Host", host)
+	if hasBody {
+		header.Set("Content-Length", strconv.FormatInt(req.ContentLength, 10))
+	} else if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
+		header.Set("Content-Length", "0")
+	} else {
+		header.Del("Content-Length")
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s %s HTTP/1.1\r\n", method, req.URL.RequestURI())
+	writeHeader := func(key string) {
+		for _, value := range header[key] {
+			fmt.Fprintf(bw, "%s: %s\r\n", key, headerValueReplacer.Replace(value))
+		}
+		delete(header, key)
+	}
+	for _, key := range order {
+		writeHeader(key)
+	}
+	rest := make([]string, 0, len(header))
+	for key := range header {
+		rest = append(rest, key)
+	}
+	sort.Strings(rest)
+	for _, key := range rest {
+		writeHeader(key)
+	}
+	bw.WriteString("\r\n")
+
+	if hasBody {
+		_, err := io.Copy(bw, req.Body)
+		req.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// abortH1 HTTP/1.1 请求被取消后连接上可能残留未读完的响应，不能再复用，标记为不健康等待清理
+func (c *UTLSConnection) abortH1(ctxErr error) error {
+	c.markAsUnhealthy()
+	projlogger.Debug("HTTP/1.1 请求已取消，连接 %s 标记为不健康: %v", c.targetIP, ctxErr)
+	return fmt.Errorf("请求已取消: %w", ctxErr)
+}
+
+// h1Body HTTP/1.1 响应体（读取期间请求上下文取消时返回上下文错误，关闭时停止监听上下文）
+// 关闭时丢弃少量未读数据，剩余过多时连接上会残留数据，不能再复用
+type h1Body struct {
+	io.ReadCloser
+	conn    *UTLSConnection
+	ctx     context.Context
+	stop    func() bool
+	aborted bool
+	eof     bool
+}
+
+func (b *h1Body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.eof = true
+	}
+	if err != nil && err != io.EOF {
+		if ctxErr := b.ctx.Err(); ctxErr != nil {
+			if !b.aborted {
+				b.aborted = true
+				return n, b.conn.abortH1(ctxErr)
+			}
+			return n, ctxErr
+		}
+	}
+	return n, err
+}
+
+// h1DrainLimit 关闭 HTTP/1.1 响应体时最多丢弃的未读数据量
+const h1DrainLimit = 256 << 10
+
+func (b *h1Body) Close() error {
+	if !b.eof && !b.aborted {
+		if _, err := io.CopyN(io.Discard, b, h1DrainLimit); err != io.EOF && !b.aborted {
+			b.aborted = true
+			b.conn.markAsUnhealthy()
+			projlogger.Debug("HTTP/1.1 响应体未读完即关闭，连接 %s 标记为不健康", b.conn.targetIP)
+		}
+	}
+	b.stop()
+	return b.ReadCloser.Close()
+}
+
 func (c *UTLSConnection) roundTripH2(req *http.Request) (*http.Response, error) {
 	// 先检查连接是否健康
 	c.mu.Lock()
@@ -292,11 +449,7 @@ func (c *UTLSConnection) roundTripH2(req *http.Request) (*http.Response, error)
 		if err != nil {
 			c.h2Mu.Unlock()
 			// 创建 HTTP/2 连接失败，检查是否是连接已关闭的错误
-			errStr := err.Error()
-			if strings.Contains(errStr, "use of closed network connection") ||
-				strings.Contains(errStr, "connection closed") ||
-				strings.Contains(errStr, "broken pipe") ||
-				strings.Contains(errStr, "EOF") {
+			if isConnectionClosed(err) {
 				// 底层连接已关闭，触发快速健康检查恢复连接（不标记为不健康）
 				// 连接保持健康状态，只有403才标记为不健康
 				// 检查是否已经在恢复中，避免重复触发导致死循环
@@ -312,7 +465,7 @@ func (c *UTLSConnection) roundTripH2(req *http.Request) (*http.Response, error)
 					go c.onQuickHealthCheck(c)
 				}
 			}
-			return nil, fmt.Errorf("创建 HTTP/2 连接失败: %w", err)
+			return nil, fmt.Errorf("创建 HTTP/2 连接失败: %w", WrapTransportError(err))
 		}
 		c.h2ClientConn = clientConn
 		projlogger.Debug("HTTP/2 连接已创建: %s", c.targetIP)
@@ -322,6 +475,10 @@ func (c *UTLSConnection) roundTripH2(req *http.Request) (*http.Response, error)
 
 	resp, err := h2Conn.RoundTrip(req)
 	if err != nil {
+		// 请求上下文取消只重置该请求的流，HTTP/2 连接仍可继续使用
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, fmt.Errorf("请求已取消: %w", ctxErr)
+		}
 		// 请求失败，关闭 HTTP/2 连接，避免 readLoop goroutine 泄漏
 		c.h2Mu.Lock()
 		if c.h2ClientConn == h2Conn {
@@ -331,11 +488,7 @@ func (c *UTLSConnection) roundTripH2(req *http.Request) (*http.Response, error)
 		c.h2Mu.Unlock()
 
 		// 检查是否是连接关闭的错误，如果是则触发快速恢复
-		errStr := err.Error()
-		if strings.Contains(errStr, "use of closed network connection") ||
-			strings.Contains(errStr, "connection closed") ||
-			strings.Contains(errStr, "broken pipe") ||
-			strings.Contains(errStr, "EOF") {
+		if isConnectionClosed(err) {
 			// 底层连接已关闭，触发快速健康检查恢复连接（不标记为不健康）
 			// 连接保持健康状态，只有403才标记为不健康
 			// 检查是否已经在恢复中，避免重复触发导致死循环
@@ -352,7 +505,7 @@ func (c *UTLSConnection) roundTripH2(req *http.Request) (*http.Response, error)
 			}
 		}
 		// 注意：只有403才标记为不健康，其他错误（包括连接关闭）不标记
-		return nil, err
+		return nil, WrapTransportError(err)
 	}
 
 	// 检测403错误，将IP加入黑名单
@@ -408,7 +561,7 @@ func establishConnection(ip, domain string, config *PoolConfig, on403 func(strin
 	// 创建 Dialer，支持绑定本地 IP 地址
 	// 设置 TCP keep-alive 以保持长连接
 	dialer := &net.Dialer{
-		Timeout:   config.ConnTimeout,
+		Timeout:   config.limits().ConnTimeout,
 		KeepAlive: 30 * time.Second, // 每30秒发送一次keep-alive探测包
 	}
 
@@ -601,7 +754,7 @@ func establishConnection(ip, domain string, config *PoolConfig, on403 func(strin
 	}, fingerprint.HelloID)
 
 	//projlogger.Debug("开始TLS握手: %s -> %s", domain, ip)
-	ctx, cancel := context.WithTimeout(context.Background(), config.ConnTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), config.limits().ConnTimeout)
 	defer cancel()
 	if err := uconn.HandshakeContext(ctx); err != nil {
 		projlogger.Debug("TLS握手失败: %s -> %s, 错误: %v", domain, ip, err)
@@ -626,10 +779,7 @@ func establishConnection(ip, domain string, config *PoolConfig, on403 func(strin
 	}
 
 	// TLS握手成功后进行健康检查（使用 HealthCheckPath，GET 方法）
-	healthCheckPath := config.HealthCheckPath
-	if healthCheckPath == "" {
-		healthCheckPath = DefaultHealthCheckPath // 使用默认健康检查路径
-	}
+	healthCheckPath := config.healthCheckPathFor(domain)
 
 	healthCheckURL := "https://" + domain + healthCheckPath
 	healthCheckReq, err := http.NewRequest("GET", healthCheckURL, nil)
@@ -693,8 +843,56 @@ type PoolConfig struct {
 	SessionIdPath          string        `mapstructure:"SessionIdPath"`          // 获取SessionID的路径（POST方法）
 	SessionIdBody          []byte        `mapstructure:"SessionIdBody"`          // 获取SessionID的请求体（POST方法使用）
 
+	// HostHealthCheckPaths 按主机名覆盖 HealthCheckPath（不同上游站点的健康检查路径不同）
+	HostHealthCheckPaths map[string]string `mapstructure:"HostHealthCheckPaths"`
+
+	// RateLimits 按目标主机、远程 IP、本地源 IP 的令牌桶限速（Rate<=0 表示不限速），运行时可通过 Client.SetRateLimits 修改
+	RateLimits RateLimits `mapstructure:"RateLimits"`
+
 	// LocalIPPool 本地 IP 地址池，用于绑定本地源 IP 地址
 	// 如果设置了此字段，建立连接时会从池中获取一个本地 IP 并绑定
 	// 支持 IPv4 和 IPv6 地址池
 	LocalIPPool localippool.IPPool `mapstructure:"-"`
+
+	// mu 保护运行时可通过 Client.SetPoolLimits 修改的字段（NewClient 时创建）
+	mu *sync.RWMutex
+}
+
+// healthCheckPathFor 返回主机的健康检查路径（HostHealthCheckPaths > HealthCheckPath > 默认路径）
+func (c *PoolConfig) healthCheckPathFor(host string) string {
+	if path := c.HostHealthCheckPaths[host]; path != "" {
+		return path
+	}
+	if c.HealthCheckPath != "" {
+		return c.HealthCheckPath
+	}
+	return DefaultHealthCheckPath
+}
+
+// PoolLimits 热连接池中可在运行时修改的连接数、并发与超时配置
+type PoolLimits struct {
+	MaxConnsPerHost       int
+	MaxConcurrentPreWarms int
+	PreWarmInterval       time.Duration
+	ConnTimeout           time.Duration
+	IdleTimeout           time.Duration
+	HealthCheckInterval   time.Duration
+	IPBlacklistTimeout    time.Duration
+}
+
+// limits 读取当前的 PoolLimits（与 Client.SetPoolLimits 并发安全）
+func (c *PoolConfig) limits() PoolLimits {
+	if c.mu != nil {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+	}
+	return PoolLimits{
+		MaxConnsPerHost:       c.MaxConnsPerHost,
+		MaxConcurrentPreWarms: c.MaxConcurrentPreWarms,
+		PreWarmInterval:       c.PreWarmInterval,
+		ConnTimeout:           c.ConnTimeout,
+		IdleTimeout:           c.IdleTimeout,
+		HealthCheckInterval:   c.HealthCheckInterval,
+		IPBlacklistTimeout:    c.IPBlacklistTimeout,
+	}
 }
@@ -19,7 +19,9 @@ const state = {
         settings: null
     },
     poolStats: null,
-    poolTimer: null
+    poolTimer: null,
+    eventSource: null,
+    ipRefreshTimer: null
 };
 
 // ===== 初始化 =====
@@ -30,8 +32,10 @@ document.addEventListener('DOMContentLoaded', () => {
     initTaskTable();
     initUserMenu();
     initEditUserModal();
+    initTokenModal();
     initIPManagement();
     initPoolStats();
+    initEventStream();
     if (typeof window.initMonitor === 'function') {
         window.initMonitor();
     }
@@ -160,7 +164,7 @@ async function handleCreateTask() {
     console.log('创建任务:', formData);
 
     try {
-        const response = await fetch('/api/tasks/create', {
+        const response = await apiFetch('/api/tasks/create', {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/json'
@@ -194,8 +198,64 @@ async function handleCreateTask() {
     }
 }
 
+// ===== 管理 API 令牌 =====
+// 服务器启用认证时，管理 API 需要携带 admin 角色的令牌（Authorization: Bearer <令牌>）
+const ADMIN_TOKEN_KEY = 'adminToken';
+
+function getAdminToken() {
+    return localStorage.getItem(ADMIN_TOKEN_KEY) || '';
+}
+
+// 发送管理 API 请求，自动携带令牌；返回 401 时提示输入令牌
+async function apiFetch(url, options = {}) {
+    const headers = new Headers(options.headers || {});
+    const token = getAdminToken();
+    if (token) {
+        headers.set('Authorization', 'Bearer ' + token);
+    }
+    const response = await fetch(url, { ...options, headers });
+    if (response.status === 401) {
+        openTokenModal();
+    }
+    return response;
+}
+
+function openTokenModal() {
+    const modal = document.getElementById('tokenModal');
+    if (modal && !modal.classList.contains('active')) {
+        document.getElementById('adminTokenInput').value = getAdminToken();
+        modal.classList.add('active');
+    }
+}
+
+function closeTokenModal() {
+    document.getElementById('tokenModal').classList.remove('active');
+}
+
+function initTokenModal() {
+    const form = document.getElementById('tokenForm');
+    if (!form) return;
+    form.addEventListener('submit', (e) => {
+        e.preventDefault();
+        const token = document.getElementById('adminTokenInput').value.trim();
+        if (token) {
+            localStorage.setItem(ADMIN_TOKEN_KEY, token);
+        } else {
+            localStorage.removeItem(ADMIN_TOKEN_KEY);
+        }
+        closeTokenModal();
+        showNotification('API 令牌已保存', 'success');
+        loadTasksFromServer();
+        refreshPoolStats();
+        initEventStream();
+    });
+}
+
+window.openTokenModal = openTokenModal;
+window.closeTokenModal = closeTokenModal;
+
 async function fetchJSON(url, options = {}) {
-    const response = await fetch(url, options);
+    const response = await apiFetch(url, options);
     let result = null;
     try {
         result = await response.json();
@@ -317,7 +377,7 @@ async function initTaskTable() {
 
 async function loadTasksFromServer() {
     try {
-        const response = await fetch('/api/tasks');
+        const response = await apiFetch('/api/tasks');
         const result = await response.json();
         if (result.success && Array.isArray(result.data)) {
             state.tasks = result.data.map(normalizeTaskFromAPI);
@@ -927,6 +987,84 @@ function initPoolStats() {
     state.poolTimer = setInterval(refreshPoolStats, 5000);
 }
 
+// ===== 实时事件推送（SSE） =====
+// 连接成功后停止定时轮询，连接断开时恢复轮询（EventSource 会自动重连）
+// EventSource 无法携带 Authorization 请求头，先由服务器把令牌写入只用于事件流的 Cookie
+async function initEventStream() {
+    if (typeof EventSource === 'undefined') return;
+
+    if (state.eventSource) {
+        state.eventSource.close();
+        state.eventSource = null;
+    }
+    if (getAdminToken()) {
+        try {
+            await apiFetch('/api/events/session', { method: 'POST' });
+        } catch (err) {
+            console.error('设置事件流令牌失败:', err);
+        }
+    }
+
+    const source = new EventSource('/api/events');
+    state.eventSource = source;
+
+    source.onopen = () => {
+        if (state.taskRefreshTimer) {
+            clearInterval(state.taskRefreshTimer);
+            state.taskRefreshTimer = null;
+        }
+        if (state.poolTimer) {
+            clearInterval(state.poolTimer);
+            state.poolTimer = null;
+        }
+    };
+
+    source.onerror = () => {
+        if (!state.taskRefreshTimer) {
+            state.taskRefreshTimer = setInterval(loadTasksFromServer, 10000);
+        }
+        if (!state.poolTimer) {
+            state.poolTimer = setInterval(refreshPoolStats, 5000);
+        }
+    };
+
+    source.addEventListener('metrics', (e) => {
+        const event = JSON.parse(e.data);
+        if (event.data && event.data.pool) {
+            state.poolStats = event.data.pool;
+            updatePoolStatsCard(event.data.pool);
+        }
+    });
+
+    source.addEventListener('job', (e) => {
+        const task = normalizeTaskFromAPI(JSON.parse(e.data).data);
+        const index = state.tasks.findIndex(t => t.id === task.id);
+        if (index >= 0) {
+            state.tasks[index] = task;
+        } else {
+            state.tasks.unshift(task);
+        }
+        updateTaskTable();
+    });
+
+    source.addEventListener('pool', () => {
+        // 同一秒内的多个变化合并为一次刷新
+        if (state.ipRefreshTimer) return;
+        state.ipRefreshTimer = setTimeout(() => {
+            state.ipRefreshTimer = null;
+            refreshWhitelist();
+            refreshBlacklist();
+        }, 1000);
+    });
+
+    source.addEventListener('node', (e) => {
+        const node = JSON.parse(e.data).data;
+        const labels = { joined: '加入集群', offline: '已离线', online: '已恢复' };
+        const type = node.action === 'offline' ? 'error' : 'info';
+        showNotification(`节点 ${node.address} ${labels[node.action] || node.action}`, type);
+    });
+}
+
 async function refreshPoolStats() {
     try {
         const data = await fetchJSON('/api/pool/stats');